@@ -0,0 +1,154 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package wayback // import "github.com/wabarc/wayback"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/metrics"
+)
+
+// BreakerState represents the state of the circuit breaker of a slot.
+type BreakerState uint8
+
+const (
+	BreakerClosed   BreakerState = iota // BreakerClosed represents the slot is available
+	BreakerHalfOpen                     // BreakerHalfOpen represents the slot is on probation
+	BreakerOpen                         // BreakerOpen represents the slot is skipped until cooldown
+)
+
+// String returns the state as a string.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return config.UNKNOWN
+	}
+}
+
+var (
+	breakers  = make(map[string]*breaker)
+	breakerMu sync.Mutex
+)
+
+// breaker is a circuit breaker that skips a slot temporarily after
+// repeated failures.
+type breaker struct {
+	mu sync.Mutex
+
+	slot     string
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func breakerOf(slot string) *breaker {
+	breakerMu.Lock()
+	defer breakerMu.Unlock()
+
+	b, ok := breakers[slot]
+	if !ok {
+		b = &breaker{slot: slot}
+		breakers[slot] = b
+	}
+	return b
+}
+
+// allow reports whether the slot is allowed to archive. An open breaker
+// turns into half-open once the cooldown elapsed, which allows a single
+// attempt to probe whether the slot recovered.
+func (b *breaker) allow(policy config.SlotPolicy) bool {
+	if policy.BreakerThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < policy.BreakerCooldown {
+			return false
+		}
+		b.transit(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// done records the result of an archiving.
+func (b *breaker) done(policy config.SlotPolicy, ok bool) {
+	if policy.BreakerThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if ok {
+		b.failures = 0
+		b.transit(BreakerClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= policy.BreakerThreshold {
+		b.openedAt = time.Now()
+		b.transit(BreakerOpen)
+	}
+}
+
+func (b *breaker) transit(state BreakerState) {
+	if b.state != state {
+		logger.Warn("circuit breaker of slot %s turns %s from %s", b.slot, state, b.state)
+	}
+	b.state = state
+	metrics.SetBreaker(b.slot, int(state))
+}
+
+// Breaker holds the circuit breaker state of a slot.
+type Breaker struct {
+	Slot     string
+	State    BreakerState
+	Failures int
+}
+
+// Breakers returns the circuit breaker states of slots that have archived,
+// sorted by slot name.
+func Breakers() []Breaker {
+	breakerMu.Lock()
+	defer breakerMu.Unlock()
+
+	list := make([]Breaker, 0, len(breakers))
+	for slot, b := range breakers {
+		b.mu.Lock()
+		list = append(list, Breaker{Slot: slot, State: b.state, Failures: b.failures})
+		b.mu.Unlock()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slot < list[j].Slot
+	})
+
+	return list
+}
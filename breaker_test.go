@@ -0,0 +1,58 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package wayback // import "github.com/wabarc/wayback"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestBreaker(t *testing.T) {
+	policy := config.SlotPolicy{BreakerThreshold: 2, BreakerCooldown: 50 * time.Millisecond}
+	b := &breaker{slot: "breaker-test"}
+
+	for i := 0; i < policy.BreakerThreshold; i++ {
+		if !b.allow(policy) {
+			t.Fatalf(`Unexpected breaker state, got %s after %d failures`, b.state, i)
+		}
+		b.done(policy, false)
+	}
+	if b.state != BreakerOpen {
+		t.Fatalf(`Unexpected breaker state, got %s instead of %s`, b.state, BreakerOpen)
+	}
+	if b.allow(policy) {
+		t.Fatalf(`Unexpected allowed when breaker is open`)
+	}
+
+	time.Sleep(policy.BreakerCooldown)
+	if !b.allow(policy) {
+		t.Fatalf(`Unexpected disallowed after cooldown`)
+	}
+	if b.state != BreakerHalfOpen {
+		t.Fatalf(`Unexpected breaker state, got %s instead of %s`, b.state, BreakerHalfOpen)
+	}
+	if b.allow(policy) {
+		t.Fatalf(`Unexpected allowed more than one probe when breaker is half-open`)
+	}
+
+	b.done(policy, true)
+	if b.state != BreakerClosed || b.failures != 0 {
+		t.Fatalf(`Unexpected breaker state, got %s with %d failures`, b.state, b.failures)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	policy := config.SlotPolicy{}
+	b := &breaker{slot: "breaker-disabled"}
+
+	for i := 0; i < 10; i++ {
+		b.done(policy, false)
+	}
+	if !b.allow(policy) || b.state != BreakerClosed {
+		t.Fatalf(`Unexpected breaker state, got %s`, b.state)
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package change compares the captures of a URL, it generates the text diff of
+the readability text and the visual diff of the screenshots.
+*/
+package change // import "github.com/wabarc/wayback/change"
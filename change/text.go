@@ -0,0 +1,46 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package change // import "github.com/wabarc/wayback/change"
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// contextLines is the number of the unchanged lines around the changes of
+// the text diff.
+const contextLines = 3
+
+// Text returns the unified diff from the text a to the text b labeled by
+// from and to, or an empty string if the texts are the same. The lines are
+// compared with the whitespaces collapsed, and the blank lines are ignored.
+func Text(a, b, from, to string) (string, error) {
+	al, bl := lines(a), lines(b)
+	if slices.Equal(al, bl) {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        al,
+		B:        bl,
+		FromFile: from,
+		ToFile:   to,
+		Context:  contextLines,
+	})
+}
+
+// lines returns the non-blank lines of the text with the whitespaces
+// collapsed, each line ends with a newline.
+func lines(text string) []string {
+	ls := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			ls = append(ls, line+"\n")
+		}
+	}
+	return ls
+}
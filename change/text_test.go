@@ -0,0 +1,45 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package change // import "github.com/wabarc/wayback/change"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"same", "Terms\nof service", "Terms\nof service", nil},
+		{"whitespaces", "Terms  of\n\n service", " Terms of\nservice\n", nil},
+		{"changed", "Terms\nWe collect your email.\nEnd", "Terms\nWe collect your email and phone.\nEnd", []string{
+			"--- previous\n+++ current\n",
+			"-We collect your email.\n",
+			"+We collect your email and phone.\n",
+			" Terms\n",
+		}},
+		{"added", "", "Privacy", []string{"+Privacy\n"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Text(test.a, test.b, "previous", "current")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(test.want) == 0 && got != "" {
+				t.Fatalf("Unexpected diff of the same texts:\n%s", got)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Unexpected diff, %q not found in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package change // import "github.com/wabarc/wayback/change"
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	_ "image/jpeg" // register the JPEG decoder
+)
+
+// tolerance is the maximum difference of the color channels of two pixels
+// which are regarded as the same, it ignores the noises of the rendering.
+const tolerance = 16
+
+// highlight is the color of the changed pixels of the visual diff.
+var highlight = color.NRGBA{R: 255, A: 255}
+
+// Visual compares the images a and b aligned at the top-left corners, it
+// returns the ratio of the changed pixels and the visual diff image, which
+// is b with the changed pixels highlighted and the others faded. The pixels
+// out of either image are changed.
+func Visual(a, b image.Image) (*image.NRGBA, float64) {
+	ab, bb := a.Bounds(), b.Bounds()
+	w, h := max(ab.Dx(), bb.Dx()), max(ab.Dy(), bb.Dy())
+	diff := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if w == 0 || h == 0 {
+		return diff, 0
+	}
+
+	var changed int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pa, pb := image.Pt(ab.Min.X+x, ab.Min.Y+y), image.Pt(bb.Min.X+x, bb.Min.Y+y)
+			if !pa.In(ab) || !pb.In(bb) || !same(a.At(pa.X, pa.Y), b.At(pb.X, pb.Y)) {
+				changed++
+				diff.SetNRGBA(x, y, highlight)
+				continue
+			}
+			diff.SetNRGBA(x, y, fade(b.At(pb.X, pb.Y)))
+		}
+	}
+	return diff, float64(changed) / float64(w*h)
+}
+
+// Open decodes the PNG or JPEG image of the file.
+func Open(name string) (image.Image, error) {
+	f, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// Save encodes the image to the file in PNG format.
+func Save(name string, img image.Image) error {
+	f, err := os.OpenFile(filepath.Clean(name), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func same(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return near(ar, br) && near(ag, bg) && near(ab, bb) && near(aa, ba)
+}
+
+// near reports whether the 16-bit color channels are within the tolerance.
+func near(a, b uint32) bool {
+	a, b = a>>8, b>>8
+	if a > b {
+		return a-b <= tolerance
+	}
+	return b-a <= tolerance
+}
+
+// fade blends the color with white, so that the highlighted pixels stand
+// out in the visual diff.
+func fade(c color.Color) color.NRGBA {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return color.NRGBA{
+		R: 255 - (255-n.R)/3,
+		G: 255 - (255-n.G)/3,
+		B: 255 - (255-n.B)/3,
+		A: 255,
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package change // import "github.com/wabarc/wayback/change"
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+func fill(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestVisual(t *testing.T) {
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+
+	a := fill(10, 10, white)
+	b := fill(10, 10, color.NRGBA{R: 250, G: 250, B: 250, A: 255})
+	if _, ratio := Visual(a, b); ratio != 0 {
+		t.Errorf("Unexpected ratio of the noises, got %f instead of 0", ratio)
+	}
+
+	for x := 0; x < 10; x++ {
+		b.Set(x, 0, color.Black)
+	}
+	diff, ratio := Visual(a, b)
+	if ratio != 0.1 {
+		t.Errorf("Unexpected ratio, got %f instead of 0.1", ratio)
+	}
+	if got := diff.NRGBAAt(0, 0); got != highlight {
+		t.Errorf("Unexpected changed pixel %v", got)
+	}
+	if got := diff.NRGBAAt(0, 1); got == highlight {
+		t.Errorf("Unexpected unchanged pixel %v", got)
+	}
+
+	// The extra rows of the taller image are changed.
+	diff, ratio = Visual(a, fill(10, 20, white))
+	if ratio != 0.5 || diff.Bounds().Dy() != 20 {
+		t.Errorf("Unexpected ratio %f and bounds %v of the images of different sizes", ratio, diff.Bounds())
+	}
+}
+
+func TestOpenSave(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "diff.png")
+	img := fill(4, 3, color.Black)
+	if err := Save(name, img); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := Open(name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ratio := Visual(img, got); ratio != 0 {
+		t.Errorf("Unexpected ratio of the saved image, got %f", ratio)
+	}
+	if _, err = Open(name + ".missing"); err == nil {
+		t.Error("Unexpected open of a missing file")
+	}
+}
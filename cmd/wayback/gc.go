@@ -0,0 +1,110 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/janitor"
+	"github.com/wabarc/wayback/storage"
+)
+
+var (
+	gcDryRun     bool
+	gcMaxAge     int
+	gcMaxSize    string
+	gcKeepLatest int
+
+	gcCmd = &cobra.Command{
+		Use:   "gc",
+		Short: "Remove the artifacts matched by the retention rules",
+		Long: `Remove the artifacts in the storage directory matched by the retention rules of
+WAYBACK_RETENTION_* or the flags, and reclaim the blobs no longer referenced.
+The running service applies the rules periodically, stop it before removing the files
+or list them with --dry-run instead.`,
+		Example: `  wayback gc --dry-run
+  wayback gc --max-age 30 --max-size 10GB
+  wayback gc --keep-latest 3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts, err := parseOptions()
+			if err != nil {
+				return err
+			}
+			if !opts.EnabledReduxer() {
+				return errors.New("storage directory is not specified by WAYBACK_STORAGE_DIR")
+			}
+
+			policy := janitor.PolicyOf(opts)
+			if cmd.Flags().Changed("max-age") {
+				policy.MaxAge = time.Duration(gcMaxAge) * 24 * time.Hour
+			}
+			if cmd.Flags().Changed("max-size") {
+				if policy.MaxSize, err = humanize.ParseBytes(gcMaxSize); err != nil {
+					return errors.Wrap(err, "invalid max size")
+				}
+			}
+			if cmd.Flags().Changed("keep-latest") {
+				policy.KeepLatest = gcKeepLatest
+			}
+			dryRun := opts.RetentionDryRun()
+			if cmd.Flags().Changed("dry-run") {
+				dryRun = gcDryRun
+			}
+
+			j := janitor.New(opts.StorageDir(), policy, dryRun)
+			if !opts.IsDefaultDatabaseURL() {
+				db, err := storage.NewConnectionPool(
+					opts.DatabaseURL(),
+					opts.DatabaseMinConns(),
+					opts.DatabaseMaxConns(),
+					opts.DatabaseConnectionLifetime(),
+				)
+				if err != nil {
+					return errors.Wrap(err, "connect to database failed")
+				}
+				store := storage.NewStorage(db, nil)
+				defer store.Close()
+				j.Forget(store)
+			}
+
+			report, err := j.Sweep()
+			if err != nil {
+				return errors.Wrap(err, "sweep storage directory failed")
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tKIND\tSIZE\tCREATED\tREASON")
+			for _, f := range report.Files {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					f.Name, f.Kind, humanize.Bytes(uint64(f.Size)), formatTime(f.Created), f.Reason)
+			}
+			if err = w.Flush(); err != nil {
+				return err
+			}
+
+			if report.DryRun {
+				cmd.Printf("%d files to remove, %s to reclaim\n", len(report.Files), humanize.Bytes(uint64(report.Bytes)))
+				return nil
+			}
+			cmd.Printf("%d files and %d blobs removed, %s reclaimed\n", len(report.Files), report.Blobs, humanize.Bytes(uint64(report.Bytes)))
+			return nil
+		},
+	}
+)
+
+func init() {
+	gcCmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file path, defaults: ./wayback.conf, ~/wayback.conf, /etc/wayback.conf")
+	gcCmd.Flags().BoolVarP(&gcDryRun, "dry-run", "n", false, "List the files matched by the retention rules without removing them")
+	gcCmd.Flags().IntVarP(&gcMaxAge, "max-age", "", 0, "Days after which the files are removed, overrides WAYBACK_RETENTION_MAX_AGE")
+	gcCmd.Flags().StringVarP(&gcMaxSize, "max-size", "", "", "Max total size of the files, e.g. 10GB, overrides WAYBACK_RETENTION_MAX_SIZE")
+	gcCmd.Flags().IntVarP(&gcKeepLatest, "keep-latest", "", 0, "Number of the latest captures of a URL to keep, overrides WAYBACK_RETENTION_KEEP_LATEST")
+
+	rootCmd.AddCommand(gcCmd)
+}
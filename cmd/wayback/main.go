@@ -27,6 +27,7 @@ var (
 	ip bool
 	ph bool
 	ga bool
+	lc bool
 
 	daemon []string
 
@@ -47,12 +48,15 @@ var (
 
 	migrate bool
 
+	feeds bool
+
 	rootCmd = &cobra.Command{
 		Use:   "wayback",
 		Short: "A command-line tool and daemon service for archiving webpages.",
 		Example: `  wayback https://www.wikipedia.org
   wayback https://www.fsf.org https://www.eff.org
   wayback --ia https://www.fsf.org
+  wayback --ia --feed https://blog.example.com/feed.xml
   wayback --ia --is -d telegram -t your-telegram-bot-token
   WAYBACK_SLOT=pinata WAYBACK_APIKEY=YOUR-PINATA-APIKEY \
     WAYBACK_SECRET=YOUR-PINATA-SECRET wayback --ip https://www.fsf.org`,
@@ -62,7 +66,10 @@ var (
 		Run: func(cmd *cobra.Command, args []string) {
 			run(cmd, args)
 		},
-		Version: version.Version,
+		// Accept URLs as arguments along with the subcommands.
+		Args:              cobra.ArbitraryArgs,
+		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
+		Version:           version.Version,
 	}
 )
 
@@ -77,6 +84,7 @@ func init() {
 	rootCmd.Flags().BoolVarP(&ip, "ip", "", false, "Wayback webpages to IPFS")
 	rootCmd.Flags().BoolVarP(&ph, "ph", "", false, "Wayback webpages to Telegraph")
 	rootCmd.Flags().BoolVarP(&ga, "ga", "", false, "Wayback webpages to Ghost Archive")
+	rootCmd.Flags().BoolVarP(&lc, "lc", "", false, "Wayback webpages to local capture, requires WAYBACK_STORAGE_DIR")
 	rootCmd.Flags().StringSliceVarP(&daemon, "daemon", "d", []string{}, "Run as daemon service, supported services are telegram, web, mastodon, twitter, discord, slack, irc, xmpp")
 	rootCmd.Flags().StringVarP(&host, "ipfs-host", "", "127.0.0.1", "IPFS daemon host, do not require, unless enable ipfs")
 	rootCmd.Flags().UintVarP(&port, "ipfs-port", "p", 5001, "IPFS daemon port")
@@ -91,6 +99,7 @@ func init() {
 	rootCmd.Flags().BoolVarP(&info, "info", "", false, "Show application information")
 	rootCmd.Flags().BoolVarP(&print, "print", "", false, "Show application configurations")
 	rootCmd.Flags().BoolVarP(&migrate, "migrate", "", false, "Run SQL migrations")
+	rootCmd.Flags().BoolVarP(&feeds, "feed", "", false, "Archive the pages of the sitemaps, RSS or Atom feeds or OPML lists given by the URLs")
 }
 
 func checkRequiredFlags(cmd *cobra.Command) error {
@@ -136,6 +145,9 @@ func setToEnv(cmd *cobra.Command) {
 	if flags.Changed("ga") {
 		os.Setenv("WAYBACK_ENABLE_GA", fmt.Sprint(ga))
 	}
+	if flags.Changed("lc") {
+		os.Setenv("WAYBACK_ENABLE_LC", fmt.Sprint(lc))
+	}
 	if flags.Changed("token") {
 		os.Setenv("WAYBACK_TELEGRAM_TOKEN", token)
 	}
@@ -161,7 +173,7 @@ func setToEnv(cmd *cobra.Command) {
 
 // nolint:gocyclo
 func run(cmd *cobra.Command, args []string) {
-	if !ia && !is && !ip && !ph && !ga {
+	if !ia && !is && !ip && !ph && !ga && !lc {
 		ia, is, ph, ga = true, true, true, true
 		os.Setenv("WAYBACK_ENABLE_IA", "true")
 		os.Setenv("WAYBACK_ENABLE_IS", "true")
@@ -13,8 +13,11 @@ import (
 	"github.com/wabarc/logger"
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/ingress"
+	"github.com/wabarc/wayback/janitor"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
+	"github.com/wabarc/wayback/schedule"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
 	"github.com/wabarc/wayback/systemd"
@@ -58,8 +61,12 @@ func serve(_ *cobra.Command, opts *config.Options, _ []string) {
 
 	cfg := []pooling.Option{
 		pooling.Capacity(opts.PoolingSize()),
+		pooling.SourceLimit(opts.PoolingSourceLimit()),
+		pooling.SourceLimits(opts.PoolingSourceLimits()),
+		pooling.Priorities(priorities(opts)),
 		pooling.Timeout(opts.WaybackTimeout()),
 		pooling.MaxRetries(opts.WaybackMaxRetries()),
+		pooling.Storage(store),
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -78,9 +85,22 @@ func serve(_ *cobra.Command, opts *config.Options, _ []string) {
 		service.Storage(store),
 		service.Pool(pool),
 		service.Publish(pub),
+		service.Limiter(ratelimit.FromConfig(opts)),
 	}
 	options := service.ParseOptions(opt...)
 
+	// Apply the retention rules and reclaim the blobs no longer referenced
+	if opts.EnabledReduxer() && (opts.EnabledRetention() || opts.StorageDedup()) {
+		j := janitor.New(opts.StorageDir(), janitor.PolicyOf(opts), opts.RetentionDryRun())
+		if !opts.IsDefaultDatabaseURL() {
+			j.Forget(store)
+		}
+		go j.Run(ctx, opts.RetentionInterval())
+	}
+
+	// Archive the watched URLs on their schedules
+	go schedule.New(ctx, store, pool, service.ArchiveWatch(options), service.ExpandWatch(options)).Run()
+
 	err = service.Serve(ctx, options)
 	if err != nil {
 		logger.Error("server failed: %v", err)
@@ -127,3 +147,18 @@ func handle(pool *pooling.Pool, pub *publish.Publish, cancel context.CancelFunc)
 
 	cancel()
 }
+
+// priorities returns the priorities of the services in the worker pool
+// configured by the options.
+func priorities(opts *config.Options) map[string]pooling.Priority {
+	m := make(map[string]pooling.Priority)
+	for service, s := range opts.PoolingPriorities() {
+		var p pooling.Priority
+		if err := p.UnmarshalText([]byte(s)); err != nil {
+			logger.Warn("ignored pooling priority of %s: %v", service, err)
+			continue
+		}
+		m[service] = p
+	}
+	return m
+}
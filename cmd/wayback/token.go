@@ -0,0 +1,189 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/storage"
+)
+
+var (
+	tokenName    string
+	tokenScopes  []string
+	tokenExpires time.Duration
+
+	tokenCmd = &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens of the web service",
+		Long: `Manage API tokens of the web service, which are required if WAYBACK_WEB_AUTH is enabled.
+The tokens are stored in the bolt database, stop the running service before managing them
+or use the /api/v1/tokens endpoints with an admin token instead.`,
+	}
+
+	tokenCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create an API token, the secret is only shown once",
+		Example: `  wayback token create --name partner --scope archive,playback
+  wayback token create --name admin --scope admin --expires 720h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			scopes := make([]entity.Scope, 0, len(tokenScopes))
+			for _, s := range tokenScopes {
+				scope, err := entity.ParseScope(strings.TrimSpace(s))
+				if err != nil {
+					return err
+				}
+				scopes = append(scopes, scope)
+			}
+			var expires time.Time
+			if tokenExpires > 0 {
+				expires = time.Now().Add(tokenExpires).UTC()
+			}
+
+			store, err := openStorage()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			secret, t, err := store.CreateToken(tokenName, scopes, expires)
+			if err != nil {
+				return errors.Wrap(err, "create token failed")
+			}
+			cmd.Printf("Token %d created, keep the secret safe since it will not be shown again:\n\n", t.ID)
+			cmd.Println(secret)
+			return nil
+		},
+	}
+
+	tokenListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens and their usage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, err := openStorage()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			tokens, err := store.Tokens()
+			if err != nil {
+				return errors.Wrap(err, "query tokens failed")
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tSCOPES\tUSAGE\tCREATED\tEXPIRES\tLAST USED")
+			for _, t := range tokens {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					t.ID, t.Name, joinScopes(t.Scopes), usage(t.Usage),
+					formatTime(t.Created), formatTime(t.Expires), formatTime(t.LastUsed))
+			}
+			return w.Flush()
+		},
+	}
+
+	tokenRevokeCmd = &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return errors.New("invalid token id: " + args[0])
+			}
+
+			store, err := openStorage()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if err = store.DeleteToken(id); err != nil {
+				return errors.Wrap(err, "revoke token failed")
+			}
+			cmd.Printf("Token %d revoked\n", id)
+			return nil
+		},
+	}
+)
+
+func init() {
+	tokenCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Configuration file path, defaults: ./wayback.conf, ~/wayback.conf, /etc/wayback.conf")
+
+	tokenCreateCmd.Flags().StringVarP(&tokenName, "name", "n", "", "Name of the token, e.g. the partner who holds it")
+	tokenCreateCmd.Flags().StringSliceVarP(&tokenScopes, "scope", "s", []string{string(entity.ScopeArchive), string(entity.ScopePlayback)}, "Scopes granted to the token, supported scopes are archive, playback, read-history, admin")
+	tokenCreateCmd.Flags().DurationVarP(&tokenExpires, "expires", "", 0, "Duration until the token expires, e.g. 720h, the token never expires by default")
+	// nolint:errcheck
+	tokenCreateCmd.MarkFlagRequired("name")
+
+	tokenCmd.AddCommand(tokenCreateCmd, tokenListCmd, tokenRevokeCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
+
+// parseOptions parses the options from the configuration file and the
+// environment variables.
+func parseOptions() (*config.Options, error) {
+	parser := config.NewParser()
+	if _, err := parser.ParseFile(configFile); err != nil {
+		return nil, errors.Wrap(err, "parse configuration file failed")
+	}
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		return nil, errors.Wrap(err, "parse environment variables failed")
+	}
+	return opts, nil
+}
+
+// openStorage opens the bolt database of the configuration, which is locked
+// by the running service.
+func openStorage() (*storage.Storage, error) {
+	opts, err := parseOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := storage.Open(opts, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "open storage failed, stop the running service first")
+	}
+	return storage.NewStorage(nil, db), nil
+}
+
+func joinScopes(scopes []entity.Scope) string {
+	ss := make([]string, len(scopes))
+	for i, s := range scopes {
+		ss[i] = string(s)
+	}
+	return strings.Join(ss, ",")
+}
+
+func usage(u map[entity.Scope]uint64) string {
+	if len(u) == 0 {
+		return "-"
+	}
+	ss := make([]string, 0, len(u))
+	for scope, n := range u {
+		ss = append(ss, fmt.Sprintf("%s=%d", scope, n))
+	}
+	sort.Strings(ss)
+	return strings.Join(ss, ",")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/schedule"
+	"github.com/wabarc/wayback/service"
+)
+
+var (
+	watchSchedule string
+	watchFeed     bool
+	watchServer   string
+	watchToken    string
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Manage watched URLs archived on schedules",
+		Long: `Manage watched URLs, which are archived on their schedules by the running service.
+The watches are managed by the /api/v1/watches endpoints of the running service, or
+in the bolt database directly if the service is not running.`,
+	}
+
+	watchAddCmd = &cobra.Command{
+		Use:   "add <url>",
+		Short: "Watch a URL",
+		Example: `  wayback watch add https://example.com
+  wayback watch add https://example.com --schedule "0 8 * * *"
+  wayback watch add https://example.com --schedule "@every 6h"
+  wayback watch add https://example.com/feed.xml --feed --schedule @hourly`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w, err := schedule.NewWatch(args[0], watchSchedule, "cli")
+			if err != nil {
+				return err
+			}
+			w.Feed = watchFeed
+
+			c, err := newWatchClient()
+			if err != nil {
+				return err
+			}
+			body := map[string]any{"url": w.URL, "schedule": w.Schedule, "feed": w.Feed}
+			err = c.do(http.MethodPost, "/watches", body, w)
+			if unreachable(err) {
+				store, er := openStorage()
+				if er != nil {
+					return er
+				}
+				defer store.Close()
+				err = store.CreateWatch(w)
+			}
+			if err != nil {
+				return errors.Wrap(err, "create watch failed")
+			}
+			cmd.Printf("Watch %d created, next run at %s\n", w.ID, formatTime(w.NextRun))
+			return nil
+		},
+	}
+
+	watchListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List watched URLs and their runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := newWatchClient()
+			if err != nil {
+				return err
+			}
+			var watches []*entity.Watch
+			err = c.do(http.MethodGet, "/watches", nil, &watches)
+			if unreachable(err) {
+				store, er := openStorage()
+				if er != nil {
+					return er
+				}
+				defer store.Close()
+				watches, err = store.Watches()
+			}
+			if err != nil {
+				return errors.Wrap(err, "query watches failed")
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tURL\tSCHEDULE\tFEED\tOWNER\tRUNS\tLAST RUN\tNEXT RUN\tLAST ERROR")
+			for _, wt := range watches {
+				lastError := wt.LastError
+				if lastError == "" {
+					lastError = "-"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\t%d\t%s\t%s\t%s\n",
+					wt.ID, wt.URL, wt.Schedule, wt.Feed, wt.Owner, wt.Runs,
+					formatTime(wt.LastRun), formatTime(wt.NextRun), lastError)
+			}
+			return w.Flush()
+		},
+	}
+
+	watchRemoveCmd = &cobra.Command{
+		Use:   "rm <id>",
+		Short: "Stop watching a URL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return errors.New("invalid watch id: " + args[0])
+			}
+
+			c, err := newWatchClient()
+			if err != nil {
+				return err
+			}
+			err = c.do(http.MethodDelete, "/watches/"+args[0], nil, nil)
+			if unreachable(err) {
+				store, er := openStorage()
+				if er != nil {
+					return er
+				}
+				defer store.Close()
+				err = store.DeleteWatch(id)
+			}
+			if err != nil {
+				return errors.Wrap(err, "delete watch failed")
+			}
+			cmd.Printf("Watch %d deleted\n", id)
+			return nil
+		},
+	}
+)
+
+// watchClient requests the watches endpoints of the API of the running service.
+type watchClient struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// newWatchClient returns the watchClient of the service specified by the
+// flags, or the one listening on the address of the configuration.
+func newWatchClient() (*watchClient, error) {
+	opts, err := parseOptions()
+	if err != nil {
+		return nil, err
+	}
+	base := watchServer
+	if base == "" {
+		base = serviceURL(opts)
+	}
+	return &watchClient{
+		base:   strings.TrimSuffix(base, "/") + "/api/v1",
+		token:  watchToken,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// serviceURL returns the URL of the service listening on the address of the
+// options, the unspecified hosts are reached by the loopback address.
+func serviceURL(opts *config.Options) string {
+	host, port, err := net.SplitHostPort(opts.ListenAddr())
+	if err != nil {
+		return "http://" + opts.ListenAddr()
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// do requests the API with the body encoded as JSON and decodes the response
+// into out if not nil, the error objects of the API are returned as errors.
+func (c *watchClient) do(method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequest(method, c.base+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&e) != nil || e.Error.Message == "" {
+			return errors.New("unexpected response: %s", resp.Status)
+		}
+		return errors.New("%s", e.Error.Message)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// unreachable reports whether the error is caused by the service not
+// running, the watches are managed in the bolt database directly then.
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func init() {
+	watchCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Configuration file path, defaults: ./wayback.conf, ~/wayback.conf, /etc/wayback.conf")
+	watchCmd.PersistentFlags().StringVarP(&watchServer, "server", "", "", "URL of the running service, defaults to the one listening on WAYBACK_LISTEN_ADDR")
+	watchCmd.PersistentFlags().StringVarP(&watchToken, "token", "", "", "API token if WAYBACK_WEB_AUTH is enabled, removing watches requires the admin scope")
+
+	watchAddCmd.Flags().BoolVarP(&watchFeed, "feed", "", false, "Archive the new items of the sitemap, RSS or Atom feed or OPML list of the URL")
+	watchAddCmd.Flags().StringVarP(&watchSchedule, "schedule", "s", service.DefaultSchedule, "Schedule of the watch, a cron expression, e.g. \"0 8 * * *\", or a descriptor, e.g. @daily, @every 6h")
+
+	watchCmd.AddCommand(watchAddCmd, watchListCmd, watchRemoveCmd)
+	rootCmd.AddCommand(watchCmd)
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/wabarc/wayback"
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/feed"
+	"github.com/wabarc/wayback/ingress"
 	"github.com/wabarc/wayback/reduxer"
 	"golang.org/x/sync/errgroup"
 )
@@ -68,6 +70,13 @@ func archive(cmd *cobra.Command, opts *config.Options, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
+	if feeds {
+		if urls, err = expandFeeds(ctx, urls); err != nil {
+			cmd.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	if err := archiving(ctx, urls); err != nil {
 		cmd.PrintErrln(err)
 	}
@@ -90,7 +99,7 @@ func pretty(cols []wayback.Collect, rdx reduxer.Reduxer) string {
 		writer.Indent()
 		items := make([]interface{}, 0)
 		for _, col := range grouped[src] {
-			item := fmt.Sprintf("%s: %s", strings.ToUpper(col.Arc), col.Dst)
+			item := fmt.Sprintf("%s: %s", strings.ToUpper(col.Arc), col.Result())
 			items = append(items, item)
 		}
 
@@ -137,6 +146,25 @@ func unmarshalArgs(args []string) (urls []*url.URL, err error) {
 	return
 }
 
+// expandFeeds returns the URLs of the pages of the sitemaps, feeds or OPML
+// lists of the URLs.
+func expandFeeds(ctx context.Context, urls []*url.URL) (items []*url.URL, err error) {
+	for _, u := range urls {
+		links, er := feed.Expand(ctx, ingress.Client(), u.String())
+		if er != nil {
+			return nil, errors.Wrap(er, "expand "+u.String()+" failed")
+		}
+		for _, link := range links {
+			item, er := url.Parse(link)
+			if er != nil {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
 func readFromFile(s string) (urls []*url.URL) {
 	if helper.Exists(s) {
 		file, err := os.Open(filepath.Clean(s))
@@ -14,8 +14,11 @@ const (
 	SLOT_PH = "ph" // Telegraph
 	SLOT_GA = "ga" // Ghostarchive
 	SLOT_TT = "tt" // Time Travel
+	SLOT_LC = "lc" // Local capture
 
 	PB_SLUG = "/playback" // Identity for playback
+	LC_SLUG = "/capture"  // Identity for local captures
+	RP_SLUG = "/replay"   // Identity for replaying local captures
 	UNKNOWN = "unknown"
 )
 
@@ -62,17 +65,8 @@ func (f Flag) String() string {
 
 // SlotName returns the descriptions of the wayback service.
 func SlotName(s string) string {
-	slots := map[string]string{
-		SLOT_IA: "Internet Archive",
-		SLOT_IS: "archive.today",
-		SLOT_IP: "IPFS",
-		SLOT_PH: "Telegraph",
-		SLOT_GA: "Ghost Archive",
-		SLOT_TT: "Time Travel",
-	}
-
-	if _, exist := slots[s]; exist {
-		return slots[s]
+	if slot, exist := LookupSlot(s); exist && slot.Title != "" {
+		return slot.Title
 	}
 
 	return UNKNOWN
@@ -80,17 +74,8 @@ func SlotName(s string) string {
 
 // SlotExtra returns the extra config of wayback slot.
 func SlotExtra(s string) string {
-	extra := map[string]string{
-		SLOT_IA: "https://web.archive.org/",
-		SLOT_IS: "https://archive.today/",
-		SLOT_IP: "https://ipfs.github.io/public-gateway-checker/",
-		SLOT_PH: "https://telegra.ph/",
-		SLOT_GA: "https://ghostarchive.org/",
-		SLOT_TT: "http://timetravel.mementoweb.org/",
-	}
-
-	if _, exist := extra[s]; exist {
-		return extra[s]
+	if slot, exist := LookupSlot(s); exist && slot.Extra != "" {
+		return slot.Extra
 	}
 
 	return UNKNOWN
@@ -10,6 +10,7 @@ package config // import "github.com/wabarc/wayback/config"
 
 import (
 	"os"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
@@ -626,6 +627,86 @@ func TestDefaultSlots(t *testing.T) {
 	}
 }
 
+func TestRegisterSlot(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_ENABLE_XX", "true")
+
+	RegisterSlot(Slot{Name: "xx", Title: "Example Archive", Extra: "https://example.org/", Env: "WAYBACK_ENABLE_XX"})
+	t.Cleanup(func() { UnregisterSlot("xx") })
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	if got := opts.Slots(); !got["xx"] {
+		t.Fatalf(`Unexpected registered slot toggle, got %v`, got)
+	}
+	if got := SlotName("xx"); got != "Example Archive" {
+		t.Errorf(`Unexpected slot name, got %s instead of Example Archive`, got)
+	}
+	if got := SlotExtra("xx"); got != "https://example.org/" {
+		t.Errorf(`Unexpected slot extra, got %s instead of https://example.org/`, got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("RegisterSlot should have panicked")
+		}
+	}()
+	RegisterSlot(Slot{Name: "xx"})
+}
+
+func TestSlotPolicy(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_SLOT_TIMEOUT", "60")
+	os.Setenv("WAYBACK_SLOT_MAX_RETRIES", "1")
+	os.Setenv("WAYBACK_IS_MAX_RETRIES", "3")
+	os.Setenv("WAYBACK_IS_BREAKER_THRESHOLD", "5")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	tests := []struct {
+		slot     string
+		expected SlotPolicy
+	}{
+		{
+			slot: SLOT_IA,
+			expected: SlotPolicy{
+				Timeout:          60 * time.Second,
+				MaxRetries:       1,
+				Backoff:          defSlotBackoff * time.Second,
+				BreakerThreshold: defSlotBreakerThreshold,
+				BreakerCooldown:  defSlotBreakerCooldown * time.Second,
+			},
+		},
+		{
+			slot: SLOT_IS,
+			expected: SlotPolicy{
+				Timeout:          60 * time.Second,
+				MaxRetries:       3,
+				Backoff:          defSlotBackoff * time.Second,
+				BreakerThreshold: 5,
+				BreakerCooldown:  defSlotBreakerCooldown * time.Second,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.slot, func(t *testing.T) {
+			got := opts.SlotPolicy(test.slot)
+			if got != test.expected {
+				t.Fatalf(`Unexpected slot policy, got %#v instead of %#v`, got, test.expected)
+			}
+		})
+	}
+}
+
 func TestTelegram(t *testing.T) {
 	tests := []struct {
 		name string
@@ -2792,6 +2873,123 @@ func TestPoolingSize(t *testing.T) {
 	}
 }
 
+func TestPoolingSourceLimit(t *testing.T) {
+	limit := 2
+	os.Clearenv()
+	os.Setenv("WAYBACK_POOLING_SOURCE_LIMIT", strconv.Itoa(limit))
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	got := opts.PoolingSourceLimit()
+	if got != limit {
+		t.Fatalf(`Unexpected pooling source limit got %d instead of %d`, got, limit)
+	}
+}
+
+func TestPoolingSourceLimits(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_POOLING_SOURCE_LIMITS", "Telegram=1, web=4,discord=foo,=2")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	got := opts.PoolingSourceLimits()
+	if len(got) != 2 || got["telegram"] != 1 || got["web"] != 4 {
+		t.Fatalf(`Unexpected pooling source limits got %v`, got)
+	}
+}
+
+func TestPoolingPriorities(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_POOLING_PRIORITIES", "web=HIGH,schedule=low,discord=urgent")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	got := opts.PoolingPriorities()
+	if len(got) != 2 || got["web"] != "high" || got["schedule"] != "low" {
+		t.Fatalf(`Unexpected pooling priorities got %v`, got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var tests = []struct {
+		user    string
+		service string
+		burst   string
+		expUser int
+		expSrv  int
+		expBst  int
+	}{
+		{"", "", "", defRateLimitUser, defRateLimitService, defRateLimitBurst},
+		{"10", "60", "3", 10, 60, 3},
+		{"foo", "bar", "zoo", defRateLimitUser, defRateLimitService, defRateLimitBurst},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_RATE_LIMIT_USER", test.user)
+			os.Setenv("WAYBACK_RATE_LIMIT_SERVICE", test.service)
+			os.Setenv("WAYBACK_RATE_LIMIT_BURST", test.burst)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.RateLimitUser(); got != test.expUser {
+				t.Errorf(`Unexpected rate limit of user got %d instead of %d`, got, test.expUser)
+			}
+			if got := opts.RateLimitService(); got != test.expSrv {
+				t.Errorf(`Unexpected rate limit of service got %d instead of %d`, got, test.expSrv)
+			}
+			if got := opts.RateLimitBurst(); got != test.expBst {
+				t.Errorf(`Unexpected rate limit burst got %d instead of %d`, got, test.expBst)
+			}
+		})
+	}
+}
+
+func TestEnabledWebAuth(t *testing.T) {
+	var tests = []struct {
+		val string
+		exp bool
+	}{
+		{"", defWebAuth},
+		{"true", true},
+		{"false", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.val, func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_WEB_AUTH", test.val)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.EnabledWebAuth(); got != test.exp {
+				t.Fatalf(`Unexpected web auth got %t instead of %t`, got, test.exp)
+			}
+		})
+	}
+}
+
 func TestBoltPath(t *testing.T) {
 	path := "./wayback.db"
 
@@ -2844,6 +3042,109 @@ func TestStorageDir(t *testing.T) {
 	}
 }
 
+func TestStorageDedup(t *testing.T) {
+	var tests = []struct {
+		dedup string
+		exp   bool
+	}{
+		{"", false},
+		{"false", false},
+		{"on", true},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_STORAGE_DEDUP", test.dedup)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.StorageDedup(); got != test.exp {
+				t.Errorf(`Unexpected storage dedup got %t instead of %t`, got, test.exp)
+			}
+		})
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	var tests = []struct {
+		artifacts string
+		exp       string
+	}{
+		{"", "full"},
+		{"minimal", "minimal"},
+		{"img,txt,warc", "img,txt,warc"},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_ARTIFACTS", test.artifacts)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.Artifacts(); got != test.exp {
+				t.Errorf(`Unexpected artifacts got %s instead of %s`, got, test.exp)
+			}
+		})
+	}
+}
+
+func TestRetention(t *testing.T) {
+	os.Clearenv()
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+	if opts.EnabledRetention() || opts.RetentionInterval() != time.Hour || opts.RetentionDryRun() {
+		t.Errorf(`Unexpected default retention: %+v`, opts.retention)
+	}
+
+	os.Setenv("WAYBACK_RETENTION_MAX_AGE", "30")
+	os.Setenv("WAYBACK_RETENTION_MAX_SIZE", "10GB")
+	os.Setenv("WAYBACK_RETENTION_KINDS", "media=7d, MEDIA=1GB,har=500MB,pdf=foo,warc=0d,=1d")
+	os.Setenv("WAYBACK_RETENTION_KEEP_LATEST", "3")
+	os.Setenv("WAYBACK_RETENTION_INTERVAL", "600")
+	os.Setenv("WAYBACK_RETENTION_DRY_RUN", "true")
+
+	parser = NewParser()
+	opts, err = parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+	if !opts.EnabledRetention() || !opts.RetentionDryRun() {
+		t.Errorf(`Unexpected retention: %+v`, opts.retention)
+	}
+	if got := opts.RetentionMaxAge(); got != 30*24*time.Hour {
+		t.Errorf(`Unexpected retention max age got %s`, got)
+	}
+	if got := opts.RetentionMaxSize(); got != 10000000000 {
+		t.Errorf(`Unexpected retention max size got %d`, got)
+	}
+	if got := opts.RetentionKeepLatest(); got != 3 {
+		t.Errorf(`Unexpected retention keep latest got %d`, got)
+	}
+	if got := opts.RetentionInterval(); got != 10*time.Minute {
+		t.Errorf(`Unexpected retention interval got %s`, got)
+	}
+	exp := map[string]RetentionLimit{
+		"media": {MaxAge: 7 * 24 * time.Hour, MaxSize: 1000000000},
+		"har":   {MaxSize: 500000000},
+	}
+	if got := opts.RetentionKinds(); !reflect.DeepEqual(got, exp) {
+		t.Errorf(`Unexpected retention kinds got %+v instead of %+v`, got, exp)
+	}
+}
+
 func TestEnabledReduxer(t *testing.T) {
 	var tests = []struct {
 		dir string
@@ -3159,6 +3460,123 @@ func TestWaybackTimeout(t *testing.T) {
 	}
 }
 
+func TestFreshnessWindow(t *testing.T) {
+	var tests = []struct {
+		window   string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"600", 10 * time.Minute},
+		{"foo", 0},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_FRESHNESS_WINDOW", test.window)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.FreshnessWindow(); got != test.expected {
+				t.Errorf(`Unexpected freshness window got %s instead of %s`, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestChangeDetection(t *testing.T) {
+	var tests = []struct {
+		detection string
+		threshold string
+		enabled   bool
+		ratio     float64
+	}{
+		{"", "", false, 0.01},
+		{"true", "5", true, 0.05},
+		{"on", "0", true, 0},
+		{"foo", "-1", false, 0},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_CHANGE_DETECTION", test.detection)
+			os.Setenv("WAYBACK_CHANGE_THRESHOLD", test.threshold)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.ChangeDetection(); got != test.enabled {
+				t.Errorf(`Unexpected change detection got %t instead of %t`, got, test.enabled)
+			}
+			if got := opts.ChangeThreshold(); got != test.ratio {
+				t.Errorf(`Unexpected change threshold got %f instead of %f`, got, test.ratio)
+			}
+		})
+	}
+}
+
+func TestArtifactStores(t *testing.T) {
+	var tests = []struct {
+		stores   string
+		expected []string
+	}{
+		{"", []string{"catbox"}},
+		{"none", []string{}},
+		{"S3, webdav,cas,s3", []string{"s3", "webdav", "cas"}},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("WAYBACK_ARTIFACT_STORES", test.stores)
+
+			parser := NewParser()
+			opts, err := parser.ParseEnvironmentVariables()
+			if err != nil {
+				t.Fatalf(`Parsing environment variables failed: %v`, err)
+			}
+
+			if got := opts.ArtifactStores(); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf(`Unexpected artifact stores got %v instead of %v`, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestArtifactStoreLocations(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_STORAGE_DIR", "/tmp/reduxer")
+	os.Setenv("WAYBACK_S3_ENDPOINT", "http://127.0.0.1:9000")
+	os.Setenv("WAYBACK_S3_BUCKET", "wayback")
+	os.Setenv("WAYBACK_S3_ACCESS_KEY", "foo")
+	os.Setenv("WAYBACK_S3_SECRET_KEY", "bar")
+	os.Setenv("WAYBACK_WEBDAV_URL", "https://dav.example.com/wayback/")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	if !opts.EnabledS3() || opts.S3Region() != defS3Region || opts.S3AccessKey() != "foo" || opts.S3SecretKey() != "bar" {
+		t.Errorf(`Unexpected S3 options: %+v`, opts.s3)
+	}
+	if !opts.EnabledWebDAV() || opts.WebDAVUsername() != "" {
+		t.Errorf(`Unexpected WebDAV options: %+v`, opts.webdav)
+	}
+	if got := opts.CASDir(); got != "/tmp/reduxer/cas" {
+		t.Errorf(`Unexpected CAS directory got %s instead of /tmp/reduxer/cas`, got)
+	}
+}
+
 func TestWaybackMaxRetries(t *testing.T) {
 	t.Parallel()
 
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,6 +37,13 @@ const (
 	defEnabledIP = false
 	defEnabledPH = false
 	defEnabledGA = false
+	defEnabledLC = false
+
+	defSlotTimeout          = 0
+	defSlotMaxRetries       = 0
+	defSlotBackoff          = 1
+	defSlotBreakerThreshold = 0
+	defSlotBreakerCooldown  = 300
 
 	defTelegramToken    = ""
 	defTelegramChannel  = ""
@@ -84,6 +92,8 @@ const (
 	defNostrPrivateKey = ""
 
 	defListenAddr      = "0.0.0.0:8964"
+	defPublicURL       = ""
+	defWebAuth         = false
 	defOnionLocalPort  = 8964
 	defOnionPrivateKey = ""
 	defOnionDisabled   = false
@@ -92,8 +102,19 @@ const (
 	defEnabledChromeRemote = false
 	defBoltPathname        = "wayback.db"
 	defPoolingSize         = 3
+	defPoolingSourceLimit  = 0
+	defPoolingSourceLimits = ""
+	defPoolingPriorities   = ""
+	defRateLimitUser       = 0
+	defRateLimitService    = 0
+	defRateLimitBurst      = 5
 	defMaxMediaSize        = "512MB"
+	defStorageDedup        = false
+	defArtifacts           = "full"
 	defWaybackTimeout      = 300
+	defFreshnessWindow     = 0
+	defChangeDetection     = false
+	defChangeThreshold     = 1
 	defWaybackMaxRetries   = 2
 	defWaybackUserAgent    = "WaybackArchiver/1.0"
 	defWaybackFallback     = false
@@ -103,6 +124,26 @@ const (
 	defMeiliIndexing = "capsules"
 	defMeiliApikey   = ""
 
+	defRetentionMaxAge     = 0
+	defRetentionMaxSize    = ""
+	defRetentionKinds      = ""
+	defRetentionKeepLatest = 0
+	defRetentionInterval   = 3600
+	defRetentionDryRun     = false
+
+	defArtifactStores = "catbox"
+	defS3Endpoint     = ""
+	defS3Region       = "us-east-1"
+	defS3Bucket       = ""
+	defS3AccessKey    = ""
+	defS3SecretKey    = ""
+	defS3PublicURL    = ""
+	defWebDAVURL      = ""
+	defWebDAVUsername = ""
+	defWebDAVPassword = ""
+	defCASDir         = ""
+	defCASPublicURL   = ""
+
 	defOmnivoreApikey = ""
 	defPrivacyURL     = ""
 
@@ -136,11 +177,17 @@ type Options struct {
 	nostr               *nostr
 	irc                 *irc
 	meili               *meili
+	retention           *retention
+	s3                  *s3
+	webdav              *webdav
+	cas                 *cas
 	omnivore            *omnivore
 	xmpp                *xmpp
 	discord             *discord
 	ipfs                *ipfs
 	slots               map[string]bool
+	slotPolicy          *slotPolicy
+	slotPolicies        map[string]*slotPolicy
 	database            *database
 	telegram            *telegram
 	mastodon            *mastodon
@@ -158,11 +205,25 @@ type Options struct {
 	proxy               string
 	logLevel            string
 	listenAddr          string
+	publicURL           string
+	webAuth             bool
 	chromeRemoteAddr    string
 	boltPathname        string
 	maxMediaSize        string
 	poolingSize         int
+	poolingSourceLimit  int
+	poolingSourceLimits string
+	poolingPriorities   string
+	rateLimitUser       int
+	rateLimitService    int
+	rateLimitBurst      int
 	waybackTimeout      int
+	freshnessWindow     int
+	changeDetection     bool
+	changeThreshold     int
+	artifactStores      string
+	storageDedup        bool
+	artifacts           string
 	waybackMaxRetries   int
 	enabledChromeRemote bool
 	debug               bool
@@ -275,6 +336,42 @@ type meili struct {
 	apikey   string
 }
 
+type retention struct {
+	maxAge     int
+	maxSize    string
+	kinds      string
+	keepLatest int
+	interval   int
+	dryRun     bool
+}
+
+// RetentionLimit represents the retention limit of a kind of the artifacts,
+// zero values mean unlimited.
+type RetentionLimit struct {
+	MaxAge  time.Duration
+	MaxSize uint64
+}
+
+type s3 struct {
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+}
+
+type webdav struct {
+	url      string
+	username string
+	password string
+}
+
+type cas struct {
+	dir       string
+	publicURL string
+}
+
 type llm struct {
 	provider string
 	baseURL  string
@@ -295,14 +392,28 @@ func NewOptions() *Options {
 		overTor:             defOverTor,
 		metrics:             defMetrics,
 		listenAddr:          defListenAddr,
+		publicURL:           defPublicURL,
+		webAuth:             defWebAuth,
 		chromeRemoteAddr:    defChromeRemoteAddr,
 		enabledChromeRemote: defEnabledChromeRemote,
 		boltPathname:        defBoltPathname,
 		poolingSize:         defPoolingSize,
+		poolingSourceLimit:  defPoolingSourceLimit,
+		poolingSourceLimits: defPoolingSourceLimits,
+		poolingPriorities:   defPoolingPriorities,
+		rateLimitUser:       defRateLimitUser,
+		rateLimitService:    defRateLimitService,
+		rateLimitBurst:      defRateLimitBurst,
 		storageDir:          defStorageDir,
+		storageDedup:        defStorageDedup,
+		artifacts:           defArtifacts,
 		maxMediaSize:        defMaxMediaSize,
 		privacyURL:          defPrivacyURL,
 		waybackTimeout:      defWaybackTimeout,
+		freshnessWindow:     defFreshnessWindow,
+		changeDetection:     defChangeDetection,
+		changeThreshold:     defChangeThreshold,
+		artifactStores:      defArtifactStores,
 		waybackMaxRetries:   defWaybackMaxRetries,
 		waybackUserAgent:    defWaybackUserAgent,
 		waybackFallback:     defWaybackFallback,
@@ -320,13 +431,15 @@ func NewOptions() *Options {
 			apikey: defIPFSApikey,
 			secret: defIPFSSecret,
 		},
-		slots: map[string]bool{
-			SLOT_IA: defEnabledIA,
-			SLOT_IS: defEnabledIS,
-			SLOT_IP: defEnabledIP,
-			SLOT_PH: defEnabledPH,
-			SLOT_GA: defEnabledGA,
+		slots: defaultSlots(),
+		slotPolicy: &slotPolicy{
+			timeout:   defSlotTimeout,
+			retries:   defSlotMaxRetries,
+			backoff:   defSlotBackoff,
+			threshold: defSlotBreakerThreshold,
+			cooldown:  defSlotBreakerCooldown,
 		},
+		slotPolicies: make(map[string]*slotPolicy),
 		telegram: &telegram{
 			token:    defTelegramToken,
 			channel:  defTelegramChannel,
@@ -399,6 +512,31 @@ func NewOptions() *Options {
 			indexing: defMeiliIndexing,
 			apikey:   defMeiliApikey,
 		},
+		retention: &retention{
+			maxAge:     defRetentionMaxAge,
+			maxSize:    defRetentionMaxSize,
+			kinds:      defRetentionKinds,
+			keepLatest: defRetentionKeepLatest,
+			interval:   defRetentionInterval,
+			dryRun:     defRetentionDryRun,
+		},
+		s3: &s3{
+			endpoint:  defS3Endpoint,
+			region:    defS3Region,
+			bucket:    defS3Bucket,
+			accessKey: defS3AccessKey,
+			secretKey: defS3SecretKey,
+			publicURL: defS3PublicURL,
+		},
+		webdav: &webdav{
+			url:      defWebDAVURL,
+			username: defWebDAVUsername,
+			password: defWebDAVPassword,
+		},
+		cas: &cas{
+			dir:       defCASDir,
+			publicURL: defCASPublicURL,
+		},
 		llm: &llm{
 			provider: defLLMProvider,
 			baseURL:  defLLMBaseURL,
@@ -556,6 +694,33 @@ func (o *Options) Slots() map[string]bool {
 	return o.slots
 }
 
+// SlotPolicy returns the timeout, retry and circuit breaker policy of the given
+// slot, the values of specific slot take precedence over the global ones.
+func (o *Options) SlotPolicy(slot string) SlotPolicy {
+	policy := o.slotPolicy
+	if policy == nil {
+		policy = &slotPolicy{}
+	}
+	specific, ok := o.slotPolicies[slot]
+	if !ok {
+		specific = newSlotPolicy()
+	}
+	pick := func(v, fallback int) int {
+		if v < 0 {
+			return fallback
+		}
+		return v
+	}
+
+	return SlotPolicy{
+		Timeout:          time.Duration(pick(specific.timeout, policy.timeout)) * time.Second,
+		MaxRetries:       pick(specific.retries, policy.retries),
+		Backoff:          time.Duration(pick(specific.backoff, policy.backoff)) * time.Second,
+		BreakerThreshold: pick(specific.threshold, policy.threshold),
+		BreakerCooldown:  time.Duration(pick(specific.cooldown, policy.cooldown)) * time.Second,
+	}
+}
+
 // TelegramToken returns the token of Telegram Bot.
 func (o *Options) TelegramToken() string {
 	return o.telegram.token
@@ -930,6 +1095,27 @@ func (o *Options) ListenAddr() string {
 	return o.listenAddr
 }
 
+// PublicURL returns the public URL of the HTTP server without trailing slash,
+// it is used to build links of the resources served by the HTTP server, e.g.
+// local captures. Defaults to the listen address if not specified.
+func (o *Options) PublicURL() string {
+	if o.publicURL != "" {
+		return strings.TrimSuffix(o.publicURL, "/")
+	}
+
+	addr := o.listenAddr
+	if strings.HasPrefix(addr, "0.0.0.0:") {
+		addr = "127.0.0.1" + strings.TrimPrefix(addr, "0.0.0.0")
+	}
+	return "http://" + addr
+}
+
+// EnabledWebAuth returns whether the archive, playback and API requests
+// of the HTTP server require API tokens.
+func (o *Options) EnabledWebAuth() bool {
+	return o.webAuth
+}
+
 // EnabledChromeRemote returns whether enable Chrome/Chromium remote debugging
 // for screenshot
 func (o *Options) EnabledChromeRemote() bool {
@@ -951,6 +1137,73 @@ func (o *Options) PoolingSize() int {
 	return o.poolingSize
 }
 
+// PoolingSourceLimit returns the maximum number of requests from a single
+// source processed by the worker pool at once, zero means unlimited.
+func (o *Options) PoolingSourceLimit() int {
+	return o.poolingSourceLimit
+}
+
+// PoolingSourceLimits returns the maximum number of requests from a single
+// source of the services processed by the worker pool at once, which are
+// configured as comma separated service=limit pairs, e.g. telegram=1,web=4.
+func (o *Options) PoolingSourceLimits() map[string]int {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(o.poolingSourceLimits, ",") {
+		service, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		service = strings.ToLower(strings.TrimSpace(service))
+		if !ok || service == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 0 {
+			logger.Warn("invalid pooling source limit %s of %s", limit, service)
+			continue
+		}
+		limits[service] = n
+	}
+	return limits
+}
+
+// PoolingPriorities returns the priorities of the requests of the services
+// in the worker pool, which are configured as comma separated
+// service=priority pairs, the priority is one of high, normal and low, e.g.
+// web=high,schedule=low.
+func (o *Options) PoolingPriorities() map[string]string {
+	priorities := make(map[string]string)
+	for _, pair := range strings.Split(o.poolingPriorities, ",") {
+		service, priority, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		service = strings.ToLower(strings.TrimSpace(service))
+		priority = strings.ToLower(strings.TrimSpace(priority))
+		if !ok || service == "" {
+			continue
+		}
+		if priority != "high" && priority != "normal" && priority != "low" {
+			logger.Warn("invalid pooling priority %s of %s", priority, service)
+			continue
+		}
+		priorities[service] = priority
+	}
+	return priorities
+}
+
+// RateLimitUser returns the number of archiving requests per minute allowed
+// from a single user or IP address, zero means unlimited.
+func (o *Options) RateLimitUser() int {
+	return o.rateLimitUser
+}
+
+// RateLimitService returns the number of archiving requests per minute allowed
+// from a single service, zero means unlimited.
+func (o *Options) RateLimitService() int {
+	return o.rateLimitService
+}
+
+// RateLimitBurst returns the number of archiving requests allowed at once
+// before the rate limit of a user applies.
+func (o *Options) RateLimitBurst() int {
+	return o.rateLimitBurst
+}
+
 // StorageDir returns the directory to storage binary file, e.g. html file, PDF
 func (o *Options) StorageDir() string {
 	return o.storageDir
@@ -961,6 +1214,18 @@ func (o *Options) EnabledReduxer() bool {
 	return o.StorageDir() != ""
 }
 
+// StorageDedup returns whether to store the files of the storage directory
+// by their digests, so the same content is stored once.
+func (o *Options) StorageDedup() bool {
+	return o.storageDedup
+}
+
+// Artifacts returns the profile of the artifacts produced by reduxer, which
+// is minimal, full or a comma-separated list of the kinds.
+func (o *Options) Artifacts() string {
+	return o.artifacts
+}
+
 // MaxMediaSize returns max size to limit download stream media.
 func (o *Options) MaxMediaSize() uint64 {
 	size, err := humanize.ParseBytes(o.maxMediaSize)
@@ -1006,6 +1271,27 @@ func (o *Options) WaybackTimeout() time.Duration {
 	return time.Duration(o.waybackTimeout) * time.Second
 }
 
+// FreshnessWindow returns the duration within which the recent captures of
+// a URL are reused instead of archiving it again, zero means disabled.
+func (o *Options) FreshnessWindow() time.Duration {
+	return time.Duration(o.freshnessWindow) * time.Second
+}
+
+// ChangeDetection returns whether to compare the captures with the previous
+// captures of the same URL and publish the changed captures only.
+func (o *Options) ChangeDetection() bool {
+	return o.changeDetection
+}
+
+// ChangeThreshold returns the ratio of the changed pixels of the screenshots
+// above which a capture is changed visually.
+func (o *Options) ChangeThreshold() float64 {
+	if o.changeThreshold < 0 {
+		return 0
+	}
+	return float64(o.changeThreshold) / 100
+}
+
 // WaybackMaxRetries returns max retries for a wayback request.
 func (o *Options) WaybackMaxRetries() uint64 {
 	s := strconv.Itoa(o.waybackMaxRetries)
@@ -1047,6 +1333,170 @@ func (o *Options) EnabledMeilisearch() bool {
 	return o.MeiliEndpoint() != ""
 }
 
+// RetentionMaxAge returns the age above which the files of the storage
+// directory are removed, zero means unlimited.
+func (o *Options) RetentionMaxAge() time.Duration {
+	if o.retention.maxAge < 0 {
+		return 0
+	}
+	return time.Duration(o.retention.maxAge) * 24 * time.Hour
+}
+
+// RetentionMaxSize returns the max total size of the files of the storage
+// directory, the oldest files are removed above it, zero means unlimited.
+func (o *Options) RetentionMaxSize() uint64 {
+	size, err := humanize.ParseBytes(o.retention.maxSize)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// RetentionKinds returns the retention limits of the kinds of the artifacts,
+// which are configured as comma separated kind=limit pairs, the limit is
+// either a max age in days, e.g. 7d, or a max total size, e.g. 1GB.
+func (o *Options) RetentionKinds() map[string]RetentionLimit {
+	kinds := make(map[string]RetentionLimit)
+	for _, pair := range strings.Split(o.retention.kinds, ",") {
+		kind, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		kind, limit = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(limit)
+		if !ok || kind == "" || limit == "" {
+			continue
+		}
+		l := kinds[kind]
+		if days, found := strings.CutSuffix(limit, "d"); found {
+			n, err := strconv.Atoi(days)
+			if err != nil || n <= 0 {
+				logger.Warn("invalid retention age %s of %s", limit, kind)
+				continue
+			}
+			l.MaxAge = time.Duration(n) * 24 * time.Hour
+		} else {
+			size, err := humanize.ParseBytes(limit)
+			if err != nil || size == 0 {
+				logger.Warn("invalid retention size %s of %s", limit, kind)
+				continue
+			}
+			l.MaxSize = size
+		}
+		kinds[kind] = l
+	}
+	return kinds
+}
+
+// RetentionKeepLatest returns the number of the latest captures of a URL
+// whose files are kept, zero means unlimited.
+func (o *Options) RetentionKeepLatest() int {
+	if o.retention.keepLatest < 0 {
+		return 0
+	}
+	return o.retention.keepLatest
+}
+
+// RetentionInterval returns the interval of applying the retention rules.
+func (o *Options) RetentionInterval() time.Duration {
+	if o.retention.interval <= 0 {
+		return time.Duration(defRetentionInterval) * time.Second
+	}
+	return time.Duration(o.retention.interval) * time.Second
+}
+
+// RetentionDryRun returns whether to report the files matched by the
+// retention rules without removing them.
+func (o *Options) RetentionDryRun() bool {
+	return o.retention.dryRun
+}
+
+// EnabledRetention returns whether any retention rule is configured.
+func (o *Options) EnabledRetention() bool {
+	return o.RetentionMaxAge() > 0 || o.RetentionMaxSize() > 0 || len(o.RetentionKinds()) > 0 || o.RetentionKeepLatest() > 0
+}
+
+// ArtifactStores returns the kinds of the stores the artifacts are uploaded
+// to, which are catbox, s3, webdav and cas, or none to keep them local only.
+func (o *Options) ArtifactStores() []string {
+	stores := []string{}
+	for _, kind := range strings.Split(o.artifactStores, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" || kind == "none" || slices.Contains(stores, kind) {
+			continue
+		}
+		stores = append(stores, kind)
+	}
+	return stores
+}
+
+// S3Endpoint returns the endpoint of the S3-compatible object storage.
+func (o *Options) S3Endpoint() string {
+	return o.s3.endpoint
+}
+
+// S3Region returns the region of the S3-compatible object storage.
+func (o *Options) S3Region() string {
+	return o.s3.region
+}
+
+// S3Bucket returns the bucket of the S3-compatible object storage.
+func (o *Options) S3Bucket() string {
+	return o.s3.bucket
+}
+
+// S3AccessKey returns the access key of the S3-compatible object storage.
+func (o *Options) S3AccessKey() string {
+	return o.s3.accessKey
+}
+
+// S3SecretKey returns the secret key of the S3-compatible object storage.
+func (o *Options) S3SecretKey() string {
+	return o.s3.secretKey
+}
+
+// S3PublicURL returns the public URL of the bucket, the artifacts uploaded
+// are linked under it instead of the endpoint if set.
+func (o *Options) S3PublicURL() string {
+	return o.s3.publicURL
+}
+
+// EnabledS3 returns whether the S3-compatible object storage is configured.
+func (o *Options) EnabledS3() bool {
+	return o.S3Endpoint() != "" && o.S3Bucket() != ""
+}
+
+// WebDAVURL returns the URL of the WebDAV collection to upload artifacts.
+func (o *Options) WebDAVURL() string {
+	return o.webdav.url
+}
+
+// WebDAVUsername returns the username of the WebDAV server.
+func (o *Options) WebDAVUsername() string {
+	return o.webdav.username
+}
+
+// WebDAVPassword returns the password of the WebDAV server.
+func (o *Options) WebDAVPassword() string {
+	return o.webdav.password
+}
+
+// EnabledWebDAV returns whether the WebDAV server is configured.
+func (o *Options) EnabledWebDAV() bool {
+	return o.WebDAVURL() != ""
+}
+
+// CASDir returns the directory of the local content-addressed store, which
+// defaults to the cas directory under the storage directory.
+func (o *Options) CASDir() string {
+	if o.cas.dir == "" {
+		return path.Join(o.StorageDir(), "cas")
+	}
+	return o.cas.dir
+}
+
+// CASPublicURL returns the public URL the content-addressed store is served
+// under, the artifacts are linked with file URLs if not set.
+func (o *Options) CASPublicURL() string {
+	return o.cas.publicURL
+}
+
 // OmnivoreApikey returns the Omnivore apikey.
 func (o *Options) OmnivoreApikey() string {
 	return o.omnivore.apikey
@@ -90,6 +90,10 @@ func (p *Parser) parseLines(lines []string) (err error) {
 			p.opts.metrics = parseBool(val, defMetrics)
 		case "HTTP_LISTEN_ADDR", "WAYBACK_LISTEN_ADDR":
 			p.opts.listenAddr = parseString(val, defListenAddr)
+		case "WAYBACK_PUBLIC_URL":
+			p.opts.publicURL = parseString(val, defPublicURL)
+		case "WAYBACK_WEB_AUTH":
+			p.opts.webAuth = parseBool(val, defWebAuth)
 		case "CHROME_REMOTE_ADDR":
 			p.opts.enabledChromeRemote = hasValue(val, defEnabledChromeRemote)
 			p.opts.chromeRemoteAddr = parseString(val, defChromeRemoteAddr)
@@ -117,16 +121,6 @@ func (p *Parser) parseLines(lines []string) (err error) {
 			p.opts.ipfs.secret = parseString(val, defIPFSSecret)
 		case "WAYBACK_USE_TOR":
 			p.opts.overTor = parseBool(val, defOverTor)
-		case "WAYBACK_ENABLE_IA":
-			p.opts.slots[SLOT_IA] = parseBool(val, defEnabledIA)
-		case "WAYBACK_ENABLE_IS":
-			p.opts.slots[SLOT_IS] = parseBool(val, defEnabledIS)
-		case "WAYBACK_ENABLE_IP":
-			p.opts.slots[SLOT_IP] = parseBool(val, defEnabledIP)
-		case "WAYBACK_ENABLE_PH":
-			p.opts.slots[SLOT_PH] = parseBool(val, defEnabledPH)
-		case "WAYBACK_ENABLE_GA":
-			p.opts.slots[SLOT_GA] = parseBool(val, defEnabledGA)
 		case "WAYBACK_TELEGRAM_TOKEN":
 			p.opts.telegram.token = parseString(val, defTelegramToken)
 		case "WAYBACK_TELEGRAM_CHANNEL":
@@ -217,14 +211,48 @@ func (p *Parser) parseLines(lines []string) (err error) {
 			p.opts.onion.disabled = parseBool(val, defOnionDisabled)
 		case "WAYBACK_POOLING_SIZE":
 			p.opts.poolingSize = parseInt(val, defPoolingSize)
+		case "WAYBACK_POOLING_SOURCE_LIMIT":
+			p.opts.poolingSourceLimit = parseInt(val, defPoolingSourceLimit)
+		case "WAYBACK_POOLING_SOURCE_LIMITS":
+			p.opts.poolingSourceLimits = parseString(val, defPoolingSourceLimits)
+		case "WAYBACK_POOLING_PRIORITIES":
+			p.opts.poolingPriorities = parseString(val, defPoolingPriorities)
+		case "WAYBACK_RATE_LIMIT_USER":
+			p.opts.rateLimitUser = parseInt(val, defRateLimitUser)
+		case "WAYBACK_RATE_LIMIT_SERVICE":
+			p.opts.rateLimitService = parseInt(val, defRateLimitService)
+		case "WAYBACK_RATE_LIMIT_BURST":
+			p.opts.rateLimitBurst = parseInt(val, defRateLimitBurst)
 		case "WAYBACK_BOLT_PATH":
 			p.opts.boltPathname = parseString(val, defBoltPathname)
 		case "WAYBACK_STORAGE_DIR":
 			p.opts.storageDir = parseString(val, defStorageDir)
+		case "WAYBACK_STORAGE_DEDUP":
+			p.opts.storageDedup = parseBool(val, defStorageDedup)
+		case "WAYBACK_ARTIFACTS":
+			p.opts.artifacts = parseString(val, defArtifacts)
+		case "WAYBACK_RETENTION_MAX_AGE":
+			p.opts.retention.maxAge = parseInt(val, defRetentionMaxAge)
+		case "WAYBACK_RETENTION_MAX_SIZE":
+			p.opts.retention.maxSize = parseString(val, defRetentionMaxSize)
+		case "WAYBACK_RETENTION_KINDS":
+			p.opts.retention.kinds = parseString(val, defRetentionKinds)
+		case "WAYBACK_RETENTION_KEEP_LATEST":
+			p.opts.retention.keepLatest = parseInt(val, defRetentionKeepLatest)
+		case "WAYBACK_RETENTION_INTERVAL":
+			p.opts.retention.interval = parseInt(val, defRetentionInterval)
+		case "WAYBACK_RETENTION_DRY_RUN":
+			p.opts.retention.dryRun = parseBool(val, defRetentionDryRun)
 		case "WAYBACK_MAX_MEDIA_SIZE":
 			p.opts.maxMediaSize = parseString(val, defMaxMediaSize)
 		case "WAYBACK_TIMEOUT":
 			p.opts.waybackTimeout = parseInt(val, defWaybackTimeout)
+		case "WAYBACK_FRESHNESS_WINDOW":
+			p.opts.freshnessWindow = parseInt(val, defFreshnessWindow)
+		case "WAYBACK_CHANGE_DETECTION":
+			p.opts.changeDetection = parseBool(val, defChangeDetection)
+		case "WAYBACK_CHANGE_THRESHOLD":
+			p.opts.changeThreshold = parseInt(val, defChangeThreshold)
 		case "WAYBACK_MAX_RETRIES":
 			p.opts.waybackMaxRetries = parseInt(val, defWaybackMaxRetries)
 		case "WAYBACK_USERAGENT":
@@ -237,6 +265,30 @@ func (p *Parser) parseLines(lines []string) (err error) {
 			p.opts.meili.indexing = parseString(val, defMeiliIndexing)
 		case "WAYBACK_MEILI_APIKEY":
 			p.opts.meili.apikey = parseString(val, defMeiliApikey)
+		case "WAYBACK_ARTIFACT_STORES":
+			p.opts.artifactStores = parseString(val, defArtifactStores)
+		case "WAYBACK_S3_ENDPOINT":
+			p.opts.s3.endpoint = parseString(val, defS3Endpoint)
+		case "WAYBACK_S3_REGION":
+			p.opts.s3.region = parseString(val, defS3Region)
+		case "WAYBACK_S3_BUCKET":
+			p.opts.s3.bucket = parseString(val, defS3Bucket)
+		case "WAYBACK_S3_ACCESS_KEY":
+			p.opts.s3.accessKey = parseString(val, defS3AccessKey)
+		case "WAYBACK_S3_SECRET_KEY":
+			p.opts.s3.secretKey = parseString(val, defS3SecretKey)
+		case "WAYBACK_S3_PUBLIC_URL":
+			p.opts.s3.publicURL = parseString(val, defS3PublicURL)
+		case "WAYBACK_WEBDAV_URL":
+			p.opts.webdav.url = parseString(val, defWebDAVURL)
+		case "WAYBACK_WEBDAV_USERNAME":
+			p.opts.webdav.username = parseString(val, defWebDAVUsername)
+		case "WAYBACK_WEBDAV_PASSWORD":
+			p.opts.webdav.password = parseString(val, defWebDAVPassword)
+		case "WAYBACK_CAS_DIR":
+			p.opts.cas.dir = parseString(val, defCASDir)
+		case "WAYBACK_CAS_PUBLIC_URL":
+			p.opts.cas.publicURL = parseString(val, defCASPublicURL)
 		case "WAYBACK_LLM_PROVIDER":
 			p.opts.llm.provider = parseString(val, defLLMProvider)
 		case "WAYBACK_LLM_BASE_URL":
@@ -250,6 +302,13 @@ func (p *Parser) parseLines(lines []string) (err error) {
 		case "WAYBACK_PRIVACY_URL":
 			p.opts.privacyURL = parseString(val, defPrivacyURL)
 		default:
+			if slot, ok := lookupSlotByEnv(strings.ToUpper(key)); ok {
+				p.opts.slots[slot.Name] = parseBool(val, slot.Enabled)
+				continue
+			}
+			if p.parseSlotPolicy(key, val) {
+				continue
+			}
 			if os.Getenv(key) == "" && val != "" {
 				os.Setenv(key, val)
 			}
@@ -0,0 +1,194 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package config // import "github.com/wabarc/wayback/config"
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Slot describes an archive slot, it holds the identifier, the
+// descriptions and the environment variable used to toggle it.
+type Slot struct {
+	Name    string // Identifier of the slot, e.g. ia
+	Title   string // Human readable name of the slot, e.g. Internet Archive
+	Extra   string // Extra data of the slot, e.g. homepage of the archive service
+	Env     string // Environment variable to toggle the slot, empty for playback only slots
+	Enabled bool   // Whether the slot is enabled by default
+}
+
+// SlotPolicy represents the policy of an archive slot.
+type SlotPolicy struct {
+	// Timeout is the deadline of each attempt, zero means it derives
+	// from the deadline of the wayback request.
+	Timeout time.Duration
+
+	// MaxRetries is the number of retries after the first attempt failed.
+	MaxRetries int
+
+	// Backoff is the base delay between attempts, it doubles for each retry.
+	Backoff time.Duration
+
+	// BreakerThreshold is the number of consecutive failures that opens
+	// the circuit breaker of the slot, zero means the breaker is disabled.
+	BreakerThreshold int
+
+	// BreakerCooldown is the duration the slot is skipped once the
+	// circuit breaker is open.
+	BreakerCooldown time.Duration
+}
+
+// slotPolicy holds the parsed policy values in seconds, negative values
+// mean not configured.
+type slotPolicy struct {
+	timeout   int
+	retries   int
+	backoff   int
+	threshold int
+	cooldown  int
+}
+
+func newSlotPolicy() *slotPolicy {
+	return &slotPolicy{timeout: -1, retries: -1, backoff: -1, threshold: -1, cooldown: -1}
+}
+
+var (
+	slots = map[string]Slot{
+		SLOT_IA: {Name: SLOT_IA, Title: "Internet Archive", Extra: "https://web.archive.org/", Env: "WAYBACK_ENABLE_IA", Enabled: defEnabledIA},
+		SLOT_IS: {Name: SLOT_IS, Title: "archive.today", Extra: "https://archive.today/", Env: "WAYBACK_ENABLE_IS", Enabled: defEnabledIS},
+		SLOT_IP: {Name: SLOT_IP, Title: "IPFS", Extra: "https://ipfs.github.io/public-gateway-checker/", Env: "WAYBACK_ENABLE_IP", Enabled: defEnabledIP},
+		SLOT_PH: {Name: SLOT_PH, Title: "Telegraph", Extra: "https://telegra.ph/", Env: "WAYBACK_ENABLE_PH", Enabled: defEnabledPH},
+		SLOT_GA: {Name: SLOT_GA, Title: "Ghost Archive", Extra: "https://ghostarchive.org/", Env: "WAYBACK_ENABLE_GA", Enabled: defEnabledGA},
+		SLOT_LC: {Name: SLOT_LC, Title: "Local Capture", Extra: "https://github.com/wabarc/wayback", Env: "WAYBACK_ENABLE_LC", Enabled: defEnabledLC},
+		SLOT_TT: {Name: SLOT_TT, Title: "Time Travel", Extra: "http://timetravel.mementoweb.org/"},
+	}
+	slotMu sync.RWMutex
+)
+
+// RegisterSlot registers the metadata of an archive slot, it must be
+// called before the configuration options are parsed, typically in an
+// init function of the package that implements the slot.
+func RegisterSlot(s Slot) {
+	if s.Name == "" {
+		panic("slot name is required")
+	}
+
+	slotMu.Lock()
+	defer slotMu.Unlock()
+
+	if _, exists := slots[s.Name]; exists {
+		panic(fmt.Sprintf("slot %s registered", s.Name))
+	}
+	slots[s.Name] = s
+}
+
+// UnregisterSlot removes the metadata of an archive slot registered by
+// RegisterSlot, it is a no-op if the slot is not registered.
+func UnregisterSlot(name string) {
+	slotMu.Lock()
+	defer slotMu.Unlock()
+
+	delete(slots, name)
+}
+
+// LookupSlot returns the metadata of the given slot name.
+func LookupSlot(name string) (Slot, bool) {
+	slotMu.RLock()
+	defer slotMu.RUnlock()
+
+	s, ok := slots[name]
+	return s, ok
+}
+
+// RegisteredSlots returns the metadata of all registered slots sorted by name.
+func RegisteredSlots() []Slot {
+	slotMu.RLock()
+	defer slotMu.RUnlock()
+
+	list := make([]Slot, 0, len(slots))
+	for _, s := range slots {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func lookupSlotByEnv(key string) (Slot, bool) {
+	slotMu.RLock()
+	defer slotMu.RUnlock()
+
+	for _, s := range slots {
+		if s.Env != "" && s.Env == key {
+			return s, true
+		}
+	}
+	return Slot{}, false
+}
+
+func defaultSlots() map[string]bool {
+	toggles := make(map[string]bool)
+	for _, s := range RegisteredSlots() {
+		if s.Env == "" {
+			continue
+		}
+		toggles[s.Name] = s.Enabled
+	}
+	return toggles
+}
+
+// parseSlotPolicy parses policy variables of slots, the key is either
+// WAYBACK_SLOT_<POLICY> for all slots or WAYBACK_<SLOT>_<POLICY> for
+// specific slot, e.g. WAYBACK_IA_TIMEOUT. It reports whether the key
+// is a policy variable.
+func (p *Parser) parseSlotPolicy(key, val string) bool {
+	key = strings.ToUpper(key)
+	if !strings.HasPrefix(key, "WAYBACK_") {
+		return false
+	}
+
+	suffixes := []string{"_TIMEOUT", "_MAX_RETRIES", "_BACKOFF", "_BREAKER_THRESHOLD", "_BREAKER_COOLDOWN"}
+	for _, suffix := range suffixes {
+		if !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(key, "WAYBACK_"), suffix))
+
+		var policy *slotPolicy
+		switch _, known := LookupSlot(name); {
+		case name == "slot":
+			policy = p.opts.slotPolicy
+		case known:
+			policy = p.opts.slotPolicies[name]
+			if policy == nil {
+				policy = newSlotPolicy()
+				p.opts.slotPolicies[name] = policy
+			}
+		default:
+			return false
+		}
+
+		switch suffix {
+		case "_TIMEOUT":
+			policy.timeout = parseInt(val, defSlotTimeout)
+		case "_MAX_RETRIES":
+			policy.retries = parseInt(val, defSlotMaxRetries)
+		case "_BACKOFF":
+			policy.backoff = parseInt(val, defSlotBackoff)
+		case "_BREAKER_THRESHOLD":
+			policy.threshold = parseInt(val, defSlotBreakerThreshold)
+		case "_BREAKER_COOLDOWN":
+			policy.cooldown = parseInt(val, defSlotBreakerCooldown)
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package entity // import "github.com/wabarc/entity"
+
+import "time"
+
+// EntityJob represents a keyword for job entity.
+const EntityJob = "job"
+
+// JobState represents the lifecycle state of a job.
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"    // JobQueued represents the job is waiting to be processed
+	JobRunning   JobState = "running"   // JobRunning represents the job is being processed
+	JobRetrying  JobState = "retrying"  // JobRetrying represents the job is being processed after failures
+	JobDone      JobState = "done"      // JobDone represents the job is processed successfully
+	JobFailed    JobState = "failed"    // JobFailed represents the job is failed after retries
+	JobCancelled JobState = "cancelled" // JobCancelled represents the job is cancelled
+)
+
+// Job represents a persisted wayback request of the worker pool.
+type Job struct {
+	ID      uint64    `json:"id"`
+	Kind    string    `json:"kind"`
+	Payload []byte    `json:"payload"`
+	State   JobState  `json:"state"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// Finished reports whether the job is finished.
+func (j *Job) Finished() bool {
+	return j.State.Finished()
+}
+
+// Finished reports whether the state is final.
+func (s JobState) Finished() bool {
+	return s == JobDone || s == JobFailed || s == JobCancelled
+}
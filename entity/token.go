@@ -0,0 +1,64 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package entity // import "github.com/wabarc/entity"
+
+import (
+	"fmt"
+	"time"
+)
+
+// EntityToken represents a keyword for token entity.
+const EntityToken = "token"
+
+// Scope represents a permission granted to a token.
+type Scope string
+
+const (
+	ScopeArchive     Scope = "archive"      // ScopeArchive allows to archive URLs and query the jobs
+	ScopePlayback    Scope = "playback"     // ScopePlayback allows to search the archived URLs
+	ScopeReadHistory Scope = "read-history" // ScopeReadHistory allows to read the archiving history
+	ScopeAdmin       Scope = "admin"        // ScopeAdmin allows everything, including managing tokens
+)
+
+// Scopes holds all the scopes.
+var Scopes = []Scope{ScopeArchive, ScopePlayback, ScopeReadHistory, ScopeAdmin}
+
+// ParseScope returns the Scope of the given name.
+func ParseScope(s string) (Scope, error) {
+	for _, scope := range Scopes {
+		if string(scope) == s {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("unknown scope %q", s)
+}
+
+// Token represents an API token of the web service, the secret of the
+// token is not stored but its hash.
+type Token struct {
+	ID       uint64           `json:"id"`
+	Name     string           `json:"name"`
+	Hash     string           `json:"hash"`
+	Scopes   []Scope          `json:"scopes"`
+	Usage    map[Scope]uint64 `json:"usage"`
+	Created  time.Time        `json:"created"`
+	Expires  time.Time        `json:"expires"`   // Zero means the token never expires
+	LastUsed time.Time        `json:"last_used"` // Zero means the token has not been used
+}
+
+// Allows reports whether the token is granted the scope.
+func (t *Token) Allows(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the token is expired at the given time.
+func (t *Token) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
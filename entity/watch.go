@@ -0,0 +1,27 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package entity // import "github.com/wabarc/entity"
+
+import "time"
+
+// EntityWatch represents a keyword for watch entity.
+const EntityWatch = "watch"
+
+// EntityFeed represents a keyword for the seen items of the feeds.
+const EntityFeed = "feed"
+
+// Watch represents a URL archived periodically on a schedule.
+type Watch struct {
+	ID        uint64    `json:"id"`
+	URL       string    `json:"url"`
+	Schedule  string    `json:"schedule"` // Cron expression or descriptor, e.g. @daily
+	Owner     string    `json:"owner"`    // Creator of the watch, e.g. telegram:<chat id>, empty for the command line
+	Feed      bool      `json:"feed"`     // Whether the URL is a sitemap, feed or OPML list whose new items are archived
+	Created   time.Time `json:"created"`
+	NextRun   time.Time `json:"next_run"`
+	LastRun   time.Time `json:"last_run"` // Zero means the watch has not run
+	LastError string    `json:"last_error,omitempty"`
+	Runs      uint64    `json:"runs"`
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package entity // import "github.com/wabarc/entity"
+
+import "time"
+
+// Wayback represents an archiving request of a source URL stored in the database.
+type Wayback struct {
+	ID       int64     `json:"id"`
+	Source   string    `json:"source"`
+	Domain   string    `json:"domain"`
+	Created  time.Time `json:"created"`
+	Archives []Archive `json:"archives"`
+
+	// Page and Artifacts are only loaded for a single Wayback.
+	Page      *Page      `json:"page,omitempty"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+}
+
+// Archive represents the archiving result of a slot for a Wayback.
+type Archive struct {
+	Slot     string `json:"slot"`
+	Dest     string `json:"dest,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Elapsed  int64  `json:"elapsed"` // Elapsed time in milliseconds
+	Attempts int    `json:"attempts"`
+}
+
+// Page represents the metadata of the webpage extracted by reduxer.
+type Page struct {
+	Title    string `json:"title"`
+	Byline   string `json:"byline,omitempty"`
+	Excerpt  string `json:"excerpt,omitempty"`
+	SiteName string `json:"site_name,omitempty"`
+	Summary  string `json:"summary,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// Artifact represents a file produced by reduxer for a Wayback.
+//
+// Remote is the URL of the first location of the file on the artifact stores.
+type Artifact struct {
+	Kind      string     `json:"kind"` // One of img, pdf, raw, txt, har, htm, warc, media, diff and diff-img
+	Path      string     `json:"path,omitempty"`
+	Remote    string     `json:"remote,omitempty"`
+	Locations []Location `json:"locations,omitempty"`
+	Size      int64      `json:"size"`
+	SHA256    string     `json:"sha256,omitempty"`
+}
+
+// Location represents the location of an artifact on an artifact store.
+type Location struct {
+	Store string `json:"store"` // One of catbox, s3, webdav and cas
+	URL   string `json:"url"`
+}
+
+// SearchHit represents a Wayback whose page matched the full-text search.
+type SearchHit struct {
+	Wayback
+	Snippet string `json:"snippet,omitempty"` // Part of the text around the matched words
+}
@@ -0,0 +1,10 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package feed expands the sitemaps, RSS and Atom feeds and OPML lists into the
+URLs of their pages, the nested sitemaps of a sitemap index and the feeds of
+an OPML list are expanded too.
+*/
+package feed // import "github.com/wabarc/wayback/feed"
@@ -0,0 +1,287 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package feed // import "github.com/wabarc/wayback/feed"
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"golang.org/x/net/html/charset"
+)
+
+// Kinds of the documents.
+const (
+	KindSitemap      = "sitemap"
+	KindSitemapIndex = "sitemapindex"
+	KindRSS          = "rss"
+	KindAtom         = "atom"
+	KindOPML         = "opml"
+)
+
+// maxDepth is the maximum depth of the nested documents, e.g. the sitemaps
+// of a sitemap index, or the feeds of an OPML list.
+const maxDepth = 2
+
+// maxSize is the maximum size of a document, which is the limit of the
+// uncompressed sitemaps.
+const maxSize = 50 << 20
+
+// Document represents a sitemap, feed or OPML list.
+type Document struct {
+	Kind string
+
+	// Items are the URLs of the pages, e.g. the posts of a feed.
+	Items []string
+
+	// Links are the URLs of the nested documents, e.g. the sitemaps of a
+	// sitemap index or the feeds of an OPML list.
+	Links []string
+}
+
+type loc struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemap struct {
+	URLs []loc `xml:"url"`
+}
+
+type sitemapIndex struct {
+	Sitemaps []loc `xml:"sitemap"`
+}
+
+type rssItem struct {
+	Link string `xml:"link"`
+	GUID struct {
+		Value     string `xml:",chardata"`
+		Permalink string `xml:"isPermaLink,attr"`
+	} `xml:"guid"`
+}
+
+// rss represents the RSS 2.0 feeds and the RSS 1.0 feeds, whose items are
+// the children of the root element.
+type rss struct {
+	Channel []rssItem `xml:"channel>item"`
+	Items   []rssItem `xml:"item"`
+}
+
+type atom struct {
+	Entries []struct {
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+type outline struct {
+	XMLURL   string    `xml:"xmlUrl,attr"`
+	URL      string    `xml:"url,attr"`
+	Outlines []outline `xml:"outline"`
+}
+
+type opml struct {
+	Outlines []outline `xml:"body>outline"`
+}
+
+// Parse parses the sitemap, feed or OPML list, which may be compressed by
+// gzip. The URLs of the document are not resolved.
+func Parse(r io.Reader) (*Document, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	d := xml.NewDecoder(io.LimitReader(br, maxSize))
+	d.CharsetReader = charset.NewReaderLabel
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	var start xml.StartElement
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("root element not found: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			start = se
+			break
+		}
+	}
+
+	doc := &Document{}
+	switch strings.ToLower(start.Name.Local) {
+	case "urlset":
+		var v sitemap
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return nil, err
+		}
+		doc.Kind = KindSitemap
+		for _, u := range v.URLs {
+			doc.Items = append(doc.Items, u.Loc)
+		}
+	case "sitemapindex":
+		var v sitemapIndex
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return nil, err
+		}
+		doc.Kind = KindSitemapIndex
+		for _, s := range v.Sitemaps {
+			doc.Links = append(doc.Links, s.Loc)
+		}
+	case "rss", "rdf":
+		var v rss
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return nil, err
+		}
+		doc.Kind = KindRSS
+		for _, item := range append(v.Channel, v.Items...) {
+			link := item.Link
+			if link == "" && !strings.EqualFold(item.GUID.Permalink, "false") {
+				link = item.GUID.Value
+			}
+			doc.Items = append(doc.Items, link)
+		}
+	case "feed":
+		var v atom
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return nil, err
+		}
+		doc.Kind = KindAtom
+		for _, entry := range v.Entries {
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					doc.Items = append(doc.Items, link.Href)
+					break
+				}
+			}
+		}
+	case "opml":
+		var v opml
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return nil, err
+		}
+		doc.Kind = KindOPML
+		doc.walk(v.Outlines)
+	default:
+		return nil, fmt.Errorf("unsupported document <%s>", start.Name.Local)
+	}
+
+	return doc, nil
+}
+
+// walk collects the feeds and the links of the outlines of an OPML list.
+func (doc *Document) walk(outlines []outline) {
+	for _, o := range outlines {
+		switch {
+		case o.XMLURL != "":
+			doc.Links = append(doc.Links, o.XMLURL)
+		case o.URL != "":
+			doc.Items = append(doc.Items, o.URL)
+		}
+		doc.walk(o.Outlines)
+	}
+}
+
+// Expand fetches the document of the URL by the client and returns the URLs
+// of its pages and the pages of its nested documents in order, the URLs are
+// resolved against the documents and deduplicated. The nested documents
+// failed to fetch are skipped.
+func Expand(ctx context.Context, client *http.Client, rawURL string) ([]string, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	e := &expander{client: client, seen: make(map[string]bool), visited: make(map[string]bool)}
+	if err = e.expand(ctx, base, 0); err != nil {
+		return nil, err
+	}
+	return e.items, nil
+}
+
+type expander struct {
+	client  *http.Client
+	items   []string
+	seen    map[string]bool
+	visited map[string]bool
+}
+
+func (e *expander) expand(ctx context.Context, u *url.URL, depth int) error {
+	e.visited[u.String()] = true
+	doc, err := fetch(ctx, e.client, u.String())
+	if err != nil {
+		return err
+	}
+
+	for _, item := range doc.Items {
+		if ref := resolve(u, item); ref != "" && !e.seen[ref] {
+			e.seen[ref] = true
+			e.items = append(e.items, ref)
+		}
+	}
+	if depth >= maxDepth {
+		return nil
+	}
+	for _, link := range doc.Links {
+		ref := resolve(u, link)
+		if ref == "" || e.visited[ref] {
+			continue
+		}
+		next, _ := url.Parse(ref)
+		if err = e.expand(ctx, next, depth+1); err != nil {
+			logger.Warn("expand %s of %s failed: %v", ref, u, err)
+		}
+	}
+	return nil
+}
+
+func fetch(ctx context.Context, client *http.Client, rawURL string) (*Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s failed: %s", rawURL, resp.Status)
+	}
+	return Parse(resp.Body)
+}
+
+// resolve returns the absolute URL of the reference, or an empty string if
+// it is not an http or https URL.
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	u = base.ResolveReference(u)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package feed // import "github.com/wabarc/wayback/feed"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	sitemapXML = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc>https://example.com/about</loc><lastmod>2025-01-01</lastmod></url>
+</urlset>`
+
+	sitemapIndexXML = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>/sitemap-posts.xml</loc></sitemap>
+  <sitemap><loc>/missing.xml</loc></sitemap>
+</sitemapindex>`
+
+	rssXML = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<rss version="2.0"><channel>
+  <title>Caf&eacute; blog</title>
+  <item><title>First</title><link>https://example.com/posts/1</link></item>
+  <item><title>Second</title><guid>https://example.com/posts/2</guid></item>
+  <item><title>Third</title><guid isPermaLink="false">urn:uuid:3</guid></item>
+</channel></rss>`
+
+	rdfXML = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+  <channel><title>Example</title></channel>
+  <item><link>https://example.com/posts/1</link></item>
+</rdf:RDF>`
+
+	atomXML = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <link rel="replies" href="https://example.com/posts/1#comments"/>
+    <link href="https://example.com/posts/1"/>
+  </entry>
+  <entry><link rel="alternate" href="/posts/2"/></entry>
+</feed>`
+
+	opmlXML = `<?xml version="1.0"?>
+<opml version="2.0"><body>
+  <outline text="Blogs">
+    <outline type="rss" text="Example" xmlUrl="/feed.xml"/>
+  </outline>
+  <outline type="link" text="News" url="https://example.org/news"/>
+</body></opml>`
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		kind  string
+		items []string
+		links []string
+	}{
+		{"sitemap", sitemapXML, KindSitemap, []string{"https://example.com/", "https://example.com/about"}, nil},
+		{"sitemapindex", sitemapIndexXML, KindSitemapIndex, nil, []string{"/sitemap-posts.xml", "/missing.xml"}},
+		{"rss", rssXML, KindRSS, []string{"https://example.com/posts/1", "https://example.com/posts/2", ""}, nil},
+		{"rdf", rdfXML, KindRSS, []string{"https://example.com/posts/1"}, nil},
+		{"atom", atomXML, KindAtom, []string{"https://example.com/posts/1", "/posts/2"}, nil},
+		{"opml", opmlXML, KindOPML, []string{"https://example.org/news"}, []string{"/feed.xml"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(test.doc))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if doc.Kind != test.kind {
+				t.Errorf("Unexpected kind, got %s instead of %s", doc.Kind, test.kind)
+			}
+			if !reflect.DeepEqual(doc.Items, test.items) {
+				t.Errorf("Unexpected items, got %q instead of %q", doc.Items, test.items)
+			}
+			if !reflect.DeepEqual(doc.Links, test.links) {
+				t.Errorf("Unexpected links, got %q instead of %q", doc.Links, test.links)
+			}
+		})
+	}
+}
+
+func TestParseGzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(sitemapXML)) // nolint:errcheck
+	zw.Close()
+
+	doc, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.Kind != KindSitemap || len(doc.Items) != 2 {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	for _, doc := range []string{"<html><body></body></html>", "plain text", ""} {
+		if _, err := Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("Unexpected parse of %q", doc)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(sitemapIndexXML)) // nolint:errcheck
+	})
+	mux.HandleFunc("/sitemap-posts.xml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(sitemapXML)) // nolint:errcheck
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(atomXML)) // nolint:errcheck
+	})
+	mux.HandleFunc("/list.opml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(opmlXML)) // nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/sitemap.xml", []string{"https://example.com/", "https://example.com/about"}},
+		{"/feed.xml", []string{"https://example.com/posts/1", server.URL + "/posts/2"}},
+		{"/list.opml", []string{"https://example.org/news", "https://example.com/posts/1", server.URL + "/posts/2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got, err := Expand(context.Background(), server.Client(), server.URL+test.path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Unexpected items, got %q instead of %q", got, test.want)
+			}
+		})
+	}
+
+	if _, err := Expand(context.Background(), server.Client(), server.URL+"/missing.xml"); err == nil {
+		t.Error("Unexpected expand of a missing document")
+	}
+	if _, err := Expand(context.Background(), server.Client(), "ftp://example.com/feed.xml"); err == nil {
+		t.Error("Unexpected expand of an invalid url")
+	}
+}
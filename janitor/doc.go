@@ -0,0 +1,11 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package janitor implements the janitor of the storage directory, which
+removes the artifacts matched by the retention rules, e.g. the max age, the
+max total size, the limits of the kinds and the latest captures of a URL to
+keep, and reclaims the blobs no longer referenced.
+*/
+package janitor // import "github.com/wabarc/wayback/janitor"
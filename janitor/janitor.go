@@ -0,0 +1,419 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package janitor // import "github.com/wabarc/wayback/janitor"
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/wabarc/helper"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/reduxer"
+)
+
+const (
+	// layout is the layout of the time prefix of the names of the
+	// artifacts, see helper.FileName.
+	layout = "2006-01-02-150405.000"
+
+	// captureWindow is the window within which the files of a URL belong to
+	// the same capture, the WARC file of a capture is named after the others.
+	captureWindow = 5 * time.Minute
+)
+
+// Reasons of the files removed.
+const (
+	ReasonAge        = "age"
+	ReasonKindAge    = "kind-age"
+	ReasonKeepLatest = "keep-latest"
+	ReasonKindSize   = "kind-size"
+	ReasonSize       = "size"
+)
+
+// Policy represents the retention rules, zero values mean unlimited.
+type Policy struct {
+	MaxAge     time.Duration
+	MaxSize    uint64
+	Kinds      map[string]config.RetentionLimit
+	KeepLatest int
+}
+
+// PolicyOf returns the Policy configured by the options.
+func PolicyOf(opts *config.Options) Policy {
+	return Policy{
+		MaxAge:     opts.RetentionMaxAge(),
+		MaxSize:    opts.RetentionMaxSize(),
+		Kinds:      opts.RetentionKinds(),
+		KeepLatest: opts.RetentionKeepLatest(),
+	}
+}
+
+// File represents a file of the storage directory matched by the rules.
+type File struct {
+	Name    string // Name relative to the storage directory
+	Kind    string // One of img, pdf, raw, txt, har, htm, warc, media, diff and diff-img
+	Size    int64
+	Created time.Time
+	Reason  string
+
+	key  string
+	path string
+	sum  string // Digest of the blob linked to, if any
+}
+
+// Report represents the results of a sweep.
+type Report struct {
+	DryRun bool
+	Files  []File // Files removed, or to be removed in the dry-run mode
+	Bytes  int64  // Size reclaimed, or to be reclaimed in the dry-run mode
+	Blobs  int    // Number of the blobs removed
+}
+
+// Records represents the records of the artifacts, e.g. the datastore,
+// which forget the files removed by the janitor.
+type Records interface {
+	ForgetArtifacts(ctx context.Context, paths []string) (int64, error)
+}
+
+// Janitor removes the files of the storage directory matched by the
+// retention rules and reclaims the blobs no longer referenced.
+//
+// Only the artifacts named by reduxer, which are prefixed with the time of
+// the captures, are removed, the other files are left alone.
+type Janitor struct {
+	dir     string
+	policy  Policy
+	dryRun  bool
+	blobs   *reduxer.Blobs
+	records Records
+	now     func() time.Time
+}
+
+// New returns a Janitor of the storage directory with the retention policy,
+// the files are only reported if dryRun is true.
+func New(dir string, policy Policy, dryRun bool) *Janitor {
+	return &Janitor{
+		dir:    dir,
+		policy: policy,
+		dryRun: dryRun,
+		blobs:  reduxer.NewBlobs(dir),
+		now:    time.Now,
+	}
+}
+
+// Forget makes the records forget the files removed by the janitor, so the
+// files removed are no longer advertised.
+func (j *Janitor) Forget(records Records) *Janitor {
+	j.records = records
+	return j
+}
+
+// Run sweeps the storage directory at once and at every interval until the
+// context is done.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := j.Sweep()
+		if err != nil {
+			logger.Error("janitor sweep failed: %v", err)
+		} else if report.DryRun {
+			for _, f := range report.Files {
+				logger.Info("janitor would remove %s by %s rule", f.Name, f.Reason)
+			}
+		} else if len(report.Files) > 0 || report.Blobs > 0 {
+			logger.Info("janitor removed %d files and %d blobs, %s reclaimed",
+				len(report.Files), report.Blobs, humanize.Bytes(uint64(report.Bytes)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep applies the retention rules to the files of the storage directory
+// and removes the blobs no longer referenced. The files are matched by the
+// max age, the limits of the kinds, the latest captures to keep and the
+// max total size in order, the oldest files are removed first for the size
+// limits.
+func (j *Janitor) Sweep() (*Report, error) {
+	files, err := j.files()
+	if err != nil {
+		return nil, err
+	}
+	u, err := j.usage(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first.
+	sort.SliceStable(files, func(a, b int) bool {
+		return files[a].Created.Before(files[b].Created)
+	})
+
+	matched := make([]*File, 0)
+	mark := func(f *File, reason string) {
+		if f.Reason != "" {
+			return
+		}
+		f.Reason = reason
+		u.remove(f)
+		matched = append(matched, f)
+	}
+
+	now := j.now()
+	for _, f := range files {
+		if j.policy.MaxAge > 0 && now.Sub(f.Created) > j.policy.MaxAge {
+			mark(f, ReasonAge)
+		}
+		if limit := j.policy.Kinds[f.Kind]; limit.MaxAge > 0 && now.Sub(f.Created) > limit.MaxAge {
+			mark(f, ReasonKindAge)
+		}
+	}
+
+	if j.policy.KeepLatest > 0 {
+		for _, f := range outdated(files, j.policy.KeepLatest) {
+			mark(f, ReasonKeepLatest)
+		}
+	}
+
+	for kind, limit := range j.policy.Kinds {
+		if limit.MaxSize == 0 {
+			continue
+		}
+		var size uint64
+		for _, f := range files {
+			if f.Kind == kind && f.Reason == "" {
+				size += uint64(f.Size)
+			}
+		}
+		for _, f := range files {
+			if size <= limit.MaxSize {
+				break
+			}
+			if f.Kind == kind && f.Reason == "" {
+				size -= uint64(f.Size)
+				mark(f, ReasonKindSize)
+			}
+		}
+	}
+
+	if j.policy.MaxSize > 0 {
+		for _, f := range files {
+			if uint64(u.total) <= j.policy.MaxSize {
+				break
+			}
+			mark(f, ReasonSize)
+		}
+	}
+
+	report := &Report{DryRun: j.dryRun, Files: []File{}}
+	if j.dryRun {
+		for _, f := range matched {
+			report.Files = append(report.Files, *f)
+		}
+		report.Bytes = u.freed
+		return report, nil
+	}
+
+	for _, f := range matched {
+		if err = os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			logger.Warn("remove %s failed: %v", f.Name, err)
+			continue
+		}
+		report.Files = append(report.Files, *f)
+		if f.sum == "" {
+			report.Bytes += f.Size
+		}
+		// Remove the directory if it is empty.
+		if dir := filepath.Dir(f.path); dir != j.dir {
+			os.Remove(dir) // nolint:errcheck
+		}
+	}
+
+	if j.records != nil && len(report.Files) > 0 {
+		paths := make([]string, 0, len(report.Files))
+		for _, f := range report.Files {
+			paths = append(paths, f.path)
+		}
+		if _, err = j.records.ForgetArtifacts(context.Background(), paths); err != nil {
+			logger.Warn("forget the records of the removed files failed: %v", err)
+		}
+	}
+
+	stats, err := j.blobs.GC()
+	if err != nil {
+		return report, err
+	}
+	report.Blobs = stats.Blobs
+	report.Bytes += stats.Bytes
+
+	return report, nil
+}
+
+// files returns the artifacts of the storage directory, the blobs and the
+// files not named by reduxer are skipped.
+func (j *Janitor) files() ([]*File, error) {
+	files := []*File{}
+	blobs := filepath.Join(j.dir, reduxer.BlobsDir)
+	err := filepath.WalkDir(j.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == blobs {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		f, ok := parse(d.Name())
+		if !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(j.dir, path)
+		if err != nil {
+			return err
+		}
+		f.Name, f.Size, f.path = filepath.ToSlash(name), info.Size(), path
+		files = append(files, f)
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return files, err
+}
+
+// usage returns the disk usage of the files and the blobs, the blobs are
+// counted once however many files are linked to them.
+func (j *Janitor) usage(files []*File) (*usage, error) {
+	blobs, err := j.blobs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	u := &usage{sizes: make(map[string]int64), refs: make(map[string]int)}
+	index := make(map[string]*File, len(files))
+	for _, f := range files {
+		index[f.Name] = f
+	}
+	for _, b := range blobs {
+		u.sizes[b.Sum] = b.Size
+		u.total += b.Size
+		for _, ref := range b.Refs {
+			if f, ok := index[ref]; ok {
+				f.sum = b.Sum
+				u.refs[b.Sum]++
+			} else if helper.Exists(filepath.Join(j.dir, filepath.FromSlash(ref))) {
+				u.refs[b.Sum]++
+			}
+		}
+	}
+	for _, f := range files {
+		if f.sum == "" {
+			u.total += f.Size
+		}
+	}
+	return u, nil
+}
+
+type usage struct {
+	total int64
+	freed int64
+	sizes map[string]int64 // Sizes of the blobs
+	refs  map[string]int   // Number of the files linked to the blobs
+}
+
+// remove updates the usage for the file removed, the size of a blob is
+// freed once the last file linked to it is removed.
+func (u *usage) remove(f *File) {
+	size := f.Size
+	if f.sum != "" {
+		u.refs[f.sum]--
+		if u.refs[f.sum] > 0 {
+			return
+		}
+		size = u.sizes[f.sum]
+	}
+	u.total -= size
+	u.freed += size
+}
+
+// outdated returns the files of the captures of every URL except the latest
+// keep captures, the files without URLs are skipped. The files are grouped
+// into the captures by the times, and they are sorted from the oldest.
+func outdated(files []*File, keep int) []*File {
+	keys := []string{}
+	groups := make(map[string][][]*File)
+	for _, f := range files {
+		if f.key == "" {
+			continue
+		}
+		caps, ok := groups[f.key]
+		if !ok {
+			keys = append(keys, f.key)
+		}
+		if n := len(caps); n > 0 && f.Created.Sub(caps[n-1][0].Created) <= captureWindow {
+			caps[n-1] = append(caps[n-1], f)
+		} else {
+			caps = append(caps, []*File{f})
+		}
+		groups[f.key] = caps
+	}
+
+	old := []*File{}
+	for _, key := range keys {
+		caps := groups[key]
+		for i := 0; i < len(caps)-keep; i++ {
+			old = append(old, caps[i]...)
+		}
+	}
+	return old
+}
+
+// parse parses the name of an artifact named by reduxer, which is the time
+// of the capture, the URL and the extension of the kind, e.g.
+// 2025-01-02-150405.000-example-com-path.pdf.
+func parse(name string) (*File, bool) {
+	if len(name) <= len(layout) {
+		return nil, false
+	}
+	created, err := time.ParseInLocation(layout, name[:len(layout)], time.Local)
+	if err != nil {
+		return nil, false
+	}
+
+	rest := name[len(layout):]
+	kind := "media"
+	for _, k := range []struct{ suffix, kind string }{
+		{".diff.png", "diff-img"}, {".diff", "diff"}, {".warc.gz", "warc"}, {".warc", "warc"},
+		{".png", "img"}, {".pdf", "pdf"}, {".html", "raw"}, {".htm", "htm"}, {".txt", "txt"}, {".har", "har"},
+	} {
+		if strings.HasSuffix(rest, k.suffix) {
+			kind, rest = k.kind, strings.TrimSuffix(rest, k.suffix)
+			break
+		}
+	}
+	if kind == "media" {
+		rest = strings.TrimSuffix(rest, filepath.Ext(rest))
+	}
+
+	return &File{Kind: kind, Created: created, key: strings.TrimPrefix(rest, "-")}, true
+}
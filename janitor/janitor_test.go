@@ -0,0 +1,213 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package janitor // import "github.com/wabarc/wayback/janitor"
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/reduxer"
+)
+
+var now = time.Date(2025, 3, 8, 12, 0, 0, 0, time.Local)
+
+// touch creates an artifact of the URL created at the time with the content.
+func touch(t *testing.T, dir, url, ext string, created time.Time, content string) string {
+	t.Helper()
+
+	name := filepath.Join(dir, created.Format("200601"), created.Format(layout)+"-"+url+ext)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func names(report *Report) string {
+	list := []string{}
+	for _, f := range report.Files {
+		list = append(list, filepath.Base(f.Name)+":"+f.Reason)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		key  string
+		ok   bool
+	}{
+		{"2025-01-02-150405.000-example-com.png", "img", "example-com", true},
+		{"2025-01-02-150405.000-example-com-some-path.warc.gz", "warc", "example-com-some-path", true},
+		{"2025-01-02-150405.000-example-com.diff.png", "diff-img", "example-com", true},
+		{"2025-01-02-150405.000-example-com.htm", "htm", "example-com", true},
+		{"2025-01-02-150405.000-example-com.mp4", "media", "example-com", true},
+		{"2025-01-02-150405.000.html", "raw", "", true},
+		{"wayback.db", "", "", false},
+		{"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824.png", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, ok := parse(test.name)
+			if ok != test.ok {
+				t.Fatalf("unexpected parsed %t", ok)
+			}
+			if ok && (f.Kind != test.kind || f.key != test.key || f.Created.Format(layout) != "2025-01-02-150405.000") {
+				t.Errorf("unexpected file: %+v", f)
+			}
+		})
+	}
+}
+
+func TestPolicyOf(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_RETENTION_MAX_AGE", "30")
+	os.Setenv("WAYBACK_RETENTION_KINDS", "media=1GB")
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	p := PolicyOf(opts)
+	if p.MaxAge != 30*24*time.Hour || p.MaxSize != 0 || p.KeepLatest != 0 || p.Kinds["media"].MaxSize != 1000000000 {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	dir := t.TempDir()
+	for _, days := range []int{10, 5, 1} {
+		created := now.Add(-time.Duration(days) * 24 * time.Hour)
+		touch(t, dir, "example-com", ".png", created, "png")
+		touch(t, dir, "example-com", ".pdf", created, "pdf")
+		// The WARC file is named after the others.
+		touch(t, dir, "example-com", ".warc.gz", created.Add(time.Minute), "warc")
+	}
+	media := touch(t, dir, "example-org", ".mp4", now.Add(-2*24*time.Hour), strings.Repeat("m", 100))
+	other := filepath.Join(dir, "wayback.db")
+	if err := os.WriteFile(other, []byte("db"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sweep := func(policy Policy, dryRun bool) *Report {
+		t.Helper()
+		j := New(dir, policy, dryRun)
+		j.now = func() time.Time { return now }
+		report, err := j.Sweep()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return report
+	}
+
+	day := 24 * time.Hour
+	old := now.Add(-10 * day).Format(layout)
+	warc := now.Add(-10*day + time.Minute).Format(layout)
+	exp := old + "-example-com.pdf:age," + old + "-example-com.png:age," + warc + "-example-com.warc.gz:age"
+
+	report := sweep(Policy{MaxAge: 7 * day}, true)
+	if got := names(report); got != exp || report.Bytes != 10 || !report.DryRun {
+		t.Fatalf("unexpected dry-run report %s, %d bytes", got, report.Bytes)
+	}
+	if !helper.Exists(report.Files[0].path) {
+		t.Fatal("unexpected removal in the dry-run mode")
+	}
+
+	report = sweep(Policy{MaxAge: 7 * day}, false)
+	if got := names(report); got != exp || report.Bytes != 10 {
+		t.Fatalf("unexpected report %s, %d bytes", got, report.Bytes)
+	}
+	if helper.Exists(report.Files[0].path) || helper.Exists(filepath.Dir(report.Files[0].path)) {
+		t.Fatal("unexpected files of the removed capture")
+	}
+
+	// The capture of 5 days ago is the only one beyond the latest capture.
+	report = sweep(Policy{KeepLatest: 1}, false)
+	if len(report.Files) != 3 || !strings.HasPrefix(filepath.Base(report.Files[0].Name), now.Add(-5*day).Format(layout)) {
+		t.Fatalf("unexpected report of keep latest: %s", names(report))
+	}
+
+	report = sweep(Policy{Kinds: map[string]config.RetentionLimit{"media": {MaxSize: 50}}}, false)
+	if got := names(report); got != filepath.Base(media)+":kind-size" {
+		t.Fatalf("unexpected report of kind size: %s", got)
+	}
+
+	if !helper.Exists(other) {
+		t.Fatal("unexpected removal of the file not named by reduxer")
+	}
+}
+
+func TestSweepBlobs(t *testing.T) {
+	dir := t.TempDir()
+	blobs := reduxer.NewBlobs(dir)
+	a := touch(t, dir, "example-com", ".pdf", now.Add(-2*time.Hour), "0123456789")
+	b := touch(t, dir, "example-com", ".pdf", now.Add(-1*time.Hour), "0123456789")
+	c := touch(t, dir, "example-org", ".pdf", now, "abcdefghij")
+	for _, name := range []string{a, b, c} {
+		if _, err := blobs.Dedup(name); err != nil {
+			t.Fatalf("dedup failed: %v", err)
+		}
+	}
+
+	// The usage is 20 bytes of the two blobs, removing the oldest file does
+	// not free its blob linked by the second one.
+	j := New(dir, Policy{MaxSize: 10}, true)
+	j.now = func() time.Time { return now }
+	report, err := j.Sweep()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Files) != 2 || report.Bytes != 10 || report.Files[1].Name != filepath.ToSlash(b[len(dir)+1:]) {
+		t.Fatalf("unexpected dry-run report %s, %d bytes", names(report), report.Bytes)
+	}
+
+	j.dryRun = false
+	if report, err = j.Sweep(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Blobs != 1 || report.Bytes != 10 {
+		t.Fatalf("unexpected report %s, %d blobs, %d bytes", names(report), report.Blobs, report.Bytes)
+	}
+	if list, _ := blobs.List(); len(list) != 1 || !helper.Exists(c) {
+		t.Fatalf("unexpected blobs: %+v", list)
+	}
+}
+
+type records []string
+
+func (r *records) ForgetArtifacts(_ context.Context, paths []string) (int64, error) {
+	*r = append(*r, paths...)
+	return int64(len(paths)), nil
+}
+
+func TestSweepForget(t *testing.T) {
+	dir := t.TempDir()
+	old := touch(t, dir, "example-com", ".png", now.Add(-48*time.Hour), "old")
+	touch(t, dir, "example-com", ".png", now, "new")
+
+	var forgotten records
+	for _, dryRun := range []bool{true, false} {
+		j := New(dir, Policy{MaxAge: 24 * time.Hour}, dryRun).Forget(&forgotten)
+		j.now = func() time.Time { return now }
+		if _, err := j.Sweep(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(forgotten) != 1 || forgotten[0] != old {
+		t.Fatalf("unexpected files forgotten: %v", forgotten)
+	}
+}
@@ -65,6 +65,24 @@ var (
 		Help:      "Total number of wayback results published to configured services",
 	}, []string{"desc", "status"})
 
+	slotGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "wayback",
+		Name:      "slot",
+		Help:      "Total number of archiving results from configured slots",
+	}, []string{"slot", "status"})
+
+	breakerGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "wayback",
+		Name:      "slot_breaker",
+		Help:      "State of circuit breaker of slots, 0 is closed, 1 is half-open and 2 is open",
+	}, []string{"slot"})
+
+	rateLimitGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "wayback",
+		Name:      "ratelimit",
+		Help:      "Total number of requests rejected by the rate limiter from configured services",
+	}, []string{"from"})
+
 	buildInfoGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Namespace: "wayback",
 		Name:      "info",
@@ -94,6 +112,21 @@ func IncrementPublish(desc, status string) {
 	publishGauge.With(prometheus.Labels{"desc": desc, "status": status}).Inc()
 }
 
+// IncrementSlot increments the archiving results counter of slot
+func IncrementSlot(slot, status string) {
+	slotGauge.With(prometheus.Labels{"slot": slot, "status": status}).Inc()
+}
+
+// SetBreaker sets the circuit breaker state of slot
+func SetBreaker(slot string, state int) {
+	breakerGauge.With(prometheus.Labels{"slot": slot}).Set(float64(state))
+}
+
+// IncrementRateLimit increments the rate limited requests counter
+func IncrementRateLimit(from string) {
+	rateLimitGauge.With(prometheus.Labels{"from": from}).Inc()
+}
+
 // Collector represents a metric collector.
 type Collector struct {
 	// WaybackPgs reports the archiving result for configured services
@@ -105,6 +138,15 @@ type Collector struct {
 	// PublishPgs reports the publish result for configured services
 	PublishPgs prometheus.GaugeVec
 
+	// SlotPgs reports the archiving result for configured slots
+	SlotPgs prometheus.GaugeVec
+
+	// BreakerPgs reports the circuit breaker state for configured slots
+	BreakerPgs prometheus.GaugeVec
+
+	// RateLimitPgs reports the rate limited requests for configured services
+	RateLimitPgs prometheus.GaugeVec
+
 	// uptimeDesc reports the uptime of the wayback
 	uptimeDesc *prometheus.Desc
 }
@@ -112,9 +154,12 @@ type Collector struct {
 // NewCollector initializes a new metric collector.
 func NewCollector() *Collector {
 	collector := &Collector{
-		WaybackPgs:  *waybackGauge,
-		PlaybackPgs: *playbackGauge,
-		PublishPgs:  *publishGauge,
+		WaybackPgs:   *waybackGauge,
+		PlaybackPgs:  *playbackGauge,
+		PublishPgs:   *publishGauge,
+		SlotPgs:      *slotGauge,
+		BreakerPgs:   *breakerGauge,
+		RateLimitPgs: *rateLimitGauge,
 		uptimeDesc: prometheus.NewDesc(
 			"wayback_uptime",
 			"The uptime of wayback service.",
@@ -131,6 +176,9 @@ func (c *Collector) metricsList() []prometheus.GaugeVec {
 		c.WaybackPgs,
 		c.PlaybackPgs,
 		c.PublishPgs,
+		c.SlotPgs,
+		c.BreakerPgs,
+		c.RateLimitPgs,
 	}
 }
 
@@ -0,0 +1,91 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package wayback // import "github.com/wabarc/wayback"
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/wabarc/wayback/config"
+)
+
+var (
+	modules = make(map[string]*Module)
+	mu      sync.RWMutex
+)
+
+// SetupFunc is a function type that takes a context, a pointer to
+// config.Options struct and the URL to archive, it returns a Waybacker
+// to be called.
+type SetupFunc func(context.Context, *config.Options, *url.URL) Waybacker
+
+// PlaybackFunc is a function type that takes a context and the URL to
+// search, it returns the archived URL found in the slot.
+type PlaybackFunc func(context.Context, *url.URL) string
+
+// Module holds an archive slot, the Setup function is used to archive
+// webpages and the Playback function is used to search archived webpages.
+// Either of them can be nil if the slot does not support it.
+type Module struct {
+	// Slot holds the metadata of the slot, it is registered to the config
+	// package if the slot is not registered yet.
+	Slot config.Slot
+
+	Setup    SetupFunc
+	Playback PlaybackFunc
+}
+
+// Register registers an archive slot and allows it to be called
+// by Wayback and Playback.
+func Register(mod *Module) {
+	if mod == nil || mod.Slot.Name == "" {
+		panic("module slot name is required")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	name := mod.Slot.Name
+	if _, exists := modules[name]; exists {
+		panic(fmt.Sprintf("module %s registered", name))
+	}
+	if _, exists := config.LookupSlot(name); !exists {
+		config.RegisterSlot(mod.Slot)
+	}
+	modules[name] = mod
+}
+
+func loadModule(slot string) (*Module, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	mod, ok := modules[slot]
+	if !ok {
+		return nil, fmt.Errorf("module %s not exists", slot)
+	}
+	return mod, nil
+}
+
+// playbackModules returns the registered modules that support playback,
+// sorted by slot name.
+func playbackModules() []*Module {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	mods := make([]*Module, 0, len(modules))
+	for _, mod := range modules {
+		if mod.Playback != nil {
+			mods = append(mods, mod)
+		}
+	}
+	sort.Slice(mods, func(i, j int) bool {
+		return mods[i].Slot.Name < mods[j].Slot.Name
+	})
+
+	return mods
+}
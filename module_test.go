@@ -0,0 +1,106 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package wayback // import "github.com/wabarc/wayback"
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/reduxer"
+)
+
+type fakeArchiver struct {
+	URL *url.URL
+}
+
+func (f fakeArchiver) Wayback(_ reduxer.Reduxer) (string, error) {
+	return "https://example.org/archived/" + f.URL.Host, nil
+}
+
+// registerTest registers the module and removes the module, the metadata
+// and the circuit breaker of its slot once the test finished.
+func registerTest(t *testing.T, mod *Module) {
+	t.Helper()
+
+	Register(mod)
+	t.Cleanup(func() {
+		name := mod.Slot.Name
+		mu.Lock()
+		delete(modules, name)
+		mu.Unlock()
+		breakerMu.Lock()
+		delete(breakers, name)
+		breakerMu.Unlock()
+		config.UnregisterSlot(name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	const slot = "fake"
+	mod := &Module{
+		Slot: config.Slot{Name: slot, Title: "Fake Archive", Env: "WAYBACK_ENABLE_FAKE"},
+		Setup: func(_ context.Context, _ *config.Options, u *url.URL) Waybacker {
+			return fakeArchiver{URL: u}
+		},
+	}
+
+	registerTest(t, mod)
+
+	if got := config.SlotName(slot); got != "Fake Archive" {
+		t.Fatalf(`Unexpected slot name, got %s instead of Fake Archive`, got)
+	}
+	if _, err := loadModule(slot); err != nil {
+		t.Fatalf(`Unexpected load module: %v`, err)
+	}
+	for _, m := range playbackModules() {
+		if m.Slot.Name == slot {
+			t.Fatalf(`Unexpected playback module for slot %s`, slot)
+		}
+	}
+
+	// Call Register again with the same slot, it should panic
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Register should have panicked")
+		}
+	}()
+	Register(mod)
+}
+
+func TestWaybackWithRegisteredSlot(t *testing.T) {
+	const slot = "fake-wayback"
+	registerTest(t, &Module{
+		Slot: config.Slot{Name: slot, Title: "Fake Archive", Env: "WAYBACK_ENABLE_FAKE_WAYBACK", Enabled: true},
+		Setup: func(_ context.Context, _ *config.Options, u *url.URL) Waybacker {
+			return fakeArchiver{URL: u}
+		},
+	})
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	u, _ := url.Parse("https://example.com/")
+	cols, err := Wayback(context.Background(), reduxer.BundleExample(), opts, u)
+	if err != nil {
+		t.Fatalf(`Unexpected wayback: %v`, err)
+	}
+
+	var found bool
+	for _, col := range cols {
+		if col.Arc == slot {
+			found = true
+			if col.Dst != "https://example.org/archived/example.com" {
+				t.Errorf(`Unexpected destination, got %s`, col.Dst)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf(`Unexpected collects, slot %s not found in %v`, slot, cols)
+	}
+}
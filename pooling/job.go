@@ -0,0 +1,189 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/errors"
+)
+
+// maxFinishedJobs is the number of finished jobs kept for querying.
+const maxFinishedJobs = 256
+
+var (
+	ErrJobNotFound = errors.New("job not found") // ErrJobNotFound job not found
+	ErrJobFinished = errors.New("job finished")  // ErrJobFinished job finished
+)
+
+// Job represents the lifecycle of a bucket in the pool.
+type Job struct {
+	ID       uint64          `json:"id"`
+	Source   string          `json:"source,omitempty"`
+	Priority Priority        `json:"priority"`
+	State    entity.JobState `json:"state"`
+	Attempts uint64          `json:"attempts"`
+	Error    string          `json:"error,omitempty"`
+	Created  time.Time       `json:"created"`
+	Updated  time.Time       `json:"updated"`
+}
+
+type tracker struct {
+	Job
+
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// track registers the bucket as a queued job, the caller must hold the mutex.
+func (p *Pool) track(b Bucket) {
+	if p.jobs == nil {
+		p.jobs = make(map[uint64]*tracker)
+	}
+	now := time.Now().UTC()
+	p.jobs[b.id] = &tracker{Job: Job{
+		ID:       b.id,
+		Source:   b.Source,
+		Priority: b.Priority,
+		State:    entity.JobQueued,
+		Created:  now,
+		Updated:  now,
+	}}
+}
+
+// start registers the cancel func of the running job, it reports false if
+// the job was cancelled.
+func (p *Pool) start(id uint64, cancel context.CancelFunc) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	t, ok := p.jobs[id]
+	if !ok {
+		return true
+	}
+	t.cancel = cancel
+	return !t.cancelled
+}
+
+func (p *Pool) cancelled(id uint64) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	t, ok := p.jobs[id]
+	return ok && t.cancelled
+}
+
+// update transits the job of the bucket to the given state.
+func (p *Pool) update(b *Bucket, state entity.JobState, err error) {
+	p.mutex.Lock()
+	p.finish(b.id, state, atomic.LoadUint64(&b.elapsed), err)
+	p.mutex.Unlock()
+
+	p.transit(b.job, state, err)
+}
+
+// finish updates the job state, the caller must hold the mutex.
+func (p *Pool) finish(id uint64, state entity.JobState, attempts uint64, err error) {
+	t, ok := p.jobs[id]
+	if !ok {
+		return
+	}
+	t.State = state
+	t.Attempts = attempts
+	t.Updated = time.Now().UTC()
+	t.Error = ""
+	if err != nil {
+		t.Error = err.Error()
+	}
+	if !state.Finished() {
+		return
+	}
+
+	t.cancel = nil
+	p.finished = append(p.finished, id)
+	if len(p.finished) > maxFinishedJobs {
+		delete(p.jobs, p.finished[0])
+		p.finished = p.finished[1:]
+	}
+}
+
+// Job returns the job of the given id.
+func (p *Pool) Job(id uint64) (Job, error) {
+	if p == nil {
+		return Job{}, ErrPoolNotExist
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	t, ok := p.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return t.Job, nil
+}
+
+// Jobs returns the unfinished jobs and the recent finished jobs sorted by id.
+func (p *Pool) Jobs() []Job {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	jobs := make([]Job, 0, len(p.jobs))
+	for _, t := range p.jobs {
+		jobs = append(jobs, t.Job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	return jobs
+}
+
+// Cancel cancels the job of the given id. A queued job is removed from the
+// pool, and a running job is interrupted without calling the fallback.
+func (p *Pool) Cancel(id uint64) error {
+	if p == nil {
+		return ErrPoolNotExist
+	}
+
+	p.mutex.Lock()
+	t, ok := p.jobs[id]
+	if !ok {
+		p.mutex.Unlock()
+		return ErrJobNotFound
+	}
+	if t.State.Finished() {
+		p.mutex.Unlock()
+		return ErrJobFinished
+	}
+	t.cancelled = true
+
+	if b, ok := p.staging.remove(id); ok {
+		p.finish(id, entity.JobCancelled, 0, nil)
+		atomic.AddInt32(&p.waiting, -1)
+		if p.idle != nil {
+			p.idle.Broadcast()
+		}
+		p.mutex.Unlock()
+		p.transit(b.job, entity.JobCancelled, nil)
+		return nil
+	}
+
+	cancel := t.cancel
+	p.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	return nil
+}
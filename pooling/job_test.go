@@ -0,0 +1,106 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+)
+
+func TestJobs(t *testing.T) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	p := New(context.Background(), Capacity(1), Timeout(time.Second), MaxRetries(1))
+
+	failed := p.Put(Bucket{Source: "a", Request: func(_ context.Context) error { return errors.New("failed") }})
+	done := p.Put(Bucket{Source: "a", Request: func(_ context.Context) error { return nil }})
+	queued := p.Put(Bucket{Source: "b", Request: func(_ context.Context) error { return nil }})
+
+	jobs := p.Jobs()
+	if len(jobs) != 3 {
+		t.Fatalf("Unexpected jobs number got %d instead of 3", len(jobs))
+	}
+	for _, job := range jobs {
+		if job.State != entity.JobQueued {
+			t.Errorf("Unexpected state of job %d got %s instead of %s", job.ID, job.State, entity.JobQueued)
+		}
+	}
+
+	if err := p.Cancel(queued); err != nil {
+		t.Fatalf("Unexpected cancel queued job: %v", err)
+	}
+	if err := p.Cancel(100); err != ErrJobNotFound {
+		t.Errorf("Unexpected cancel job not exists got %v instead of %v", err, ErrJobNotFound)
+	}
+
+	go p.Roll()
+	p.wake()
+	p.Close()
+
+	tests := []struct {
+		id       uint64
+		state    entity.JobState
+		attempts uint64
+	}{
+		{failed, entity.JobFailed, 2},
+		{done, entity.JobDone, 0},
+		{queued, entity.JobCancelled, 0},
+	}
+	for _, test := range tests {
+		job, err := p.Job(test.id)
+		if err != nil {
+			t.Fatalf("Unexpected query job %d: %v", test.id, err)
+		}
+		if job.State != test.state || job.Attempts != test.attempts {
+			t.Errorf("Unexpected job %d got %s with %d attempts instead of %s with %d attempts",
+				test.id, job.State, job.Attempts, test.state, test.attempts)
+		}
+	}
+	if err := p.Cancel(done); err != ErrJobFinished {
+		t.Errorf("Unexpected cancel finished job got %v instead of %v", err, ErrJobFinished)
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	p := New(context.Background(), Capacity(1), Timeout(time.Minute), MaxRetries(3))
+	go p.Roll()
+
+	started := make(chan struct{})
+	fallback := false
+	id := p.Put(Bucket{
+		Request: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Fallback: func(_ context.Context) error {
+			fallback = true
+			return nil
+		},
+	})
+
+	<-started
+	if job, _ := p.Job(id); job.State != entity.JobRunning {
+		t.Fatalf("Unexpected job state got %s instead of %s", job.State, entity.JobRunning)
+	}
+	if err := p.Cancel(id); err != nil {
+		t.Fatalf("Unexpected cancel running job: %v", err)
+	}
+	p.Close()
+
+	if job, _ := p.Job(id); job.State != entity.JobCancelled {
+		t.Errorf("Unexpected job state got %s instead of %s", job.State, entity.JobCancelled)
+	}
+	if fallback {
+		t.Error("Unexpected fallback of cancelled job")
+	}
+}
@@ -10,9 +10,16 @@ import (
 
 // Options represents configuration for pooling.
 type Options struct {
-	Timeout    time.Duration // Timeout specifies the maximum amount of time to wait for an operation to complete.
-	MaxRetries uint64        // MaxRetries specifies the maximum number of times to retry the operation in case of failure.
-	Capacity   int           // Capacity specifies the maximum number of items that can be processed simultaneously.
+	Timeout     time.Duration // Timeout specifies the maximum amount of time to wait for an operation to complete.
+	MaxRetries  uint64        // MaxRetries specifies the maximum number of times to retry the operation in case of failure.
+	Capacity    int           // Capacity specifies the maximum number of items that can be processed simultaneously.
+	SourceLimit int           // SourceLimit specifies the maximum number of items from a single source that can be processed simultaneously, zero means unlimited.
+	Store       Store         // Store specifies the storage to persist buckets, buckets are kept in memory only if it is nil.
+
+	// SourceLimits specifies the SourceLimit of the sources of the services, e.g. {"telegram": 1}.
+	SourceLimits map[string]int
+	// Priorities specifies the priorities of the buckets of the services, e.g. {"web": PriorityHigh}.
+	Priorities map[string]Priority
 }
 
 // Option is a function that modifies the provided Options instance.
@@ -41,3 +48,41 @@ func Capacity(c int) Option {
 		opts.Capacity = c
 	}
 }
+
+// Storage returns an Option function that sets the store to persist buckets.
+// The given store will be applied when the returned function is called.
+func Storage(s Store) Option {
+	return func(opts *Options) {
+		opts.Store = s
+	}
+}
+
+// SourceLimit returns an Option function that sets the maximum number of
+// buckets from a single source that are processed simultaneously.
+// The given limit will be applied when the returned function is called.
+func SourceLimit(n int) Option {
+	return func(opts *Options) {
+		opts.SourceLimit = n
+	}
+}
+
+// SourceLimits returns an Option function that sets the maximum number of
+// buckets from a single source of the services that are processed
+// simultaneously, which overrides SourceLimit, e.g. {"telegram": 1} limits
+// each chat of Telegram. The given limits will be applied when the returned
+// function is called.
+func SourceLimits(limits map[string]int) Option {
+	return func(opts *Options) {
+		opts.SourceLimits = limits
+	}
+}
+
+// Priorities returns an Option function that sets the priorities of the
+// buckets of the services, which override the priorities of the buckets,
+// e.g. {"web": PriorityHigh}. The given priorities will be applied when the
+// returned function is called.
+func Priorities(priorities map[string]Priority) Option {
+	return func(opts *Options) {
+		opts.Priorities = priorities
+	}
+}
@@ -10,8 +10,8 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/phf/go-queue/queue"
 	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
 	"github.com/wabarc/wayback/errors"
 )
 
@@ -20,7 +20,6 @@ var (
 	ErrTimeout      = errors.New("process timeout") // ErrTimeout process timeout
 
 	errRollTimeout = errors.New("roll bucket timeout")
-	errElapsed     = errors.New("retried to reach maximum times")
 )
 
 type resource struct {
@@ -31,16 +30,26 @@ type resource struct {
 type Pool struct {
 	context  context.Context
 	resource chan *resource
+	notify   chan struct{}
 	closed   chan bool
-	staging  queue.Queue
+	staging  scheduler
+	store    Store
 	timeout  time.Duration
 
 	maxRetries uint64
 	multiplier float64
 	mutex      sync.Mutex
+	idle       *sync.Cond
+	closeOnce  sync.Once
 
 	waiting    int32
 	processing int32
+
+	seq      uint64
+	jobs     map[uint64]*tracker
+	finished []uint64
+
+	priorities map[string]Priority
 }
 
 // A Bucket represents a wayback request is sent by a service.
@@ -54,8 +63,29 @@ type Bucket struct {
 	// An object that will perform exactly one action.
 	once *sync.Once
 
+	// Source identifies the sender of the bucket, e.g. telegram:<chat id>,
+	// buckets are served fairly between sources.
+	Source string
+
+	// Priority is the priority class of the bucket.
+	Priority Priority
+
+	// Kind identifies the service that sends the bucket, the bucket is
+	// persisted if it is specified and the pool has a store, see Pool.Resume.
+	// It must be specified only by the services that resume their buckets.
+	Kind string
+
+	// Payload holds the data to rebuild the bucket once resumed.
+	Payload []byte
+
 	// Count of retried attempts
 	elapsed uint64
+
+	// Identifier of the bucket in the pool
+	id uint64
+
+	// Persisted job of the bucket
+	job *entity.Job
 }
 
 func newResource(id int) *resource {
@@ -71,6 +101,9 @@ func New(ctx context.Context, options ...Option) *Pool {
 	}
 
 	p := new(Pool)
+	p.staging.limit = opts.SourceLimit
+	p.staging.limits = opts.SourceLimits
+	p.priorities = opts.Priorities
 	capacity := opts.Capacity
 	p.resource = make(chan *resource, capacity)
 	wg := new(sync.WaitGroup)
@@ -83,11 +116,14 @@ func New(ctx context.Context, options ...Option) *Pool {
 	}
 	wg.Wait()
 
+	p.notify = make(chan struct{}, 1)
 	p.closed = make(chan bool, 1)
+	p.idle = sync.NewCond(&p.mutex)
 	p.timeout = opts.Timeout
 	p.maxRetries = opts.MaxRetries + 1
 	p.multiplier = 0.75
 	p.context = ctx
+	p.store = opts.Store
 
 	return p
 }
@@ -97,18 +133,17 @@ func (p *Pool) Roll() {
 	// Blocks until closed
 	for {
 		select {
-		default:
 		case <-p.closed:
-			close(p.closed)
 			return
+		case <-p.notify:
 		}
 
-		// Waiting for new requests
-		if atomic.LoadInt32(&p.waiting) == 0 {
-			continue
-		}
-
-		if b, has := p.bucket(); has {
+		// Dispatches the staging requests until the pool is full
+		for {
+			b, has := p.bucket()
+			if !has {
+				break
+			}
 			go b.once.Do(func() {
 				err := p.do(b)
 				if err != nil {
@@ -119,28 +154,49 @@ func (p *Pool) Roll() {
 	}
 }
 
-// Put puts wayback requests to the resource pool
-func (p *Pool) Put(b Bucket) {
-	// Inserts a new bucket at the front of queue.
+// Put puts wayback requests to the resource pool, it returns the id
+// of the job to query its state.
+func (p *Pool) Put(b Bucket) uint64 {
+	if priority, ok := p.priorities[serviceOf(b.Source)]; ok {
+		b.Priority = priority
+	}
+	p.persist(&b)
+	b.id = p.nextID(&b)
+
 	p.mutex.Lock()
-	p.staging.PushFront(b)
-	p.mutex.Unlock()
+	p.track(b)
+	p.staging.push(b)
 	atomic.AddInt32(&p.waiting, 1)
+	p.mutex.Unlock()
+
+	p.wake()
+
+	return b.id
+}
+
+// wake wakes up the roller, the notification is dropped if there is one pending.
+func (p *Pool) wake() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
 }
 
 // Close closes the worker pool, and it is blocked until all workers are idle.
 func (p *Pool) Close() {
-	var once sync.Once
-	for {
-		waiting := atomic.LoadInt32(&p.waiting)
-		processing := atomic.LoadInt32(&p.processing)
-		if p.resource != nil && waiting == 0 && processing == 0 {
-			once.Do(func() {
-				p.closed <- true
-			})
-			return
-		}
+	if p.resource == nil {
+		return
 	}
+
+	p.mutex.Lock()
+	for atomic.LoadInt32(&p.waiting) != 0 || atomic.LoadInt32(&p.processing) != 0 {
+		p.idle.Wait()
+	}
+	p.mutex.Unlock()
+
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
 }
 
 // Closed returns whether the pooling is closed. It uses a select with a
@@ -168,22 +224,33 @@ func (p *Pool) push(r *resource) error {
 }
 
 func (p *Pool) do(b Bucket) error {
-	atomic.AddInt32(&p.processing, 1)
+	bctx, cancel := context.WithCancel(p.context)
+	defer cancel()
 	defer func() {
+		p.mutex.Lock()
+		p.staging.done(b.Source)
 		atomic.AddInt32(&p.waiting, -1)
 		atomic.AddInt32(&p.processing, -1)
+		p.idle.Broadcast()
+		p.mutex.Unlock()
+		p.wake()
 	}()
+	if !p.start(b.id, cancel) {
+		p.update(&b, entity.JobCancelled, nil)
+		return nil
+	}
+	p.update(&b, entity.JobRunning, nil)
 
 	action := func() error {
 		interval := float64(b.elapsed) * p.multiplier
 		timeout := p.timeout + p.timeout*time.Duration(interval)
-		ctx, cancel := context.WithTimeout(p.context, timeout)
+		ctx, cancel := context.WithTimeout(bctx, timeout)
 		defer cancel()
 
 		r := p.pull()
 		defer func() {
 			p.push(r) // nolint:errcheck
-			if b.elapsed >= p.maxRetries {
+			if b.elapsed >= p.maxRetries && !p.cancelled(b.id) {
 				if b.Fallback != nil {
 					// nolint:errcheck
 					b.Fallback(ctx)
@@ -211,16 +278,23 @@ func (p *Pool) do(b Bucket) error {
 		}
 	}
 
-	ran := uint64(1)
-	max := p.maxRetries
-	for ; ran <= max; ran++ {
-		err := action()
-		switch err {
-		case nil, errElapsed:
-			return err
-		case errRollTimeout:
+	var err error
+	for ran := uint64(1); ran <= p.maxRetries; ran++ {
+		if ran > 1 {
+			p.update(&b, entity.JobRetrying, err)
+		}
+		if err = action(); err == nil || p.cancelled(b.id) {
+			break
 		}
 	}
+	switch {
+	case p.cancelled(b.id):
+		p.update(&b, entity.JobCancelled, nil)
+	case err != nil:
+		p.update(&b, entity.JobFailed, err)
+	default:
+		p.update(&b, entity.JobDone, nil)
+	}
 
 	return nil
 }
@@ -229,8 +303,13 @@ func (p *Pool) bucket() (b Bucket, ok bool) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	if b, ok = p.staging.PopBack().(Bucket); ok {
+	// Buckets are processed as many as the capacity of pool at once.
+	if int(atomic.LoadInt32(&p.processing)) >= cap(p.resource) {
+		return
+	}
+	if b, ok = p.staging.pop(); ok {
 		b.once = new(sync.Once)
+		atomic.AddInt32(&p.processing, 1)
 		return b, ok
 	}
 
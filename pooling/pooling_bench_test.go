@@ -0,0 +1,64 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/wabarc/logger"
+)
+
+func cpuTime(b *testing.B) time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		b.Fatalf("Unexpected get resource usage: %v", err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// BenchmarkRollIdle reports the CPU time consumed by an idle pool, which
+// is expected to be near zero.
+func BenchmarkRollIdle(b *testing.B) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	p := New(context.Background(), Capacity(2), Timeout(time.Second))
+	go p.Roll()
+	defer p.Close()
+
+	window := 10 * time.Millisecond
+	b.ResetTimer()
+	start := cpuTime(b)
+	for i := 0; i < b.N; i++ {
+		time.Sleep(window)
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(cpuTime(b)-start)/float64(b.N)/float64(window), "cpu/op")
+}
+
+// BenchmarkPut reports the throughput of the pool.
+func BenchmarkPut(b *testing.B) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	p := New(context.Background(), Capacity(4), Timeout(time.Second))
+	go p.Roll()
+
+	bucket := Bucket{
+		Request: func(_ context.Context) error {
+			return nil
+		},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Put(bucket)
+	}
+	p.Close()
+}
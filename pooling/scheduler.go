@@ -0,0 +1,174 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"strings"
+
+	"github.com/wabarc/wayback/errors"
+)
+
+// Priority represents the priority class of a bucket.
+type Priority uint8
+
+const (
+	PriorityNormal Priority = iota // PriorityNormal represents the default priority
+	PriorityHigh                   // PriorityHigh represents the priority for interactive requests
+	PriorityLow                    // PriorityLow represents the priority for background requests
+)
+
+// weight returns the share of the priority class, a bucket of high
+// priority is served twice as often as a normal one.
+func (p Priority) weight() float64 {
+	switch p {
+	case PriorityHigh:
+		return 4
+	case PriorityLow:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, it
+// returns an error if the text is not one of high, normal and low.
+func (p *Priority) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "high":
+		*p = PriorityHigh
+	case "low":
+		*p = PriorityLow
+	case "normal", "":
+		*p = PriorityNormal
+	default:
+		return errors.New("unknown priority %q", text)
+	}
+	return nil
+}
+
+// String returns the priority as a string.
+func (p Priority) String() string {
+	switch p {
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityLow:
+		return "low"
+	}
+
+	return "unknown"
+}
+
+type entry struct {
+	bucket Bucket
+	finish float64
+	seq    uint64
+}
+
+// scheduler implements weighted fair queuing between the sources of
+// buckets. Each bucket is tagged with a virtual finish time on arrival,
+// which advances per source by the inverse of the priority weight, the
+// bucket with the smallest tag whose source is under the limit is served
+// first. The zero value is ready to use, it is not safe for concurrent use.
+type scheduler struct {
+	entries []entry
+	vtime   float64
+	seq     uint64
+	limit   int
+	limits  map[string]int
+
+	finish  map[string]float64
+	running map[string]int
+}
+
+func (s *scheduler) push(b Bucket) {
+	if s.finish == nil {
+		s.finish = make(map[string]float64)
+		s.running = make(map[string]int)
+	}
+	start := s.finish[b.Source]
+	if start < s.vtime {
+		start = s.vtime
+	}
+	finish := start + 1/b.Priority.weight()
+	s.finish[b.Source] = finish
+	s.seq++
+	s.entries = append(s.entries, entry{bucket: b, finish: finish, seq: s.seq})
+}
+
+// pop returns the next bucket to serve, it reports false if there is no
+// bucket or the sources of all buckets reach the limit.
+func (s *scheduler) pop() (b Bucket, ok bool) {
+	idx := -1
+	for i, e := range s.entries {
+		if limit := s.limitOf(e.bucket.Source); limit > 0 && s.running[e.bucket.Source] >= limit {
+			continue
+		}
+		if idx < 0 || e.finish < s.entries[idx].finish ||
+			(e.finish == s.entries[idx].finish && e.seq < s.entries[idx].seq) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return b, false
+	}
+
+	e := s.entries[idx]
+	s.entries = append(s.entries[:idx], s.entries[idx+1:]...)
+	s.vtime = e.finish
+	s.running[e.bucket.Source]++
+
+	return e.bucket, true
+}
+
+// limitOf returns the limit of the source, which is the limit of its
+// service if specified.
+func (s *scheduler) limitOf(source string) int {
+	if limit, ok := s.limits[serviceOf(source)]; ok {
+		return limit
+	}
+	return s.limit
+}
+
+// serviceOf returns the service of the source, which is the part before
+// the colon, e.g. telegram of telegram:123.
+func serviceOf(source string) string {
+	service, _, _ := strings.Cut(source, ":")
+	return service
+}
+
+// remove removes the bucket of the given id.
+func (s *scheduler) remove(id uint64) (b Bucket, ok bool) {
+	for i, e := range s.entries {
+		if e.bucket.id == id {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return e.bucket, true
+		}
+	}
+	return b, false
+}
+
+// done releases the running bucket of the source.
+func (s *scheduler) done(source string) {
+	s.running[source]--
+	if s.running[source] <= 0 {
+		delete(s.running, source)
+	}
+	if len(s.entries) == 0 && len(s.running) == 0 {
+		// Resets the virtual time to avoid precision loss for long-running pool.
+		s.vtime = 0
+		clear(s.finish)
+	}
+}
+
+func (s *scheduler) len() int {
+	return len(s.entries)
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wabarc/logger"
+)
+
+func drain(s *scheduler) string {
+	var order []string
+	for {
+		b, ok := s.pop()
+		if !ok {
+			break
+		}
+		order = append(order, string(b.Payload))
+		s.done(b.Source)
+	}
+	return strings.Join(order, ",")
+}
+
+func TestScheduler(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []Bucket
+		expect  string
+	}{
+		{
+			name: "fifo",
+			buckets: []Bucket{
+				{Source: "a", Payload: []byte("a1")},
+				{Source: "a", Payload: []byte("a2")},
+				{Source: "a", Payload: []byte("a3")},
+			},
+			expect: "a1,a2,a3",
+		},
+		{
+			name: "fair",
+			buckets: []Bucket{
+				{Source: "a", Payload: []byte("a1")},
+				{Source: "a", Payload: []byte("a2")},
+				{Source: "a", Payload: []byte("a3")},
+				{Source: "b", Payload: []byte("b1")},
+				{Source: "b", Payload: []byte("b2")},
+			},
+			expect: "a1,b1,a2,b2,a3",
+		},
+		{
+			name: "priority",
+			buckets: []Bucket{
+				{Source: "a", Payload: []byte("a1"), Priority: PriorityLow},
+				{Source: "a", Payload: []byte("a2"), Priority: PriorityLow},
+				{Source: "b", Payload: []byte("b1"), Priority: PriorityHigh},
+				{Source: "b", Payload: []byte("b2"), Priority: PriorityHigh},
+				{Source: "b", Payload: []byte("b3"), Priority: PriorityHigh},
+				{Source: "b", Payload: []byte("b4"), Priority: PriorityHigh},
+			},
+			expect: "b1,b2,b3,a1,b4,a2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &scheduler{}
+			for _, b := range test.buckets {
+				s.push(b)
+			}
+			if got := drain(s); got != test.expect {
+				t.Errorf("Unexpected order got %s instead of %s", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestSchedulerLimit(t *testing.T) {
+	s := &scheduler{limit: 1}
+	s.push(Bucket{Source: "a", Payload: []byte("a1")})
+	s.push(Bucket{Source: "a", Payload: []byte("a2")})
+	s.push(Bucket{Source: "b", Payload: []byte("b1")})
+
+	if b, _ := s.pop(); string(b.Payload) != "a1" {
+		t.Fatalf("Unexpected bucket got %s instead of a1", b.Payload)
+	}
+	if b, _ := s.pop(); string(b.Payload) != "b1" {
+		t.Fatalf("Unexpected bucket got %s instead of b1", b.Payload)
+	}
+	if b, ok := s.pop(); ok {
+		t.Fatalf("Unexpected bucket %s of source reached the limit", b.Payload)
+	}
+
+	s.done("a")
+	if b, _ := s.pop(); string(b.Payload) != "a2" {
+		t.Fatalf("Unexpected bucket got %s instead of a2", b.Payload)
+	}
+	if s.len() != 0 {
+		t.Errorf("Unexpected staging buckets got %d instead of 0", s.len())
+	}
+}
+
+func TestPoolSourceLimit(t *testing.T) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	p := New(context.Background(), Capacity(3), Timeout(time.Second), SourceLimit(1))
+	go p.Roll()
+
+	var running, peak int32
+	request := func(_ context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		p.Put(Bucket{Source: "telegram:1", Request: request})
+	}
+	p.Close()
+
+	if peak != 1 {
+		t.Errorf("Unexpected concurrency of source got %d instead of 1", peak)
+	}
+}
+
+func TestSchedulerLimits(t *testing.T) {
+	s := &scheduler{limit: 1, limits: map[string]int{"web": 2}}
+	s.push(Bucket{Source: "telegram:1", Payload: []byte("t1")})
+	s.push(Bucket{Source: "telegram:1", Payload: []byte("t2")})
+	s.push(Bucket{Source: "web:token-1", Payload: []byte("w1")})
+	s.push(Bucket{Source: "web:token-1", Payload: []byte("w2")})
+
+	var order []string
+	for {
+		b, ok := s.pop()
+		if !ok {
+			break
+		}
+		order = append(order, string(b.Payload))
+	}
+	if got := strings.Join(order, ","); got != "t1,w1,w2" {
+		t.Fatalf("Unexpected order got %s instead of t1,w1,w2", got)
+	}
+}
+
+func TestPoolPriorities(t *testing.T) {
+	p := New(context.Background(), Capacity(1), Timeout(time.Second), Priorities(map[string]Priority{"web": PriorityHigh}))
+
+	web := p.Put(Bucket{Source: "web:127.0.0.1"})
+	telegram := p.Put(Bucket{Source: "telegram:1", Priority: PriorityLow})
+	if job, _ := p.Job(web); job.Priority != PriorityHigh {
+		t.Errorf("Unexpected priority of web got %s instead of high", job.Priority)
+	}
+	if job, _ := p.Job(telegram); job.Priority != PriorityLow {
+		t.Errorf("Unexpected priority of telegram got %s instead of low", job.Priority)
+	}
+}
+
+func TestPriorityUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		text     string
+		priority Priority
+		valid    bool
+	}{
+		{"high", PriorityHigh, true},
+		{"normal", PriorityNormal, true},
+		{"low", PriorityLow, true},
+		{"", PriorityNormal, true},
+		{"urgent", PriorityNormal, false},
+	}
+
+	for _, test := range tests {
+		var p Priority
+		err := p.UnmarshalText([]byte(test.text))
+		if (err == nil) != test.valid {
+			t.Errorf("Unexpected unmarshal priority %q, error: %v", test.text, err)
+		}
+		if p != test.priority {
+			t.Errorf("Unexpected priority of %q, got %s instead of %s", test.text, p, test.priority)
+		}
+	}
+}
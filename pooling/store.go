@@ -0,0 +1,111 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"sync/atomic"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+)
+
+// Store persists the buckets as jobs, so that the pending buckets survive
+// restarts, the jobs are deleted once finished. It is implemented by
+// storage.Storage.
+type Store interface {
+	CreateJob(*entity.Job) error
+	UpdateJob(*entity.Job) error
+	DeleteJob(id uint64) error
+	PendingJobs(kind string) ([]*entity.Job, error)
+	NextJobID() (uint64, error)
+}
+
+// ResumeFunc rebuilds a bucket from the payload of a persisted job.
+type ResumeFunc func(payload []byte) (Bucket, error)
+
+// Resume puts the pending jobs of the given kind that are interrupted by
+// last shutdown back to the pool, the buckets are rebuilt by fn. It should
+// be called once the service that sends the kind of bucket is ready.
+func (p *Pool) Resume(kind string, fn ResumeFunc) error {
+	if p == nil {
+		return ErrPoolNotExist
+	}
+	if p.store == nil {
+		return nil
+	}
+
+	jobs, err := p.store.PendingJobs(kind)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		b, err := fn(job.Payload)
+		if err != nil {
+			logger.Error("resume job %d failed: %v", job.ID, err)
+			p.transit(job, entity.JobFailed, err)
+			continue
+		}
+		logger.Info("resume %s job %d", kind, job.ID)
+		b.Kind = kind
+		b.job = job
+		p.Put(b)
+	}
+
+	return nil
+}
+
+// persist stores the bucket as a pending job, it is skipped if the bucket
+// has no kind or it was persisted.
+func (p *Pool) persist(b *Bucket) {
+	if p.store == nil || b.Kind == "" || b.job != nil {
+		return
+	}
+
+	job := &entity.Job{Kind: b.Kind, Payload: b.Payload, State: entity.JobQueued}
+	if err := p.store.CreateJob(job); err != nil {
+		logger.Error("persist bucket failed: %v", err)
+		return
+	}
+	b.job = job
+}
+
+// nextID returns the id of the job of the bucket, which is the id of the
+// persisted job or the next id of the store, so that the ids are unique
+// across restarts. The ids are sequenced in memory if the pool has no store.
+func (p *Pool) nextID(b *Bucket) uint64 {
+	if b.job != nil {
+		return b.job.ID
+	}
+	if p.store != nil {
+		id, err := p.store.NextJobID()
+		if err == nil {
+			return id
+		}
+		logger.Error("generate job id failed: %v", err)
+	}
+	return atomic.AddUint64(&p.seq, 1)
+}
+
+func (p *Pool) transit(job *entity.Job, state entity.JobState, err error) {
+	if p.store == nil || job == nil {
+		return
+	}
+
+	if state.Finished() {
+		if err := p.store.DeleteJob(job.ID); err != nil {
+			logger.Error("delete job %d failed: %v", job.ID, err)
+		}
+		return
+	}
+
+	job.State = state
+	job.Error = ""
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if err := p.store.UpdateJob(job); err != nil {
+		logger.Error("update job %d failed: %v", job.ID, err)
+	}
+}
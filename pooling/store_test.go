@@ -0,0 +1,143 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package pooling // import "github.com/wabarc/wayback/pooling"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+)
+
+type memStore struct {
+	mu      sync.Mutex
+	seq     uint64
+	jobs    map[uint64]entity.Job
+	deleted []uint64
+}
+
+func (s *memStore) CreateJob(job *entity.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jobs == nil {
+		s.jobs = make(map[uint64]entity.Job)
+	}
+	s.seq++
+	job.ID = s.seq
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *memStore) UpdateJob(job *entity.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *memStore) DeleteJob(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func (s *memStore) NextJobID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	return s.seq, nil
+}
+
+func (s *memStore) PendingJobs(kind string) (jobs []*entity.Job, _ error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := uint64(1); id <= s.seq; id++ {
+		job, ok := s.jobs[id]
+		if ok && job.Kind == kind && !job.Finished() {
+			jobs = append(jobs, &job)
+		}
+	}
+	return jobs, nil
+}
+
+func TestPersist(t *testing.T) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	store := &memStore{}
+	p := New(context.Background(), Capacity(1), Timeout(time.Second), Storage(store))
+	go p.Roll()
+
+	p.Put(Bucket{Kind: "test", Payload: []byte("ok"), Request: func(_ context.Context) error { return nil }})
+	p.Put(Bucket{Kind: "test", Payload: []byte("fail"), Request: func(_ context.Context) error { return errors.New("failed") }})
+	p.Put(Bucket{Request: func(_ context.Context) error { return nil }})
+	p.Close()
+
+	// The finished jobs are deleted.
+	if len(store.deleted) != 2 || len(store.jobs) != 0 {
+		t.Fatalf("Unexpected persisted jobs got %d deleted and %d left", len(store.deleted), len(store.jobs))
+	}
+
+	// The ids of the jobs are the ids of the store, which survive restarts.
+	p = New(context.Background(), Capacity(1), Timeout(time.Second), Storage(store))
+	if id := p.Put(Bucket{}); id != 4 {
+		t.Fatalf("Unexpected job id got %d instead of 4", id)
+	}
+	if _, err := p.Job(4); err != nil {
+		t.Fatalf("Unexpected job: %v", err)
+	}
+}
+
+func TestResume(t *testing.T) {
+	logger.SetLogLevel(logger.LevelFatal)
+
+	store := &memStore{}
+	store.CreateJob(&entity.Job{Kind: "test", Payload: []byte("foo"), State: entity.JobRunning}) // nolint:errcheck
+	store.CreateJob(&entity.Job{Kind: "test", Payload: []byte("bar"), State: entity.JobDone})    // nolint:errcheck
+	store.CreateJob(&entity.Job{Kind: "other", Payload: []byte("baz"), State: entity.JobQueued}) // nolint:errcheck
+	store.CreateJob(&entity.Job{Kind: "test", Payload: []byte("bad"), State: entity.JobQueued})  // nolint:errcheck
+
+	p := New(context.Background(), Capacity(1), Timeout(time.Second), Storage(store))
+	go p.Roll()
+
+	var mu sync.Mutex
+	var resumed []string
+	err := p.Resume("test", func(payload []byte) (Bucket, error) {
+		if string(payload) == "bad" {
+			return Bucket{}, errors.New("bad payload")
+		}
+		return Bucket{Request: func(_ context.Context) error {
+			mu.Lock()
+			resumed = append(resumed, string(payload))
+			mu.Unlock()
+			return nil
+		}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected resume: %v", err)
+	}
+	p.Close()
+
+	if len(resumed) != 1 || resumed[0] != "foo" {
+		t.Fatalf("Unexpected resumed buckets: %v", resumed)
+	}
+	// The resumed job and the job failed to resume are deleted once finished.
+	if _, ok := store.jobs[1]; ok || len(store.jobs) != 2 {
+		t.Errorf("Unexpected jobs left: %v", store.jobs)
+	}
+	if _, ok := store.jobs[4]; ok {
+		t.Errorf("Unexpected job failed to resume left: %v", store.jobs[4])
+	}
+}
@@ -52,7 +52,7 @@ func New(ctx context.Context, store *storage.Storage, opts *config.Options) *Dat
 }
 
 // Publish save url to the datastore of the given cols and args.
-func (d *Datastore) Publish(ctx context.Context, _ reduxer.Reduxer, cols []wayback.Collect, args ...string) error {
+func (d *Datastore) Publish(ctx context.Context, rdx reduxer.Reduxer, cols []wayback.Collect, args ...string) error {
 	metrics.IncrementPublish(metrics.PublishDatabase, metrics.StatusRequest)
 
 	if len(cols) == 0 {
@@ -60,7 +60,15 @@ func (d *Datastore) Publish(ctx context.Context, _ reduxer.Reduxer, cols []wayba
 		return errors.New("publish to datastore: collects empty")
 	}
 
-	err := d.bot.CreateWayback(ctx, cols)
+	// Reused captures are stored already, storing them again would extend
+	// the freshness window.
+	if cached(cols) {
+		logger.Debug("skip storing reused captures")
+		metrics.IncrementPublish(metrics.PublishDatabase, metrics.StatusSuccess)
+		return nil
+	}
+
+	err := d.bot.CreateWayback(ctx, cols, rdx)
 	if err != nil {
 		metrics.IncrementPublish(metrics.PublishDatabase, metrics.StatusFailure)
 		return err
@@ -74,3 +82,12 @@ func (d *Datastore) Publish(ctx context.Context, _ reduxer.Reduxer, cols []wayba
 func (d *Datastore) Shutdown() error {
 	return d.bot.Close()
 }
+
+func cached(cols []wayback.Collect) bool {
+	for _, col := range cols {
+		if !col.Cached {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,8 @@ package datastore // import "github.com/wabarc/wayback/publish/datastore"
 
 import (
 	"testing"
+
+	"github.com/wabarc/wayback"
 )
 
 func TestPublish(t *testing.T) {
@@ -13,3 +15,23 @@ func TestPublish(t *testing.T) {
 
 func TestShutdown(t *testing.T) {
 }
+
+func TestCached(t *testing.T) {
+	tests := []struct {
+		name string
+		cols []wayback.Collect
+		want bool
+	}{
+		{"all cached", []wayback.Collect{{Arc: "ia", Cached: true}, {Arc: "is", Cached: true}}, true},
+		{"partially cached", []wayback.Collect{{Arc: "ia", Cached: true}, {Arc: "is"}}, false},
+		{"not cached", []wayback.Collect{{Arc: "ia"}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cached(test.cols); got != test.want {
+				t.Errorf("Unexpected cached, got %t instead of %t", got, test.want)
+			}
+		})
+	}
+}
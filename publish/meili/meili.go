@@ -324,8 +324,8 @@ func (m *Meili) documents(cols []wayback.Collect) (docs []document) {
 		}
 		for _, col := range maps {
 			_, err := url.Parse(col.Dst)
-			// If the URI is invalid, the results will be an empty string.
-			if err != nil {
+			// If the URI is invalid or archiving failed, the results will be an empty string.
+			if err != nil || !col.Succeeded() {
 				col.Dst = ""
 			}
 			switch col.Arc {
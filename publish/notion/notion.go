@@ -143,7 +143,7 @@ func (no *Notion) params(cols []wayback.Collect, head, body string) (notion.Crea
 					{Text: &notion.Text{Content: config.SlotName(col.Arc)}, Annotations: &notion.Annotations{Bold: true}},
 				},
 				{
-					{Text: &notion.Text{Content: col.Dst, Link: &notion.Link{URL: col.Dst}}},
+					dest(col),
 				},
 			},
 		}
@@ -367,3 +367,10 @@ func uploadImage(client *imgbb.ImgBB, s string) (string, error) {
 func (no *Notion) Shutdown() error {
 	return nil
 }
+
+func dest(col wayback.Collect) notion.RichText {
+	if col.Succeeded() {
+		return notion.RichText{Text: &notion.Text{Content: col.Dst, Link: &notion.Link{URL: col.Dst}}}
+	}
+	return notion.RichText{Text: &notion.Text{Content: col.Result()}}
+}
@@ -6,7 +6,6 @@ package publish // import "github.com/wabarc/wayback/publish"
 
 import (
 	"context"
-	"sync"
 
 	"github.com/wabarc/logger"
 	"github.com/wabarc/wayback"
@@ -34,6 +33,7 @@ const (
 	FlagMeili                // FlagMeili is a flag for meilisearch publish service
 	FlagOmnivore             // FlagOmnivore is a flag for Omnivore publish service
 	FlagDatabase             // FlagDatabase is a flag for database store publish service
+	FlagSchedule             // FlagSchedule publish from the scheduler of watched URLs
 )
 
 // Publisher is the interface that wraps the basic Publish method.
@@ -78,6 +78,8 @@ func (f Flag) String() string {
 		return "omnivore"
 	case FlagDatabase:
 		return "database"
+	case FlagSchedule:
+		return "schedule"
 	default:
 		return "unknown"
 	}
@@ -114,32 +116,28 @@ func (p *Publish) Start() {
 	p.pool.Roll()
 }
 
-// Stop stop the Publish pooling. It waits until the pool status
-// is idle and then calls Stop on the pool.
-//
-// Stop uses a sync.Once to ensure that Stop is only called once.
+// Stop stop the Publish pooling. It shuts down the publishers and
+// closes the pool, which blocks until all publishing requests are done.
 func (p *Publish) Stop() {
 	exec(func(mod *Module) {
 		_ = mod.Shutdown() // nolint:errcheck
 	})
 
-	var once sync.Once
-	for {
-		if p.pool.Status() == pooling.StatusIdle {
-			once.Do(func() {
-				p.pool.Close()
-			})
-			return
-		}
-	}
+	p.pool.Close()
 }
 
 // Spread accepts calls from services that with collections and various parameters.
-// It prepare all available publishers and put them into pooling.
+// It prepare all available publishers and put them into pooling. The captures
+// unchanged from the previous captures are stored to the database only.
 func (p *Publish) Spread(ctx context.Context, rdx reduxer.Reduxer, cols []wayback.Collect, from Flag, args ...string) {
 	v := ctx.Value(from)
+	changed := Changed(rdx, cols)
 
 	exec(func(mod *Module) {
+		if !changed && mod.Flag != FlagDatabase {
+			logger.Info("skip publishing unchanged captures from [%s] to [%s]", from, mod.Flag)
+			return
+		}
 		bucket := pooling.Bucket{
 			Request: func(ctx context.Context) error {
 				logger.Info("requesting publishing from [%s] to [%s]...", from, mod.Flag)
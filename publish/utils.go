@@ -26,3 +26,18 @@ func Artifact(ctx context.Context, rdx reduxer.Reduxer, cols []wayback.Collect)
 	}
 	return art, errors.New("reduxer data not found")
 }
+
+// Changed reports whether any page of the collects changed from its previous
+// capture, the pages not compared by the change detection are changed.
+func Changed(rdx reduxer.Reduxer, cols []wayback.Collect) bool {
+	if rdx == nil || len(cols) == 0 {
+		return true
+	}
+	for _, col := range cols {
+		bundle, ok := rdx.Load(reduxer.Src(col.Src))
+		if !ok || bundle.Changed() {
+			return true
+		}
+	}
+	return false
+}
@@ -62,9 +62,31 @@ func TestArtifact(t *testing.T) {
 				t.Errorf("expected error %v, but got %v", test.err, err)
 			}
 
-			if art != test.exp {
+			if !reflect.DeepEqual(art, test.exp) {
 				t.Errorf("expected artifact %v, but got %v", test.exp, art)
 			}
 		})
 	}
 }
+
+func TestChanged(t *testing.T) {
+	rdx := reduxer.BundleExample()
+	src := "https://example.com/"
+	cols := []wayback.Collect{{Src: src}}
+
+	if !Changed(rdx, cols) {
+		t.Fatal("expected changed without change detection")
+	}
+	if !Changed(nil, cols) || !Changed(rdx, nil) {
+		t.Fatal("expected changed without reduxer or collects")
+	}
+
+	bundle, _ := rdx.Load(reduxer.Src(src))
+	bundle.SetChange(false, "", "")
+	if Changed(rdx, cols) {
+		t.Fatal("expected unchanged")
+	}
+	if !Changed(rdx, append(cols, wayback.Collect{Src: "https://example.org/"})) {
+		t.Fatal("expected changed of the page not compared")
+	}
+}
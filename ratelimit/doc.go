@@ -0,0 +1,9 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package ratelimit implements the token bucket rate limiter of archiving
+requests, shared by the web service and the chat bots.
+*/
+package ratelimit // import "github.com/wabarc/wayback/ratelimit"
@@ -0,0 +1,141 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package ratelimit // import "github.com/wabarc/wayback/ratelimit"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/metrics"
+	"golang.org/x/time/rate"
+)
+
+// idle is the interval to drop the buckets of users which are full again.
+const idle = 10 * time.Minute
+
+type bucket struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// Limiter limits the archiving requests per user, e.g. an IP address or
+// a chat user, and per service with token buckets. A nil Limiter allows
+// all requests.
+type Limiter struct {
+	mu sync.Mutex
+
+	user    rate.Limit
+	service rate.Limit
+	burst   int
+
+	users    map[string]*bucket
+	services map[string]*bucket
+	swept    time.Time
+
+	now func() time.Time
+}
+
+// New returns a Limiter that allows perUser requests per minute from a
+// single user with bursts of burst requests, and perService requests per
+// minute from a single service. Zero means unlimited.
+func New(perUser, perService, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		user:     perMinute(perUser),
+		service:  perMinute(perService),
+		burst:    burst,
+		users:    make(map[string]*bucket),
+		services: make(map[string]*bucket),
+		now:      time.Now,
+	}
+}
+
+// FromConfig returns a Limiter configured by the given options.
+func FromConfig(opts *config.Options) *Limiter {
+	return New(opts.RateLimitUser(), opts.RateLimitService(), opts.RateLimitBurst())
+}
+
+func perMinute(n int) rate.Limit {
+	if n <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(float64(n) / time.Minute.Seconds())
+}
+
+// Allow reports whether a request of the user from the service may be
+// processed now, it consumes a token of both buckets if so.
+func (l *Limiter) Allow(service, user string) bool {
+	return l.Reserve(service, user) == 0
+}
+
+// Reserve consumes a token of the buckets of the user and the service and
+// returns zero if the request may be processed now. Otherwise, it consumes
+// nothing and returns the duration to wait before the next attempt.
+func (l *Limiter) Reserve(service, user string) time.Duration {
+	if l == nil || (l.user == rate.Inf && l.service == rate.Inf) {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	var limiters []*rate.Limiter
+	if l.user != rate.Inf {
+		limiters = append(limiters, l.get(l.users, service+":"+user, l.user, l.burst, now))
+	}
+	if l.service != rate.Inf {
+		burst := int(l.service * rate.Limit(time.Minute.Seconds()))
+		limiters = append(limiters, l.get(l.services, service, l.service, burst, now))
+	}
+
+	var delay time.Duration
+	reservations := make([]*rate.Reservation, 0, len(limiters))
+	for _, lim := range limiters {
+		r := lim.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+	if delay > 0 {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+		metrics.IncrementRateLimit(service)
+	}
+
+	return delay
+}
+
+func (l *Limiter) get(buckets map[string]*bucket, key string, limit rate.Limit, burst int, now time.Time) *rate.Limiter {
+	b, ok := buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(limit, burst)}
+		buckets[key] = b
+	}
+	b.seen = now
+	return b.limiter
+}
+
+// sweep drops the buckets of users that have been idle long enough to
+// be full again, which are the same as new ones.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < idle {
+		return
+	}
+	l.swept = now
+
+	for key, b := range l.users {
+		if now.Sub(b.seen) >= idle && b.limiter.TokensAt(now) >= float64(b.limiter.Burst()) {
+			delete(l.users, key)
+		}
+	}
+}
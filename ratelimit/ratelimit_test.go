@@ -0,0 +1,111 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package ratelimit // import "github.com/wabarc/wayback/ratelimit"
+
+import (
+	"testing"
+	"time"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) add(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newLimiter(perUser, perService, burst int) (*Limiter, *clock) {
+	c := &clock{t: time.Unix(0, 0)}
+	l := New(perUser, perService, burst)
+	l.now = c.now
+	return l, c
+}
+
+func TestLimiterNil(t *testing.T) {
+	var l *Limiter
+	for i := 0; i < 10; i++ {
+		if !l.Allow("web", "127.0.0.1") {
+			t.Fatal("Unexpected nil limiter rejects request")
+		}
+	}
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l, _ := newLimiter(0, 0, 1)
+	for i := 0; i < 100; i++ {
+		if !l.Allow("web", "127.0.0.1") {
+			t.Fatal("Unexpected unlimited limiter rejects request")
+		}
+	}
+}
+
+func TestLimiterUser(t *testing.T) {
+	l, c := newLimiter(6, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		if !l.Allow("telegram", "1") {
+			t.Fatalf("Unexpected request %d rejected within burst", i)
+		}
+	}
+	delay := l.Reserve("telegram", "1")
+	if delay != 10*time.Second {
+		t.Fatalf("Unexpected delay got %s instead of 10s", delay)
+	}
+	if !l.Allow("telegram", "2") {
+		t.Fatal("Unexpected request of another user rejected")
+	}
+	if !l.Allow("discord", "1") {
+		t.Fatal("Unexpected request of the same user from another service rejected")
+	}
+
+	c.add(delay)
+	if !l.Allow("telegram", "1") {
+		t.Fatal("Unexpected request rejected after delay")
+	}
+	if l.Allow("telegram", "1") {
+		t.Fatal("Unexpected request allowed before delay")
+	}
+}
+
+func TestLimiterService(t *testing.T) {
+	l, c := newLimiter(60, 2, 5)
+
+	if !l.Allow("slack", "1") || !l.Allow("slack", "2") {
+		t.Fatal("Unexpected request rejected within service quota")
+	}
+	if l.Allow("slack", "3") {
+		t.Fatal("Unexpected request allowed over service quota")
+	}
+	if !l.Allow("irc", "1") {
+		t.Fatal("Unexpected request of another service rejected")
+	}
+
+	c.add(30 * time.Second)
+	if !l.Allow("slack", "3") {
+		t.Fatal("Unexpected request rejected after delay")
+	}
+	if got := l.users["slack:3"].limiter.TokensAt(c.now()); got != 4 {
+		t.Fatalf("Unexpected tokens of user got %v instead of 4, rejected request consumed token", got)
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l, c := newLimiter(60, 0, 1)
+
+	l.Allow("web", "1")
+	c.add(idle)
+	l.Allow("web", "2")
+	if _, ok := l.users["web:1"]; ok {
+		t.Fatal("Unexpected idle bucket not swept")
+	}
+	if _, ok := l.users["web:2"]; !ok {
+		t.Fatal("Unexpected active bucket swept")
+	}
+}
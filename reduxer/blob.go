@@ -0,0 +1,325 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/errors"
+)
+
+// BlobsDir is the directory of the blobs under the storage directory.
+const BlobsDir = "blobs"
+
+// refsExt is the extension of the files recording the references of the blobs.
+const refsExt = ".refs"
+
+// blobPerm is the mode of the blobs, which are read-only to keep the names
+// linked to them from being changed in place.
+var blobPerm = os.FileMode(0o400)
+
+// lockFile is the file under the directory of the blobs locked to serialize
+// the changes of the blobs across the processes.
+const lockFile = ".lock"
+
+// blobsMu serializes the changes of the blobs within the process.
+var blobsMu sync.Mutex
+
+// Blobs represents the content-addressed blobs of the artifacts under the
+// storage directory.
+//
+// The artifacts are stored once by their SHA-256 digests and linked under
+// their human-readable names, hard links are preferred and symbolic links
+// are the fallback. The names linked to a blob are recorded as its
+// references, and the blobs without references are reclaimed by GC.
+type Blobs struct {
+	root string
+	dir  string
+}
+
+// NewBlobs returns the Blobs of the storage directory.
+func NewBlobs(root string) *Blobs {
+	return &Blobs{root: root, dir: filepath.Join(root, BlobsDir)}
+}
+
+// GCStats represents the results of a garbage collection of the blobs.
+type GCStats struct {
+	Blobs int   // Number of the blobs removed
+	Bytes int64 // Size of the blobs removed
+}
+
+func (b *Blobs) path(sum string) string {
+	return filepath.Join(b.dir, sum[:2], sum)
+}
+
+// Dedup stores the file of the name as a blob if its content is not stored
+// yet, links the name to the blob and returns the digest of the content.
+func (b *Blobs) Dedup(name string) (string, error) {
+	_, sum, err := Asset{Local: name}.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	blob := b.path(sum)
+	// nosemgrep
+	if err = os.MkdirAll(filepath.Dir(blob), 0o755); err != nil {
+		return "", err
+	}
+
+	blobsMu.Lock()
+	defer blobsMu.Unlock()
+	unlock, err := b.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	// The reference is recorded before linking, a reference to a name not
+	// linked is dropped by GC, while a linked name without a reference
+	// loses its blob.
+	if err = b.addRef(sum, name); err != nil {
+		return "", err
+	}
+
+	if !helper.Exists(blob) {
+		if err = os.Link(name, blob); err == nil {
+			return sum, os.Chmod(blob, blobPerm)
+		}
+		// Hard links are not supported, move the file to the blob and link
+		// the name to it symbolically.
+		if err = os.Rename(name, blob); err != nil {
+			return "", err
+		}
+		if err = symlink(blob, name); err != nil {
+			if e := os.Rename(blob, name); e != nil {
+				logger.Error("restore %s from blob %s failed: %v", name, sum, e)
+			}
+			return "", err
+		}
+		return sum, os.Chmod(blob, blobPerm)
+	}
+
+	if linked(name, blob) {
+		return sum, nil
+	}
+	// The modification time of a blob is the last time it was archived.
+	now := time.Now()
+	if err = os.Chtimes(blob, now, now); err != nil {
+		logger.Warn("touch blob %s failed: %v", sum, err)
+	}
+
+	// Replace the file with a link to the blob atomically.
+	tmp := name + ".link"
+	os.Remove(tmp)
+	if err = os.Link(blob, tmp); err != nil {
+		if err = symlink(blob, tmp); err != nil {
+			return "", err
+		}
+	}
+	// Never replace the file with a link to a blob removed meanwhile, which
+	// is the only copy of the content.
+	if !linked(tmp, blob) {
+		os.Remove(tmp)
+		return "", errors.New("blob " + sum + " vanished")
+	}
+	if err = os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return sum, nil
+}
+
+// Refs returns the names referencing the blob of the digest, relative to the
+// storage directory.
+func (b *Blobs) Refs(sum string) ([]string, error) {
+	f, err := os.Open(b.path(sum) + refsExt)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	refs := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if ref := strings.TrimSpace(scanner.Text()); ref != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, scanner.Err()
+}
+
+// Blob represents a blob with the names referencing it.
+type Blob struct {
+	Sum  string
+	Size int64
+	Refs []string // Names relative to the storage directory
+}
+
+// List returns the blobs with their references.
+func (b *Blobs) List() ([]Blob, error) {
+	blobs := []Blob{}
+	err := filepath.WalkDir(b.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != "" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		refs, err := b.Refs(d.Name())
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, Blob{Sum: d.Name(), Size: info.Size(), Refs: refs})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return blobs, err
+}
+
+// GC drops the references of the names no longer linked to their blobs and
+// removes the blobs without references.
+func (b *Blobs) GC() (stats GCStats, err error) {
+	blobsMu.Lock()
+	defer blobsMu.Unlock()
+	unlock, err := b.lock()
+	if err != nil {
+		return stats, err
+	}
+	defer unlock()
+
+	err = filepath.WalkDir(b.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip the directories and the files of the references.
+		if d.IsDir() || filepath.Ext(path) != "" {
+			return nil
+		}
+		sum := d.Name()
+		refs, err := b.Refs(sum)
+		if err != nil {
+			return err
+		}
+		alive := []string{}
+		for _, ref := range refs {
+			if linked(filepath.Join(b.root, filepath.FromSlash(ref)), path) {
+				alive = append(alive, ref)
+			}
+		}
+		if len(alive) > 0 {
+			if len(alive) < len(refs) {
+				return b.writeRefs(sum, alive)
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+		if err = os.Remove(path + refsExt); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		logger.Debug("removed unreferenced blob %s", sum)
+		stats.Blobs++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return stats, err
+}
+
+func (b *Blobs) addRef(sum, name string) error {
+	ref, err := filepath.Rel(b.root, name)
+	if err != nil {
+		return err
+	}
+	ref = filepath.ToSlash(ref)
+	refs, err := b.Refs(sum)
+	if err != nil || slices.Contains(refs, ref) {
+		return err
+	}
+
+	f, err := os.OpenFile(b.path(sum)+refsExt, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+	if _, err = f.WriteString(ref + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (b *Blobs) writeRefs(sum string, refs []string) error {
+	name := b.path(sum) + refsExt
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(refs, "\n")+"\n"), filePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// linked reports whether the name is linked to the blob, either hard or
+// symbolically.
+func linked(name, blob string) bool {
+	a, err := os.Stat(name)
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(blob)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(a, b)
+}
+
+// symlink links the name to the blob symbolically with a relative path.
+func symlink(blob, name string) error {
+	target, err := filepath.Rel(filepath.Dir(name), blob)
+	if err != nil {
+		target = blob
+	}
+	return os.Symlink(target, name)
+}
+
+// dedup stores the local files of the artifact as blobs, the files failed to
+// store are kept as they are.
+func dedup(blobs *Blobs, artifact *Artifact) {
+	for _, asset := range []*Asset{
+		&artifact.Img, &artifact.PDF, &artifact.Raw, &artifact.Txt,
+		&artifact.HAR, &artifact.HTM, &artifact.WARC, &artifact.Media,
+	} {
+		if asset.Local == "" || !helper.Exists(asset.Local) {
+			continue
+		}
+		if _, err := blobs.Dedup(asset.Local); err != nil {
+			logger.Warn("dedup %s failed: %v", asset.Local, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lock locks the blobs exclusively against the other processes sharing the
+// storage directory, e.g. `wayback gc` while the service is archiving, and
+// returns the function to unlock them. It does nothing if the directory of
+// the blobs does not exist.
+func (b *Blobs) lock() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(b.dir, lockFile), os.O_CREATE|os.O_RDWR, filePerm)
+	if os.IsNotExist(err) {
+		return func() {}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,13 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+// lock does nothing on Windows, the blobs are serialized within the process
+// only, run `wayback gc` while the service is stopped.
+func (b *Blobs) lock() (func(), error) {
+	return func() {}, nil
+}
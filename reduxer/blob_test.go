@@ -0,0 +1,145 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/wabarc/helper"
+)
+
+func TestBlobs(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		fp := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(content), filePerm); err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+	a := write("202501/a.txt", "hello")
+	b := write("202502/b.txt", "hello")
+	c := write("202502/c.txt", "world")
+
+	blobs := NewBlobs(root)
+	sum, err := blobs.Dedup(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("unexpected digest: %s", sum)
+	}
+	// Deduplicating a linked name again is a no-op.
+	for _, name := range []string{a, b, b} {
+		if s, err := blobs.Dedup(name); err != nil || s != sum {
+			t.Fatalf("unexpected digest %s of %s, error: %v", s, name, err)
+		}
+	}
+	if _, err = blobs.Dedup(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blob := blobs.path(sum)
+	if !linked(a, blob) || !linked(b, blob) || linked(c, blob) {
+		t.Fatal("unexpected links to the blob")
+	}
+	if buf, _ := os.ReadFile(b); string(buf) != "hello" {
+		t.Errorf("unexpected content of the linked name: %s", buf)
+	}
+	if info, err := os.Stat(blob); err != nil || info.Mode().Perm() != blobPerm {
+		t.Errorf("unexpected mode of the blob: %v, error: %v", info.Mode(), err)
+	}
+	refs, err := blobs.Refs(sum)
+	if err != nil || len(refs) != 2 || refs[0] != "202501/a.txt" || refs[1] != "202502/b.txt" {
+		t.Fatalf("unexpected references %v, error: %v", refs, err)
+	}
+
+	list, err := blobs.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("unexpected blobs %+v, error: %v", list, err)
+	}
+	for _, blob := range list {
+		if blob.Sum == sum && (blob.Size != 5 || len(blob.Refs) != 2) {
+			t.Errorf("unexpected blob: %+v", blob)
+		}
+	}
+
+	// The blob referenced by a name is kept.
+	if err = os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := blobs.GC()
+	if err != nil || stats.Blobs != 0 {
+		t.Fatalf("unexpected gc %+v, error: %v", stats, err)
+	}
+	if refs, _ = blobs.Refs(sum); len(refs) != 1 || refs[0] != "202502/b.txt" {
+		t.Errorf("unexpected references after gc: %v", refs)
+	}
+
+	// A name replaced by another file no longer references the blob.
+	if err = os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	write("202502/b.txt", "changed")
+	if stats, err = blobs.GC(); err != nil || stats.Blobs != 1 || stats.Bytes != 5 {
+		t.Fatalf("unexpected gc %+v, error: %v", stats, err)
+	}
+	if helper.Exists(blob) || helper.Exists(blob+refsExt) {
+		t.Error("unexpected unreferenced blob")
+	}
+	if buf, _ := os.ReadFile(c); string(buf) != "world" {
+		t.Errorf("unexpected content of the other name: %s", buf)
+	}
+
+	if stats, err = NewBlobs(filepath.Join(root, "missing")).GC(); err != nil || stats.Blobs != 0 {
+		t.Errorf("unexpected gc of missing blobs %+v, error: %v", stats, err)
+	}
+}
+
+func TestBlobsLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the blobs are not locked across the processes on Windows")
+	}
+
+	root := t.TempDir()
+	blobs := NewBlobs(root)
+	if err := os.MkdirAll(blobs.dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// The locks of the other processes are the same as the ones of the
+	// other open files.
+	unlock, err := blobs.lock()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := blobs.GC()
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("unexpected garbage collection while the blobs are locked")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unexpected garbage collection blocked after unlocked")
+	}
+}
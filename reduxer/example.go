@@ -14,52 +14,34 @@ func BundleExample() Reduxer {
 	bnd := &bundle{
 		artifact: Artifact{
 			Img: Asset{
-				Local: "/path/to/image",
-				Remote: Remote{
-					Catbox: "https://files.catbox.moe/9u6yvu.png",
-				},
+				Local:  "/path/to/image",
+				Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/9u6yvu.png"}},
 			},
 			PDF: Asset{
-				Local: "/path/to/pdf",
-				Remote: Remote{
-					Catbox: "https://files.catbox.moe/q73uqh.pdf",
-				},
+				Local:  "/path/to/pdf",
+				Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/q73uqh.pdf"}},
 			},
 			Raw: Asset{
-				Local: "/path/to/htm",
-				Remote: Remote{
-					Catbox: "https://files.catbox.moe/bph1g6.htm",
-				},
+				Local:  "/path/to/htm",
+				Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/bph1g6.htm"}},
 			},
 			Txt: Asset{
-				Local: "/path/to/txt",
-				Remote: Remote{
-					Catbox: "https://files.catbox.moe/wwrby6.txt",
-				},
+				Local:  "/path/to/txt",
+				Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/wwrby6.txt"}},
 			},
 			HAR: Asset{
-				Local: "/path/to/har",
-				Remote: Remote{
-					Catbox: "https://files.catbox.moe/3agtva.har",
-				},
+				Local:  "/path/to/har",
+				Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/3agtva.har"}},
 			},
 			HTM: Asset{
 				Local: "/path/to/single-htm",
-				Remote: Remote{
-					Catbox: "",
-				},
 			},
 			WARC: Asset{
-				Local: "/path/to/warc",
-				Remote: Remote{
-					Catbox: "invalid-url-moe/kkai0w.warc",
-				},
+				Local:  "/path/to/warc",
+				Remote: Remote{{Store: StoreCatbox, URL: "invalid-url-moe/kkai0w.warc"}},
 			},
 			Media: Asset{
 				Local: "",
-				Remote: Remote{
-					Catbox: "",
-				},
 			},
 		},
 		shots: &screenshot.Screenshots[screenshot.Path]{
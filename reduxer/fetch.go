@@ -0,0 +1,78 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/screenshot"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/ingress"
+)
+
+// maxPageSize limits the size of the pages fetched without browsers.
+const maxPageSize = 32 << 20
+
+// fetch returns screenshot.Screenshots of the URL fetched by the HTTP client
+// without browsers, which has the raw HTML and the title only, the
+// screenshot, the PDF and the HAR require browsers.
+func fetch(ctx context.Context, cfg *config.Options, uri *url.URL, dir string, profile Profile) (*screenshot.Screenshots[screenshot.Path], error) {
+	shot := &screenshot.Screenshots[screenshot.Path]{URL: uri.String()}
+	if !profile.html() {
+		return shot, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return shot, errors.Wrap(err, "create request failed")
+	}
+	req.Header.Set("User-Agent", cfg.WaybackUserAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+
+	resp, err := ingress.Client().Do(req)
+	if err != nil {
+		return shot, errors.Wrap(err, "fetch page failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return shot, errors.New(fmt.Sprintf("fetch page failed, status: %s", resp.Status))
+	}
+	if typ, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); typ != "" && typ != "text/html" && typ != "application/xhtml+xml" {
+		logger.Debug("skip the raw html of %s, content type: %s", uri, typ)
+		return shot, nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return shot, errors.Wrap(err, "read page failed")
+	}
+	shot.DataLength = int64(len(buf))
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(buf)); err == nil {
+		shot.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+
+	path := filepath.Join(dir, basename(ctx)+".html")
+	if err = os.WriteFile(path, buf, filePerm); err != nil {
+		return shot, errors.Wrap(err, "write page failed")
+	}
+	shot.HTML = screenshot.Path(path)
+
+	return shot, nil
+}
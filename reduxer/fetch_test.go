@@ -0,0 +1,127 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/config"
+)
+
+func TestFetch(t *testing.T) {
+	_, mux, server := helper.MockServer()
+	mux.HandleFunc("/", handleResponse)
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, _ *http.Request) {
+		http.NotFound(w, nil)
+	})
+	defer server.Close()
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	full, _ := ParseProfile(ProfileFull)
+	dir := t.TempDir()
+	ctx := context.WithValue(context.Background(), ctxBasenameKey, "page") // nolint:staticcheck
+
+	var tests = []struct {
+		name    string
+		path    string
+		profile Profile
+		title   string
+		html    bool
+		err     bool
+	}{
+		{"html", "/", full, "Example Domain", true, false},
+		{"image", "/image.png", full, "", false, false},
+		{"not found", "/missing", full, "", false, true},
+		{"without html", "/", Profile{"img": true, "pdf": true}, "", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uri, _ := url.Parse(server.URL + test.path)
+			shot, err := fetch(ctx, opts, uri, dir, test.profile)
+			if test.err {
+				if err == nil {
+					t.Fatal("Unexpected fetch without error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected fetch error: %v", err)
+			}
+			if shot.URL != uri.String() {
+				t.Errorf("Unexpected url, got %s instead of %s", shot.URL, uri)
+			}
+			if shot.Title != test.title {
+				t.Errorf("Unexpected title, got %q instead of %q", shot.Title, test.title)
+			}
+			if (shot.HTML != "") != test.html {
+				t.Fatalf("Unexpected raw html %q", shot.HTML)
+			}
+			if shot.Image != "" || shot.PDF != "" || shot.HAR != "" {
+				t.Errorf("Unexpected files of browsers: %#v", shot)
+			}
+			if test.html {
+				buf, err := os.ReadFile(string(shot.HTML))
+				if err != nil || string(buf) != content {
+					t.Errorf("Unexpected raw html content: %s, error: %v", buf, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDoWithoutBrowser(t *testing.T) {
+	if _, err := exec.LookPath(helper.FindChromeExecPath()); err == nil {
+		t.Skip("Chrome headless browser found, skipped")
+	}
+
+	_, mux, server := helper.MockServer()
+	mux.HandleFunc("/", handleResponse)
+	defer server.Close()
+
+	os.Clearenv()
+	os.Setenv("WAYBACK_STORAGE_DIR", t.TempDir())
+	os.Setenv("WAYBACK_ARTIFACT_STORES", "none")
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	uri, _ := url.Parse(server.URL)
+	rdx, err := Do(context.Background(), opts, uri)
+	if err != nil {
+		t.Fatalf("Unexpected execute do: %v", err)
+	}
+	bundle, ok := rdx.Load(Src(uri.String()))
+	if !ok {
+		t.Fatal("Unexpected bundles")
+	}
+
+	art := bundle.Artifact()
+	if art.Img.Local != "" || art.PDF.Local != "" || art.HAR.Local != "" {
+		t.Errorf("Unexpected files of browsers: %#v", art)
+	}
+	for kind, asset := range map[string]Asset{"raw": art.Raw, "htm": art.HTM, "txt": art.Txt, "warc": art.WARC} {
+		if asset.Local == "" || !helper.Exists(asset.Local) {
+			t.Errorf("Unexpected %s file %q", kind, asset.Local)
+		}
+	}
+	if bundle.Shots().Title != "Example Domain" {
+		t.Errorf("Unexpected title %q", bundle.Shots().Title)
+	}
+	if !strings.Contains(bundle.Article().TextContent, "illustrative examples") {
+		t.Errorf("Unexpected article text %q", bundle.Article().TextContent)
+	}
+}
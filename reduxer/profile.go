@@ -0,0 +1,118 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+)
+
+// Profiles of the artifacts.
+const (
+	// ProfileMinimal produces the screenshot, the readable text and the summary.
+	ProfileMinimal = "minimal"
+	// ProfileFull produces all the artifacts.
+	ProfileFull = "full"
+)
+
+// Kinds of the artifacts selectable by the profiles, summary is the summary
+// of the readable text rather than a file.
+var kinds = []string{"img", "pdf", "raw", "txt", "har", "htm", "warc", "media", "summary"}
+
+var profiles = map[string][]string{
+	ProfileMinimal: {"img", "txt", "summary"},
+	ProfileFull:    kinds,
+}
+
+type ctxProfileKey struct{}
+
+// Profile represents the kinds of the artifacts to produce.
+type Profile map[string]bool
+
+// ParseProfile parses the profile of the artifacts, which is one of minimal
+// and full, or a comma-separated list of the kinds, e.g. "img,txt,warc". An
+// empty string is the full profile.
+func ParseProfile(s string) (Profile, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		s = ProfileFull
+	}
+	if names, ok := profiles[s]; ok {
+		return newProfile(names), nil
+	}
+
+	names := []string{}
+	for _, kind := range strings.Split(s, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(kinds, kind) {
+			return nil, errors.New(fmt.Sprintf("unknown artifact kind %q, expected %s, %s or a list of %s",
+				kind, ProfileMinimal, ProfileFull, strings.Join(kinds, ", ")))
+		}
+		names = append(names, kind)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no artifact kinds specified")
+	}
+	return newProfile(names), nil
+}
+
+func newProfile(names []string) Profile {
+	p := make(Profile, len(names))
+	for _, name := range names {
+		p[name] = true
+	}
+	return p
+}
+
+// Has reports whether the profile produces the kind of artifacts.
+func (p Profile) Has(kind string) bool {
+	return p[kind]
+}
+
+// String returns the kinds of the profile separated by commas in a stable order.
+func (p Profile) String() string {
+	names := []string{}
+	for _, kind := range kinds {
+		if p[kind] {
+			names = append(names, kind)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// html reports whether the profile requires the raw HTML of the page, which
+// is the source of the single file, the readable text and the summary.
+func (p Profile) html() bool {
+	return p.Has("raw") || p.Has("htm") || p.Has("txt") || p.Has("summary")
+}
+
+// WithProfile returns a copy of ctx which produces the artifacts of the
+// profile instead of the one configured by the options.
+func WithProfile(ctx context.Context, p Profile) context.Context {
+	return context.WithValue(ctx, ctxProfileKey{}, p)
+}
+
+// profileOf returns the profile of the context if set, or the one configured
+// by the options, an invalid profile configured falls back to the full profile.
+func profileOf(ctx context.Context, opts *config.Options) Profile {
+	if p, ok := ctx.Value(ctxProfileKey{}).(Profile); ok && len(p) > 0 {
+		return p
+	}
+	p, err := ParseProfile(opts.Artifacts())
+	if err != nil {
+		logger.Warn("invalid artifact profile %q, fallback to %s: %v", opts.Artifacts(), ProfileFull, err)
+		return newProfile(kinds)
+	}
+	return p
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestParseProfile(t *testing.T) {
+	var tests = []struct {
+		profile string
+		exp     string
+		err     bool
+	}{
+		{"", "img,pdf,raw,txt,har,htm,warc,media,summary", false},
+		{"full", "img,pdf,raw,txt,har,htm,warc,media,summary", false},
+		{"Minimal", "img,txt,summary", false},
+		{"warc, img", "img,warc", false},
+		{"img,,txt", "img,txt", false},
+		{"img,foo", "", true},
+		{",", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.profile, func(t *testing.T) {
+			p, err := ParseProfile(test.profile)
+			if test.err {
+				if err == nil {
+					t.Fatalf("Unexpected parse profile %q without error", test.profile)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected parse profile %q error: %v", test.profile, err)
+			}
+			if got := p.String(); got != test.exp {
+				t.Errorf("Unexpected profile, got %s instead of %s", got, test.exp)
+			}
+		})
+	}
+}
+
+func TestProfileHTML(t *testing.T) {
+	for profile, exp := range map[string]bool{
+		"img,pdf": false,
+		"img,txt": true,
+		"htm":     true,
+		"summary": true,
+	} {
+		p, _ := ParseProfile(profile)
+		if got := p.html(); got != exp {
+			t.Errorf("Unexpected raw html of profile %s, got %t instead of %t", profile, got, exp)
+		}
+	}
+}
+
+func TestProfileOf(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_ARTIFACTS", "minimal")
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	ctx := context.Background()
+	if got := profileOf(ctx, opts).String(); got != "img,txt,summary" {
+		t.Errorf("Unexpected configured profile, got %s", got)
+	}
+
+	p, _ := ParseProfile("pdf")
+	if got := profileOf(WithProfile(ctx, p), opts).String(); got != "pdf" {
+		t.Errorf("Unexpected profile of the context, got %s", got)
+	}
+
+	os.Setenv("WAYBACK_ARTIFACTS", "foo")
+	opts, _ = config.NewParser().ParseEnvironmentVariables()
+	if got := profileOf(ctx, opts).String(); got != "img,pdf,raw,txt,har,htm,warc,media,summary" {
+		t.Errorf("Unexpected profile of an invalid one, got %s", got)
+	}
+}
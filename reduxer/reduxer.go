@@ -7,6 +7,8 @@ package reduxer // import "github.com/wabarc/wayback/reduxer"
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/url"
@@ -19,14 +21,12 @@ import (
 
 	"github.com/go-shiori/go-readability"
 	"github.com/go-shiori/obelisk"
-	"github.com/wabarc/go-catbox"
 	"github.com/wabarc/helper"
 	"github.com/wabarc/logger"
 	"github.com/wabarc/screenshot"
 	"github.com/wabarc/warcraft"
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/errors"
-	"github.com/wabarc/wayback/ingress"
 	"github.com/wabarc/wayback/summary"
 	"golang.org/x/sync/errgroup"
 )
@@ -55,26 +55,65 @@ type Reduxer interface {
 
 // bundle represents a bundle data of a webpage.
 type bundle struct {
-	shots    *screenshot.Screenshots[screenshot.Path]
-	artifact Artifact
-	article  readability.Article
-	summary  string
+	shots     *screenshot.Screenshots[screenshot.Path]
+	artifact  Artifact
+	article   readability.Article
+	summary   string
+	unchanged bool
 }
 
 // Artifact represents the file paths stored on the local disk.
+//
+// Diff and DiffImg are the text diff and the visual diff from the previous
+// capture of the same URL, they are set by the change detection.
 type Artifact struct {
 	Img, PDF, Raw, Txt, HAR, HTM, WARC, Media Asset
+	Diff, DiffImg                             Asset
 }
 
-// Asset represents the files on the local disk and the remote servers.
+// Asset represents the files on the local disk and the artifact stores.
 type Asset struct {
 	Remote Remote
 	Local  string
 }
 
-// Remote represents the file on the remote server.
-type Remote struct {
-	Catbox string
+// KindAsset represents an asset of an Artifact with its kind, which is one
+// of img, pdf, raw, txt, har, htm, warc, media, diff and diff-img.
+type KindAsset struct {
+	Kind string
+	Asset
+}
+
+// Assets returns the non-empty assets of the artifact in a stable order.
+func (a Artifact) Assets() []KindAsset {
+	assets := []KindAsset{
+		{"img", a.Img}, {"pdf", a.PDF}, {"raw", a.Raw}, {"txt", a.Txt},
+		{"har", a.HAR}, {"htm", a.HTM}, {"warc", a.WARC}, {"media", a.Media},
+		{"diff", a.Diff}, {"diff-img", a.DiffImg},
+	}
+	nonempty := assets[:0]
+	for _, asset := range assets {
+		if asset.Local != "" || len(asset.Remote) > 0 {
+			nonempty = append(nonempty, asset)
+		}
+	}
+	return nonempty
+}
+
+// Digest returns the size and the hex encoded SHA-256 digest of the local
+// file of the asset.
+func (a Asset) Digest() (size int64, sum string, err error) {
+	f, err := os.Open(a.Local)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if size, err = io.Copy(h, f); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // Src represents the requested url.
@@ -142,8 +181,26 @@ func (b *bundle) Summary() string {
 	return b.summary
 }
 
+// Changed reports whether the page changed from the previous capture of the
+// same URL, it is true unless the change detection found no changes.
+func (b *bundle) Changed() bool {
+	return !b.unchanged
+}
+
+// SetChange sets the result of the change detection with the local files of
+// the text diff and the visual diff, which are empty if not generated.
+func (b *bundle) SetChange(changed bool, diff, diffImg string) {
+	b.unchanged = !changed
+	b.artifact.Diff.Local = diff
+	b.artifact.DiffImg.Local = diffImg
+}
+
 // Do executes secreenshot, print PDF and export html of given URLs
 // Returns a set of bundle containing screenshot data and file path
+//
+// If no browser is found, the pages are fetched by the HTTP client instead,
+// the other artifacts are produced as usual except the screenshot, the PDF
+// and the HAR.
 // nolint:gocyclo
 func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, error) {
 	// Returns an initialized Reduxer for safe.
@@ -154,10 +211,14 @@ func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, e
 		return bs, errors.New("Specify directory to environment `WAYBACK_STORAGE_DIR` to enable reduxer")
 	}
 
-	// No supported browser found, returns empty results to ensure archives are normal.
-	if _, err = exec.LookPath(helper.FindChromeExecPath()); err != nil {
-		logger.Debug("No browser detected, no artifacts scraped.")
-		return bs, nil
+	// No supported browser found, the pages are fetched directly and the
+	// screenshot, the PDF and the HAR are skipped.
+	var browser = opts.ChromeRemoteAddr() != ""
+	if _, err = exec.LookPath(helper.FindChromeExecPath()); err == nil {
+		browser = true
+	}
+	if !browser {
+		logger.Debug("No browser detected, fetching the pages without browsers.")
 	}
 
 	dir, err := createDir(opts.StorageDir())
@@ -165,6 +226,9 @@ func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, e
 		return bs, errors.Wrap(err, "create storage directory failed")
 	}
 
+	var profile = profileOf(ctx, opts)
+	var stores = ArtifactStores(opts)
+	var blobs = NewBlobs(opts.StorageDir())
 	var warc = &warcraft.Warcraft{BasePath: dir, UserAgent: opts.WaybackUserAgent()}
 	var craft = func(ctx context.Context, in *url.URL) (path string) {
 		ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -186,18 +250,36 @@ func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, e
 			basename = strings.TrimSuffix(basename, ".htm")
 			ctx = context.WithValue(ctx, ctxBasenameKey, basename) // nolint:staticcheck
 
-			shot, er := capture(ctx, opts, uri, dir)
+			var shot *screenshot.Screenshots[screenshot.Path]
+			var er error
+			if browser {
+				shot, er = capture(ctx, opts, uri, dir, profile)
+			} else if shot, er = fetch(ctx, opts, uri, dir, profile); er != nil {
+				// The failures without browsers are not fatal to keep the
+				// archives normal, the artifacts not depending on the page
+				// are still produced.
+				logger.Warn("fetch %s without browsers failed: %v", uri, er)
+				er = nil
+			}
 			if er != nil {
 				return errors.Wrap(er, "capture failed")
 			}
 			logger.Debug("capture results: %#v", shot)
 
 			artifact := &Artifact{
-				Img:  Asset{Local: fmt.Sprint(shot.Image)},
-				Raw:  Asset{Local: fmt.Sprint(shot.HTML)},
-				PDF:  Asset{Local: fmt.Sprint(shot.PDF)},
-				HAR:  Asset{Local: fmt.Sprint(shot.HAR)},
-				WARC: Asset{Local: craft(ctx, uri)},
+				PDF: Asset{Local: fmt.Sprint(shot.PDF)},
+				HAR: Asset{Local: fmt.Sprint(shot.HAR)},
+			}
+			if profile.Has("img") {
+				artifact.Img.Local = fmt.Sprint(shot.Image)
+			} else {
+				discard(&shot.Image)
+			}
+			if profile.Has("raw") {
+				artifact.Raw.Local = fmt.Sprint(shot.HTML)
+			}
+			if profile.Has("warc") {
+				artifact.WARC.Local = craft(ctx, uri)
 			}
 
 			fp := filepath.Join(dir, basename)
@@ -208,37 +290,53 @@ func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, e
 				url:  shot.URL,
 			}
 
-			if supportedMediaSite(uri) {
+			if profile.Has("media") && supportedMediaSite(uri) {
 				artifact.Media.Local = m.download(ctx, opts)
 			}
-			// Attach single file
-			var buf []byte
+
+			var sum string
 			var article readability.Article
-			buf, err = os.ReadFile(fmt.Sprint(shot.HTML))
-			if err == nil {
-				singleFilePath := singleFile(ctx, opts, bytes.NewReader(buf), dir, shot.URL)
-				artifact.HTM.Local = singleFilePath
-			}
-			article, err = readability.FromReader(bytes.NewReader(buf), uri)
-			if err != nil {
-				logger.Error("parse html failed: %v", err)
+			if profile.html() {
+				// Attach single file
+				var buf []byte
+				buf, err = os.ReadFile(fmt.Sprint(shot.HTML))
+				if err == nil && profile.Has("htm") {
+					singleFilePath := singleFile(ctx, opts, bytes.NewReader(buf), dir, shot.URL)
+					artifact.HTM.Local = singleFilePath
+				}
+				article, err = readability.FromReader(bytes.NewReader(buf), uri)
+				if err != nil {
+					logger.Error("parse html failed: %v", err)
+				}
+				if profile.Has("txt") {
+					txtName := basename + ".txt"
+					fp = filepath.Join(dir, txtName)
+					if err = os.WriteFile(fp, helper.String2Byte(article.TextContent), filePerm); err == nil && article.TextContent != "" {
+						artifact.Txt.Local = fp
+					}
+				}
+
+				// Generate summary
+				if profile.Has("summary") {
+					summarizer := summary.NewSummary(opts)
+					sum, err = summarizer.Summarize(article.TextContent)
+					if err != nil {
+						logger.Error("sumarize failed: %v", err)
+					}
+				}
 			}
-			txtName := basename + ".txt"
-			fp = filepath.Join(dir, txtName)
-			if err = os.WriteFile(fp, helper.String2Byte(article.TextContent), filePerm); err == nil && article.TextContent != "" {
-				artifact.Txt.Local = fp
+			// The raw HTML is only exported for the other artifacts.
+			if !profile.Has("raw") {
+				discard(&shot.HTML)
 			}
 
-			// Generate summary
-			var sum string
-			summarizer := summary.NewSummary(opts)
-			sum, err = summarizer.Summarize(article.TextContent)
-			if err != nil {
-				logger.Error("sumarize failed: %v", err)
+			// Store the same content once
+			if opts.StorageDedup() {
+				dedup(blobs, artifact)
 			}
 
-			// Upload files to third-party server
-			if err = remotely(ctx, artifact); err != nil {
+			// Upload files to the artifact stores
+			if err = remotely(ctx, stores, artifact); err != nil {
 				logger.Error("upload files to remote server failed: %v", err)
 			}
 			bundle := &bundle{shots: shot, artifact: *artifact, article: article, summary: sum}
@@ -253,8 +351,9 @@ func Do(ctx context.Context, opts *config.Options, urls ...*url.URL) (Reduxer, e
 	return bs, err
 }
 
-// capture returns screenshot.Screenshots of given URLs
-func capture(ctx context.Context, cfg *config.Options, uri *url.URL, dir string) (shot *screenshot.Screenshots[screenshot.Path], err error) {
+// capture returns screenshot.Screenshots of given URLs, the PDF, the HAR and
+// the raw HTML are exported if required by the profile.
+func capture(ctx context.Context, cfg *config.Options, uri *url.URL, dir string, profile Profile) (shot *screenshot.Screenshots[screenshot.Path], err error) {
 	filename := basename(ctx)
 	files := screenshot.Files{
 		Image: filepath.Join(dir, filename+".png"),
@@ -265,10 +364,10 @@ func capture(ctx context.Context, cfg *config.Options, uri *url.URL, dir string)
 	opts := []screenshot.ScreenshotOption{
 		screenshot.AppendToFile(files),
 		screenshot.ScaleFactor(1),
-		screenshot.PrintPDF(true), // print pdf
-		screenshot.DumpHAR(true),  // export har
-		screenshot.RawHTML(true),  // export html
-		screenshot.Quality(100),   // image quality
+		screenshot.PrintPDF(profile.Has("pdf")), // print pdf
+		screenshot.DumpHAR(profile.Has("har")),  // export har
+		screenshot.RawHTML(profile.html()),      // export html
+		screenshot.Quality(100),                 // image quality
 	}
 
 	fallback := func() (*screenshot.Screenshots[screenshot.Path], error) {
@@ -306,6 +405,18 @@ func capture(ctx context.Context, cfg *config.Options, uri *url.URL, dir string)
 	return fallback()
 }
 
+// discard removes the file of the path not selected by the profile, the
+// screenshot is always taken by the browser.
+func discard(path *screenshot.Path) {
+	if *path == "" {
+		return
+	}
+	if err := os.Remove(string(*path)); err != nil && !os.IsNotExist(err) {
+		logger.Warn("remove %s failed: %v", *path, err)
+	}
+	*path = ""
+}
+
 func createDir(baseDir string) (dir string, err error) {
 	dir = filepath.Join(baseDir, time.Now().Format("200601"))
 	if helper.Exists(dir) {
@@ -318,51 +429,6 @@ func createDir(baseDir string) (dir string, err error) {
 	return dir, nil
 }
 
-func remotely(ctx context.Context, artifact *Artifact) (err error) {
-	assets := []*Asset{
-		&artifact.Img,
-		&artifact.PDF,
-		&artifact.Raw,
-		&artifact.Txt,
-		&artifact.HAR,
-		&artifact.HTM,
-		&artifact.WARC,
-		&artifact.Media,
-	}
-
-	cat := catbox.New(ingress.Client())
-	g, _ := errgroup.WithContext(ctx)
-
-	var mu sync.RWMutex
-	for _, asset := range assets {
-		asset := asset
-		if asset.Local == "" {
-			continue
-		}
-		if !helper.Exists(asset.Local) {
-			err = errors.Wrap(err, fmt.Sprintf("local asset: %s not exists", asset.Local))
-			continue
-		}
-		g.Go(func() error {
-			var remote Remote
-			mu.Lock()
-			c, e := cat.Upload(asset.Local)
-			if e != nil {
-				err = errors.Wrap(err, fmt.Sprintf("upload %s to catbox failed: %v", asset.Local, e))
-			} else {
-				remote.Catbox = c
-			}
-			asset.Remote = remote
-			mu.Unlock()
-			return nil
-		})
-	}
-	// nolint:errcheck
-	_ = g.Wait()
-
-	return err
-}
-
 func singleFile(ctx context.Context, opts *config.Options, inp io.Reader, dir, uri string) string {
 	req := obelisk.Request{URL: uri, Input: inp}
 	arc := &obelisk.Archiver{
@@ -175,3 +175,65 @@ func TestSingleFile(t *testing.T) {
 		t.Fatal(`unexpected archive webpage as a single file`)
 	}
 }
+
+func TestArtifactAssets(t *testing.T) {
+	art := Artifact{
+		Img:   Asset{Local: "/path/to/image"},
+		WARC:  Asset{Remote: Remote{{Store: StoreCatbox, URL: "https://files.catbox.moe/kkai0w.warc"}}},
+		Media: Asset{},
+	}
+
+	assets := art.Assets()
+	if len(assets) != 2 {
+		t.Fatalf("unexpected assets, got %d instead of 2", len(assets))
+	}
+	if assets[0].Kind != "img" || assets[0].Local != "/path/to/image" {
+		t.Errorf("unexpected asset: %+v", assets[0])
+	}
+	if assets[1].Kind != "warc" || assets[1].Remote.Catbox() == "" {
+		t.Errorf("unexpected asset: %+v", assets[1])
+	}
+}
+
+func TestAssetDigest(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "example.txt")
+	if err := os.WriteFile(fp, []byte("hello"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	size, sum, err := Asset{Local: fp}.Digest()
+	if err != nil {
+		t.Fatalf("unexpected digest error: %v", err)
+	}
+	if size != 5 {
+		t.Errorf("unexpected size, got %d instead of 5", size)
+	}
+	if sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("unexpected digest: %s", sum)
+	}
+
+	if _, _, err = (Asset{Local: fp + ".missing"}).Digest(); err == nil {
+		t.Error("unexpected digest of a missing file")
+	}
+}
+
+func TestBundleSetChange(t *testing.T) {
+	b := &bundle{artifact: Artifact{Img: Asset{Local: "/path/to/image"}}}
+	if !b.Changed() {
+		t.Fatal("unexpected unchanged bundle before detection")
+	}
+
+	b.SetChange(false, "", "")
+	if b.Changed() {
+		t.Fatal("unexpected changed bundle")
+	}
+
+	b.SetChange(true, "/path/to/diff", "/path/to/diff.png")
+	if !b.Changed() {
+		t.Fatal("unexpected unchanged bundle")
+	}
+	assets := b.Artifact().Assets()
+	if len(assets) != 3 || assets[1].Kind != "diff" || assets[2].Kind != "diff-img" {
+		t.Errorf("unexpected assets: %+v", assets)
+	}
+}
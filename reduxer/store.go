@@ -0,0 +1,182 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/wabarc/go-catbox"
+	"github.com/wabarc/helper"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/ingress"
+	"golang.org/x/sync/errgroup"
+)
+
+// Kinds of the artifact stores.
+const (
+	StoreCatbox = "catbox"
+	StoreS3     = "s3"
+	StoreWebDAV = "webdav"
+	StoreCAS    = "cas"
+)
+
+// ArtifactStore is the interface that wraps the basic methods of a store the
+// artifacts are uploaded to.
+//
+// Kind returns the kind of the store, which is one of catbox, s3, webdav and cas.
+//
+// Put stores the local file of the path and returns its URL on the store.
+type ArtifactStore interface {
+	Kind() string
+	Put(ctx context.Context, path string) (string, error)
+}
+
+// Location represents the location of a file on an artifact store.
+type Location struct {
+	Store string
+	URL   string
+}
+
+// Remote represents the locations of a file on the artifact stores, in the
+// order of the stores configured.
+type Remote []Location
+
+// Get returns the URL of the file on the store of the kind, or an empty
+// string if the file is not stored on it.
+func (r Remote) Get(store string) string {
+	for _, l := range r {
+		if l.Store == store {
+			return l.URL
+		}
+	}
+	return ""
+}
+
+// URL returns the URL of the first location of the file.
+func (r Remote) URL() string {
+	if len(r) == 0 {
+		return ""
+	}
+	return r[0].URL
+}
+
+// Catbox returns the URL of the file on catbox.moe.
+func (r Remote) Catbox() string {
+	return r.Get(StoreCatbox)
+}
+
+// ArtifactStores returns the artifact stores configured by the options, the
+// stores selected without the required options are skipped.
+func ArtifactStores(opts *config.Options) []ArtifactStore {
+	stores := []ArtifactStore{}
+	for _, kind := range opts.ArtifactStores() {
+		switch kind {
+		case StoreCatbox:
+			stores = append(stores, &catboxStore{cat: catbox.New(ingress.Client())})
+		case StoreS3:
+			if !opts.EnabledS3() {
+				logger.Warn("artifact store s3 requires WAYBACK_S3_ENDPOINT and WAYBACK_S3_BUCKET, skipped")
+				continue
+			}
+			stores = append(stores, NewS3Store(opts))
+		case StoreWebDAV:
+			if !opts.EnabledWebDAV() {
+				logger.Warn("artifact store webdav requires WAYBACK_WEBDAV_URL, skipped")
+				continue
+			}
+			stores = append(stores, NewWebDAVStore(opts))
+		case StoreCAS:
+			stores = append(stores, NewCASStore(opts.CASDir(), opts.CASPublicURL()))
+		default:
+			logger.Warn("unknown artifact store %s, skipped", kind)
+		}
+	}
+	return stores
+}
+
+// catboxStore uploads the artifacts to catbox.moe.
+type catboxStore struct {
+	cat *catbox.Catbox
+}
+
+// Kind returns the kind of the store.
+func (s *catboxStore) Kind() string {
+	return StoreCatbox
+}
+
+// Put uploads the file of the path to catbox.moe.
+func (s *catboxStore) Put(_ context.Context, path string) (string, error) {
+	return s.cat.Upload(path)
+}
+
+// objectKey returns the key of the file of the path on the remote stores,
+// which keeps the monthly directory of the local disk.
+func objectKey(path string) string {
+	return filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path)
+}
+
+// remotely puts the local files of the artifact to the stores and sets their
+// locations, the files failed to put are skipped for the store.
+func remotely(ctx context.Context, stores []ArtifactStore, artifact *Artifact) (err error) {
+	assets := []*Asset{
+		&artifact.Img,
+		&artifact.PDF,
+		&artifact.Raw,
+		&artifact.Txt,
+		&artifact.HAR,
+		&artifact.HTM,
+		&artifact.WARC,
+		&artifact.Media,
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	var mu sync.Mutex
+	for _, asset := range assets {
+		asset := asset
+		if asset.Local == "" {
+			continue
+		}
+		if !helper.Exists(asset.Local) {
+			mu.Lock()
+			err = wrapError(err, fmt.Sprintf("local asset: %s not exists", asset.Local))
+			mu.Unlock()
+			continue
+		}
+		g.Go(func() error {
+			var remote Remote
+			for _, store := range stores {
+				u, e := store.Put(ctx, asset.Local)
+				if e != nil {
+					mu.Lock()
+					err = wrapError(err, fmt.Sprintf("upload %s to %s failed: %v", asset.Local, store.Kind(), e))
+					mu.Unlock()
+					continue
+				}
+				remote = append(remote, Location{Store: store.Kind(), URL: u})
+			}
+			asset.Remote = remote
+			return nil
+		})
+	}
+	// nolint:errcheck
+	_ = g.Wait()
+
+	return err
+}
+
+// wrapError wraps err with the message, or returns a new error of the
+// message if err is nil.
+func wrapError(err error, message string) error {
+	if err == nil {
+		return errors.New(message)
+	}
+	return errors.Wrap(err, message)
+}
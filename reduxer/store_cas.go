@@ -0,0 +1,95 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wabarc/helper"
+)
+
+// CASStore stores the artifacts in a local content-addressed directory, the
+// files are named after their SHA-256 digests, so the same content is stored
+// once however many times it is archived.
+type CASStore struct {
+	dir       string
+	publicURL string
+}
+
+// NewCASStore returns a CASStore of the directory, the files are linked under
+// the public URL if set, or with file URLs.
+func NewCASStore(dir, publicURL string) *CASStore {
+	return &CASStore{dir: dir, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+// Kind returns the kind of the store.
+func (s *CASStore) Kind() string {
+	return StoreCAS
+}
+
+// Put copies the file of the path to the store if its content is not stored
+// yet and returns its URL.
+func (s *CASStore) Put(_ context.Context, path string) (string, error) {
+	_, sum, err := Asset{Local: path}.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	// Shard the files by the first byte of the digests to keep the directories small.
+	name := filepath.Join(sum[:2], sum+filepath.Ext(path))
+	dst := filepath.Join(s.dir, name)
+	if !helper.Exists(dst) {
+		if err = copyFile(path, dst); err != nil {
+			return "", err
+		}
+	}
+
+	if s.publicURL != "" {
+		return s.publicURL + "/" + filepath.ToSlash(name), nil
+	}
+	abs, err := filepath.Abs(dst)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+// copyFile copies the file of src to dst through a temporary file, so the
+// file of dst is never partially written.
+func copyFile(src, dst string) error {
+	// nosemgrep
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(filePerm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCASStorePut(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	for _, name := range []string{a, b} {
+		if err := os.WriteFile(name, []byte("hello"), filePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cas := filepath.Join(dir, "cas")
+	store := NewCASStore(cas, "")
+	u1, err := store.Put(context.Background(), a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sum := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if !strings.HasPrefix(u1, "file://") || !strings.HasSuffix(u1, "/cas/2c/"+sum+".txt") {
+		t.Errorf("unexpected url: %s", u1)
+	}
+
+	// The same content is stored once.
+	u2, err := store.Put(context.Background(), b)
+	if err != nil || u2 != u1 {
+		t.Errorf("unexpected url %s of the same content, error: %v", u2, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(cas, "2c"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("unexpected files %v in store, error: %v", entries, err)
+	}
+
+	u3, err := NewCASStore(cas, "https://cas.example.com/").Put(context.Background(), a)
+	if err != nil || u3 != "https://cas.example.com/2c/"+sum+".txt" {
+		t.Errorf("unexpected public url %s, error: %v", u3, err)
+	}
+
+	if _, err = store.Put(context.Background(), filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("unexpected put of missing file")
+	}
+}
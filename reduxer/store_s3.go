@@ -0,0 +1,199 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/ingress"
+)
+
+// S3Store uploads the artifacts to an S3-compatible object storage, e.g.
+// Amazon S3, MinIO or Cloudflare R2, with the path-style requests signed by
+// AWS Signature Version 4.
+type S3Store struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+}
+
+// NewS3Store returns an S3Store configured by the options.
+func NewS3Store(opts *config.Options) *S3Store {
+	return &S3Store{
+		client:    ingress.Client(),
+		endpoint:  strings.TrimSuffix(opts.S3Endpoint(), "/"),
+		region:    opts.S3Region(),
+		bucket:    opts.S3Bucket(),
+		accessKey: opts.S3AccessKey(),
+		secretKey: opts.S3SecretKey(),
+		publicURL: strings.TrimSuffix(opts.S3PublicURL(), "/"),
+	}
+}
+
+// Kind returns the kind of the store.
+func (s *S3Store) Kind() string {
+	return StoreS3
+}
+
+// Put uploads the file of the path to the bucket and returns its URL, which
+// is under the public URL of the bucket if set.
+func (s *S3Store) Put(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// The payload is hashed before uploading to sign the request.
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	key := objectKey(path)
+	uri := s.endpoint + "/" + uriEncode(s.bucket+"/"+key, false)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, f)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	if typ := mime.TypeByExtension(filepath.Ext(path)); typ != "" {
+		req.Header.Set("Content-Type", typ)
+	}
+	s.sign(req, hex.EncodeToString(h.Sum(nil)), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) // nolint:errcheck
+		return "", errors.New(fmt.Sprintf("put object %s failed, status: %s, body: %s", key, resp.Status, body))
+	}
+
+	if s.publicURL != "" {
+		return s.publicURL + "/" + uriEncode(key, false), nil
+	}
+	return uri, nil
+}
+
+// sign signs the request with AWS Signature Version 4, the host and all the
+// headers of the request are signed.
+func (s *S3Store) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string of the request sorted by the keys
+// with the keys and values encoded.
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	pairs := make([]string, 0, len(query))
+	for k, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes the string as the URI encoding of AWS Signature Version 4,
+// which keeps the unreserved characters only, the slashes are kept too unless
+// encodeSlash is true.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+)
+
+type fakeStore struct {
+	kind string
+	err  error
+}
+
+func (s *fakeStore) Kind() string {
+	return s.kind
+}
+
+func (s *fakeStore) Put(_ context.Context, path string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	return "https://" + s.kind + ".example.com/" + objectKey(path), nil
+}
+
+func TestRemote(t *testing.T) {
+	remote := Remote{
+		{Store: StoreS3, URL: "https://s3.example.com/202501/example.png"},
+		{Store: StoreCatbox, URL: "https://files.catbox.moe/9u6yvu.png"},
+	}
+
+	if got := remote.URL(); got != "https://s3.example.com/202501/example.png" {
+		t.Errorf("unexpected url: %s", got)
+	}
+	if got := remote.Catbox(); got != "https://files.catbox.moe/9u6yvu.png" {
+		t.Errorf("unexpected catbox url: %s", got)
+	}
+	if got := remote.Get(StoreWebDAV); got != "" {
+		t.Errorf("unexpected webdav url: %s", got)
+	}
+	if got := (Remote{}).URL(); got != "" {
+		t.Errorf("unexpected url of empty remote: %s", got)
+	}
+}
+
+func TestArtifactStores(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_ARTIFACT_STORES", "catbox,s3,webdav,cas,foo")
+	os.Setenv("WAYBACK_WEBDAV_URL", "https://dav.example.com/wayback")
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	// The s3 store is skipped without the endpoint and bucket.
+	kinds := []string{}
+	for _, store := range ArtifactStores(opts) {
+		kinds = append(kinds, store.Kind())
+	}
+	if got := strings.Join(kinds, ","); got != "catbox,webdav,cas" {
+		t.Errorf("unexpected artifact stores: %s", got)
+	}
+}
+
+func TestRemotely(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "202501")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	img := filepath.Join(dir, "example.png")
+	if err := os.WriteFile(img, []byte("png"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	stores := []ArtifactStore{
+		&fakeStore{kind: StoreS3},
+		&fakeStore{kind: StoreCatbox, err: errors.New("unavailable")},
+		&fakeStore{kind: StoreWebDAV},
+	}
+	artifact := &Artifact{
+		Img: Asset{Local: img},
+		PDF: Asset{Local: filepath.Join(dir, "missing.pdf")},
+	}
+
+	err := remotely(context.Background(), stores, artifact)
+	if err == nil || !strings.Contains(err.Error(), "missing.pdf not exists") || !strings.Contains(err.Error(), "to catbox failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	exp := Remote{
+		{Store: StoreS3, URL: "https://s3.example.com/202501/example.png"},
+		{Store: StoreWebDAV, URL: "https://webdav.example.com/202501/example.png"},
+	}
+	if got := artifact.Img.Remote; len(got) != 2 || got[0] != exp[0] || got[1] != exp[1] {
+		t.Errorf("unexpected remote: %+v", got)
+	}
+	if len(artifact.PDF.Remote) != 0 {
+		t.Errorf("unexpected remote of missing file: %+v", artifact.PDF.Remote)
+	}
+}
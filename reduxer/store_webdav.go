@@ -0,0 +1,118 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/ingress"
+)
+
+// WebDAVStore uploads the artifacts to a collection of a WebDAV server, e.g.
+// Nextcloud or an Nginx with the dav module.
+type WebDAVStore struct {
+	client   *http.Client
+	url      string
+	username string
+	password string
+}
+
+// NewWebDAVStore returns a WebDAVStore configured by the options.
+func NewWebDAVStore(opts *config.Options) *WebDAVStore {
+	return &WebDAVStore{
+		client:   ingress.Client(),
+		url:      strings.TrimSuffix(opts.WebDAVURL(), "/"),
+		username: opts.WebDAVUsername(),
+		password: opts.WebDAVPassword(),
+	}
+}
+
+// Kind returns the kind of the store.
+func (s *WebDAVStore) Kind() string {
+	return StoreWebDAV
+}
+
+// Put uploads the file of the path to the collection and returns its URL,
+// the monthly collection of the file is created if not exists.
+func (s *WebDAVStore) Put(ctx context.Context, name string) (string, error) {
+	key := objectKey(name)
+	if err := s.mkcol(ctx, path.Dir(key)); err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	uri := s.url + "/" + uriEncode(key, false)
+	req, err := s.request(ctx, http.MethodPut, uri, f)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = info.Size()
+	if typ := mime.TypeByExtension(filepath.Ext(name)); typ != "" {
+		req.Header.Set("Content-Type", typ)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return uri, nil
+	default:
+		return "", errors.New(fmt.Sprintf("put %s failed, status: %s", key, resp.Status))
+	}
+}
+
+// mkcol creates the collection of the directory, it succeeds if the
+// collection already exists.
+func (s *WebDAVStore) mkcol(ctx context.Context, dir string) error {
+	req, err := s.request(ctx, "MKCOL", s.url+"/"+uriEncode(dir, false)+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	// 405 Method Not Allowed is returned for the existing collections.
+	case http.StatusCreated, http.StatusMethodNotAllowed:
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("create collection %s failed, status: %s", dir, resp.Status))
+	}
+}
+
+func (s *WebDAVStore) request(ctx context.Context, method, uri string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return req, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package reduxer // import "github.com/wabarc/wayback/reduxer"
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestWebDAVStorePut(t *testing.T) {
+	var mu sync.Mutex
+	cols := map[string]bool{}
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case "MKCOL":
+			if cols[r.URL.Path] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			cols[r.URL.Path] = true
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPut:
+			if !cols[filepath.Dir(r.URL.Path)+"/"] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	os.Clearenv()
+	os.Setenv("WAYBACK_WEBDAV_URL", server.URL+"/wayback/")
+	os.Setenv("WAYBACK_WEBDAV_USERNAME", "alice")
+	os.Setenv("WAYBACK_WEBDAV_PASSWORD", "secret")
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "202501")
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "example.txt")
+	if err = os.WriteFile(name, []byte("hello"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewWebDAVStore(opts)
+	// The existing collection is reused by the second put.
+	for i := 0; i < 2; i++ {
+		u, err := store.Put(context.Background(), name)
+		if err != nil || u != server.URL+"/wayback/202501/example.txt" {
+			t.Fatalf("unexpected url %s, error: %v", u, err)
+		}
+	}
+	if got := files["/wayback/202501/example.txt"]; got != "hello" {
+		t.Errorf("unexpected file: %s", got)
+	}
+
+	store.password = "invalid"
+	if _, err = store.Put(context.Background(), name); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("unexpected error of invalid password: %v", err)
+	}
+}
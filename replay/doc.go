@@ -0,0 +1,9 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package replay implements a reader and a CDX-style index of the WARC files
+stored by reduxer, and rewrites captured resources for replaying.
+*/
+package replay // import "github.com/wabarc/wayback/replay"
@@ -0,0 +1,434 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wabarc/logger"
+)
+
+// TimestampLayout is the layout of the 14-digit timestamp of captures.
+const TimestampLayout = "20060102150405"
+
+// Capture represents an entry of the index, it follows the fields of
+// the CDX format.
+type Capture struct {
+	URLKey    string // Canonicalized URL in SURT form
+	Timestamp string // Capture time in 14-digit form
+	Original  string // Original URL
+	MIME      string // Media type of the response
+	Status    int    // Status code of the response
+	Digest    string // Payload digest of the record
+	Filename  string // WARC file relative to the storage directory
+	Offset    int64  // Offset of the record in the WARC file
+	RecordID  string // WARC-Record-ID of the record
+}
+
+// Time returns the capture time.
+func (c Capture) Time() time.Time {
+	t, _ := time.Parse(TimestampLayout, c.Timestamp)
+	return t
+}
+
+// String returns the capture as a CDX line.
+func (c Capture) String() string {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	return fmt.Sprintf("%s %s %s %s %d %s %d %s",
+		c.URLKey, c.Timestamp, c.Original, orDash(c.MIME), c.Status, orDash(c.Digest), c.Offset, c.Filename)
+}
+
+// Key returns the canonicalized form of a URL used to look up the index,
+// the scheme, the www prefix and the default port are ignored, e.g.
+// https://www.example.com/Path?q=1 turns to com,example)/path?q=1.
+func Key(uri string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid url: %s", uri)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	parts := strings.Split(host, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	key := strings.Join(parts, ",")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		key = net.JoinHostPort(key, port)
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	key += ")" + path
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+
+	return strings.ToLower(key), nil
+}
+
+type indexedFile struct {
+	modTime  time.Time
+	size     int64
+	captures []Capture
+}
+
+// Index is an in-memory CDX-style index of the WARC files under a directory.
+type Index struct {
+	mu  sync.RWMutex
+	dir string
+
+	// refreshMu serializes the refreshes, the files are only changed by
+	// the refreshes, and the captures are guarded by mu.
+	refreshMu sync.Mutex
+
+	files    map[string]*indexedFile
+	captures map[string][]Capture
+}
+
+var (
+	indexes   = make(map[string]*Index)
+	indexesMu sync.Mutex
+)
+
+// IndexOf returns the Index of the WARC files under dir shared in the
+// process, the captures added by the local capture slot are replayed by
+// the httpd service at once.
+func IndexOf(dir string) *Index {
+	indexesMu.Lock()
+	defer indexesMu.Unlock()
+
+	dir = filepath.Clean(dir)
+	idx, ok := indexes[dir]
+	if !ok {
+		idx = NewIndex(dir)
+		indexes[dir] = idx
+	}
+	return idx
+}
+
+// NewIndex returns an Index of the WARC files under dir, call Refresh to
+// build the index.
+func NewIndex(dir string) *Index {
+	return &Index{
+		dir:      dir,
+		files:    make(map[string]*indexedFile),
+		captures: make(map[string][]Capture),
+	}
+}
+
+// Run refreshes the index at once and at every interval until the context
+// is done, the lookups read the index refreshed last meanwhile.
+func (idx *Index) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := idx.Refresh(); err != nil {
+			logger.Error("refresh replay index failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh indexes the WARC files that are new or modified since last
+// refresh, and drops the entries of the files removed. The lookups are not
+// blocked while the files are indexed.
+func (idx *Index) Refresh() error {
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+
+	seen := make(map[string]bool)
+	updated := make(map[string]*indexedFile)
+	err := filepath.WalkDir(idx.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isWARC(path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(idx.dir, path)
+		if err != nil {
+			return err
+		}
+		seen[name] = true
+		if f, ok := idx.files[name]; ok && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+			return nil
+		}
+
+		captures, err := indexFile(path, filepath.ToSlash(name))
+		if err != nil {
+			// WARC file may be incomplete while it is being written.
+			logger.Warn("index warc file %s failed: %v", name, err)
+		}
+		updated[name] = &indexedFile{modTime: info.ModTime(), size: info.Size(), captures: captures}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	changed := len(updated) > 0
+	for name, f := range updated {
+		idx.files[name] = f
+	}
+	for name := range idx.files {
+		if !seen[name] {
+			delete(idx.files, name)
+			changed = true
+		}
+	}
+	if changed {
+		idx.mu.Lock()
+		idx.rebuild()
+		idx.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Add indexes the WARC file at path under the directory of the index and
+// returns its captures, which are looked up without waiting for the next
+// refresh.
+func (idx *Index) Add(path string) ([]Capture, error) {
+	name, err := filepath.Rel(idx.dir, path)
+	if err != nil || strings.HasPrefix(name, "..") {
+		return nil, fmt.Errorf("warc file %s is outside of %s", path, idx.dir)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	captures, err := indexFile(path, filepath.ToSlash(name))
+	if err != nil {
+		return nil, err
+	}
+
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+
+	idx.files[name] = &indexedFile{modTime: info.ModTime(), size: info.Size(), captures: captures}
+	idx.mu.Lock()
+	idx.rebuild()
+	idx.mu.Unlock()
+
+	return captures, nil
+}
+
+func (idx *Index) rebuild() {
+	captures := make(map[string][]Capture)
+	for _, f := range idx.files {
+		for _, c := range f.captures {
+			captures[c.URLKey] = append(captures[c.URLKey], c)
+		}
+	}
+	for _, list := range captures {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Timestamp < list[j].Timestamp
+		})
+	}
+	idx.captures = captures
+}
+
+// Lookup returns the captures of the given URL sorted by capture time.
+func (idx *Index) Lookup(uri string) []Capture {
+	key, err := Key(uri)
+	if err != nil {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return append([]Capture(nil), idx.captures[key]...)
+}
+
+// Closest returns the capture of the given URL closest to the timestamp.
+func (idx *Index) Closest(uri, timestamp string) (Capture, bool) {
+	captures := idx.Lookup(uri)
+	if len(captures) == 0 {
+		return Capture{}, false
+	}
+
+	target, err := time.Parse(TimestampLayout, padTimestamp(timestamp))
+	if err != nil {
+		return captures[len(captures)-1], true
+	}
+
+	closest := captures[0]
+	for _, c := range captures[1:] {
+		if absDuration(c.Time().Sub(target)) < absDuration(closest.Time().Sub(target)) {
+			closest = c
+		}
+	}
+	return closest, true
+}
+
+// Latest returns the latest capture of each URL sorted by original URL.
+func (idx *Index) Latest() []Capture {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	list := make([]Capture, 0, len(idx.captures))
+	for _, captures := range idx.captures {
+		list = append(list, captures[len(captures)-1])
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Original < list[j].Original
+	})
+
+	return list
+}
+
+// Open returns the captured HTTP response, the caller must close the
+// response body.
+func (idx *Index) Open(c Capture) (*http.Response, error) {
+	f, err := os.Open(filepath.Join(idx.dir, filepath.FromSlash(c.Filename)))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = f.Seek(c.Offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	rd := NewReader(f, isGzipped(c.Filename))
+	for {
+		rec, err := rd.Next()
+		if err != nil {
+			f.Close()
+			if err == io.EOF {
+				err = fmt.Errorf("record %s not found", c.RecordID)
+			}
+			return nil, err
+		}
+		if rec.ID() != c.RecordID {
+			continue
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(rec.Block), nil)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		resp.Body = &readCloser{Reader: resp.Body, closers: []io.Closer{resp.Body, f}}
+		return resp, nil
+	}
+}
+
+func indexFile(path, name string) ([]Capture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var captures []Capture
+	rd := NewReader(f, isGzipped(path))
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return captures, nil
+		}
+		if err != nil {
+			return captures, err
+		}
+		if rec.Type() != "response" || !strings.HasPrefix(rec.Header.Get("Content-Type"), "application/http") {
+			continue
+		}
+		key, err := Key(rec.TargetURI())
+		if err != nil {
+			continue
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(rec.Block), nil)
+		if err != nil {
+			continue
+		}
+		mediatype, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		captures = append(captures, Capture{
+			URLKey:    key,
+			Timestamp: rec.Date().UTC().Format(TimestampLayout),
+			Original:  rec.TargetURI(),
+			MIME:      mediatype,
+			Status:    resp.StatusCode,
+			Digest:    rec.Header.Get("WARC-Payload-Digest"),
+			Filename:  name,
+			Offset:    rec.Offset,
+			RecordID:  rec.ID(),
+		})
+	}
+}
+
+func isWARC(name string) bool {
+	return strings.HasSuffix(name, ".warc") || strings.HasSuffix(name, ".warc.gz")
+}
+
+func isGzipped(name string) bool {
+	return strings.HasSuffix(name, ".gz")
+}
+
+// padTimestamp pads partial timestamp, e.g. 2021 turns to 20210101000000.
+func padTimestamp(ts string) string {
+	const min = "00000101000000"
+	if len(ts) >= len(min) {
+		return ts[:len(min)]
+	}
+	return ts + min[len(ts):]
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var err error
+	for _, c := range rc.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		uri string
+		key string
+	}{
+		{"https://example.com", "com,example)/"},
+		{"http://www.Example.com/Path?q=1", "com,example)/path?q=1"},
+		{"https://example.com:8080/", "com,example:8080)/"},
+		{"https://example.com:443/", "com,example)/"},
+	}
+
+	for _, test := range tests {
+		key, err := Key(test.uri)
+		if err != nil {
+			t.Fatalf("unexpected key of %s: %v", test.uri, err)
+		}
+		if key != test.key {
+			t.Errorf("unexpected key of %s, got %s instead of %s", test.uri, key, test.key)
+		}
+	}
+
+	if _, err := Key("/path"); err == nil {
+		t.Error("unexpected key of url without host")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "202101"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "202101", "a.warc"), warcFile(t, false), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.warc.gz"), warcFile(t, true), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := NewIndex(dir)
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("unexpected refresh index: %v", err)
+	}
+
+	captures := idx.Lookup("http://www.example.com")
+	if len(captures) != 4 {
+		t.Fatalf("unexpected captures number, got %d instead of 4", len(captures))
+	}
+	if captures[0].Timestamp != "20210101000000" || captures[3].Timestamp != "20220101000000" {
+		t.Errorf("unexpected captures order: %v", captures)
+	}
+	if captures[0].MIME != "text/html" || captures[0].Status != 200 {
+		t.Errorf("unexpected capture: %s", captures[0])
+	}
+	if n := len(idx.Latest()); n != 2 {
+		t.Errorf("unexpected latest captures number, got %d instead of 2", n)
+	}
+
+	capture, ok := idx.Closest("https://example.com/", "2021")
+	if !ok || capture.Timestamp != "20210101000000" {
+		t.Fatalf("unexpected closest capture: %v", capture)
+	}
+	for _, c := range idx.Lookup("https://example.com/about") {
+		resp, err := idx.Open(c)
+		if err != nil {
+			t.Fatalf("unexpected open capture %s: %v", c, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), "About") {
+			t.Errorf("unexpected body of capture %s: %s", c, body)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(dir, "b.warc.gz")); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("unexpected refresh index: %v", err)
+	}
+	if n := len(idx.Lookup("https://example.com/")); n != 2 {
+		t.Errorf("unexpected captures number after removal, got %d instead of 2", n)
+	}
+
+	// Run refreshes the index at once before it checks the context.
+	if err := os.Remove(filepath.Join(dir, "202101", "a.warc")); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	idx.Run(ctx, time.Minute)
+	if n := len(idx.Lookup("https://example.com/")); n != 0 {
+		t.Errorf("unexpected captures number after run, got %d instead of 0", n)
+	}
+}
+
+func TestIndexAdd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.warc")
+	if err := os.WriteFile(path, warcFile(t, false), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := IndexOf(dir)
+	if IndexOf(dir+"/") != idx {
+		t.Fatal("unexpected index not shared for the same directory")
+	}
+	captures, err := idx.Add(path)
+	if err != nil {
+		t.Fatalf("unexpected add warc file: %v", err)
+	}
+	if len(captures) != 3 || captures[0].Filename != "a.warc" {
+		t.Fatalf("unexpected captures of added file: %v", captures)
+	}
+	if n := len(idx.Lookup("https://example.com/")); n != 2 {
+		t.Errorf("unexpected captures number without refresh, got %d instead of 2", n)
+	}
+
+	if _, err := idx.Add(filepath.Join(t.TempDir(), "b.warc")); err == nil {
+		t.Error("unexpected add warc file outside of the directory")
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	cssURLRegexp    = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)(['"]?)\s*\)`)
+	cssImportRegexp = regexp.MustCompile(`@import\s+(['"])([^'"]+)(['"])`)
+	refreshRegexp   = regexp.MustCompile(`(?i)^(\s*\d+\s*;\s*url\s*=\s*)(.+)$`)
+
+	// urlAttrs holds the attributes that reference to a URL.
+	urlAttrs = map[string]bool{
+		"href":       true,
+		"src":        true,
+		"action":     true,
+		"poster":     true,
+		"background": true,
+		"data-src":   true,
+		"formaction": true,
+	}
+)
+
+// Rewriter rewrites the URLs of a captured resource to the replay URLs, so
+// that the page is replayed with the captured resources.
+type Rewriter struct {
+	Prefix    string   // Path prefix of the replay URLs, e.g. /replay
+	Timestamp string   // Timestamp of the capture
+	Base      *url.URL // URL of the captured resource
+}
+
+// URL returns the replay URL of the reference, the reference is kept as
+// is if it is not an HTTP URL.
+func (rw *Rewriter) URL(ref string) string {
+	trimmed := strings.TrimSpace(ref)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return ref
+	}
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return ref
+	}
+	if rw.Base != nil {
+		u = rw.Base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ref
+	}
+
+	return rw.Prefix + "/" + rw.Timestamp + "/" + u.String()
+}
+
+// CSS rewrites the URLs in the stylesheet.
+func (rw *Rewriter) CSS(s string) string {
+	s = cssURLRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		sub := cssURLRegexp.FindStringSubmatch(m)
+		if strings.HasPrefix(strings.TrimSpace(sub[2]), "data:") {
+			return m
+		}
+		return "url(" + sub[1] + rw.URL(sub[2]) + sub[3] + ")"
+	})
+	return cssImportRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		sub := cssImportRegexp.FindStringSubmatch(m)
+		return "@import " + sub[1] + rw.URL(sub[2]) + sub[3]
+	})
+}
+
+// HTML rewrites the URLs in the document read from r and writes the
+// result to w, the banner is inserted at the beginning of the body.
+func (rw *Rewriter) HTML(w io.Writer, r io.Reader, banner string) error {
+	z := html.NewTokenizer(r)
+	inStyle := false
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.TextToken:
+			raw := string(z.Raw())
+			if inStyle {
+				raw = rw.CSS(raw)
+			}
+			if _, err := io.WriteString(w, raw); err != nil {
+				return err
+			}
+			continue
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			inStyle = tok.Data == "style" && tt == html.StartTagToken
+			rw.rewriteToken(&tok)
+			if _, err := io.WriteString(w, tok.String()); err != nil {
+				return err
+			}
+			if tok.Data == "body" && banner != "" {
+				if _, err := io.WriteString(w, banner); err != nil {
+					return err
+				}
+				banner = ""
+			}
+			continue
+		case html.EndTagToken:
+			inStyle = false
+		}
+		if _, err := w.Write(z.Raw()); err != nil {
+			return err
+		}
+	}
+}
+
+func (rw *Rewriter) rewriteToken(tok *html.Token) {
+	attrs := tok.Attr[:0]
+	for _, attr := range tok.Attr {
+		key := strings.ToLower(attr.Key)
+		switch {
+		case key == "integrity":
+			// Subresource integrity is not guaranteed once rewritten.
+			continue
+		case tok.Data == "base" && key == "href":
+			if u, err := url.Parse(strings.TrimSpace(attr.Val)); err == nil && rw.Base != nil {
+				rw.Base = rw.Base.ResolveReference(u)
+			}
+			attr.Val = rw.URL(attr.Val)
+		case urlAttrs[key]:
+			attr.Val = rw.URL(attr.Val)
+		case key == "srcset":
+			attr.Val = rw.srcset(attr.Val)
+		case key == "style":
+			attr.Val = rw.CSS(attr.Val)
+		case tok.Data == "meta" && key == "content":
+			if m := refreshRegexp.FindStringSubmatch(attr.Val); m != nil {
+				attr.Val = m[1] + rw.URL(strings.Trim(m[2], `'"`))
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	tok.Attr = attrs
+}
+
+func (rw *Rewriter) srcset(val string) string {
+	candidates := strings.Split(val, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = rw.URL(fields[0])
+		candidates[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
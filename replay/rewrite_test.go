@@ -0,0 +1,59 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRewriter(t *testing.T) {
+	base, _ := url.Parse("https://example.com/dir/page")
+	rw := &Rewriter{Prefix: "/replay", Timestamp: "20210101000000", Base: base}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"/about", "/replay/20210101000000/https://example.com/about"},
+		{"img.png", "/replay/20210101000000/https://example.com/dir/img.png"},
+		{"//cdn.example.org/a.js", "/replay/20210101000000/https://cdn.example.org/a.js"},
+		{"#top", "#top"},
+		{"mailto:foo@example.com", "mailto:foo@example.com"},
+		{"data:image/png;base64,AAAA", "data:image/png;base64,AAAA"},
+	}
+	for _, test := range tests {
+		if got := rw.URL(test.ref); got != test.want {
+			t.Errorf("unexpected rewrite %s, got %s instead of %s", test.ref, got, test.want)
+		}
+	}
+
+	css := rw.CSS(`body{background:url("/bg.png")} @import 'a.css';`)
+	if !strings.Contains(css, `url("/replay/20210101000000/https://example.com/bg.png")`) ||
+		!strings.Contains(css, `@import '/replay/20210101000000/https://example.com/dir/a.css'`) {
+		t.Errorf("unexpected rewrite css: %s", css)
+	}
+
+	doc := `<html><head><style>p{background:url(/p.png)}</style>` +
+		`<script src="/a.js" integrity="sha384-x"></script></head>` +
+		`<body><img srcset="/a.png 1x, /b.png 2x"><a href="/about">About</a></body></html>`
+	var b strings.Builder
+	if err := rw.HTML(&b, strings.NewReader(doc), `<div id="banner"></div>`); err != nil {
+		t.Fatalf("unexpected rewrite html: %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`url(/replay/20210101000000/https://example.com/p.png)`,
+		`<script src="/replay/20210101000000/https://example.com/a.js">`,
+		`srcset="/replay/20210101000000/https://example.com/a.png 1x, /replay/20210101000000/https://example.com/b.png 2x"`,
+		`<body><div id="banner"></div>`,
+		`<a href="/replay/20210101000000/https://example.com/about">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("unexpected rewrite html, %s not found in %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record represents a record of WARC file.
+type Record struct {
+	// Header holds the named fields of the record, e.g. WARC-Type.
+	Header textproto.MIMEHeader
+
+	// Offset is the position of the record in the WARC file. For the
+	// gzipped WARC file, it is the position of the gzip member that
+	// holds the record.
+	Offset int64
+
+	// Block is the content block of the record, it is valid until the
+	// next call to Reader.Next.
+	Block io.Reader
+}
+
+// Type returns the WARC-Type of the record.
+func (r *Record) Type() string {
+	return r.Header.Get("WARC-Type")
+}
+
+// ID returns the WARC-Record-ID of the record.
+func (r *Record) ID() string {
+	return r.Header.Get("WARC-Record-ID")
+}
+
+// TargetURI returns the WARC-Target-URI of the record, angle brackets
+// written by some tools are removed.
+func (r *Record) TargetURI() string {
+	return strings.Trim(r.Header.Get("WARC-Target-URI"), "<>")
+}
+
+// Date returns the WARC-Date of the record.
+func (r *Record) Date() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, r.Header.Get("WARC-Date"))
+	return t
+}
+
+// Reader reads records from a WARC file, it supports both the plain
+// file and the gzipped file that compressed record by record.
+type Reader struct {
+	src     *countingReader
+	gz      *gzip.Reader
+	rr      *recordReader
+	gzipped bool
+	member  int64
+}
+
+// NewReader returns a Reader reads from r, gzipped reports whether the
+// WARC file is compressed.
+func NewReader(r io.Reader, gzipped bool) *Reader {
+	src := &countingReader{br: bufio.NewReader(r)}
+	rd := &Reader{src: src, gzipped: gzipped}
+	if !gzipped {
+		rd.rr = &recordReader{br: src.br}
+	}
+	return rd
+}
+
+// Next returns the next record, it returns io.EOF if no more records.
+func (r *Reader) Next() (*Record, error) {
+	for {
+		if r.rr == nil {
+			if err := r.openMember(); err != nil {
+				return nil, err
+			}
+		}
+		rec, err := r.rr.next()
+		if err == io.EOF && r.gzipped {
+			r.rr = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.gzipped {
+			rec.Offset = r.member
+		}
+		return rec, nil
+	}
+}
+
+// openMember opens the next gzip member, the gzip reader consumes bytes
+// through countingReader without reading ahead, the offset of the
+// member is exact.
+func (r *Reader) openMember() (err error) {
+	r.member = r.src.n
+	if r.gz == nil {
+		r.gz, err = gzip.NewReader(r.src)
+	} else {
+		err = r.gz.Reset(r.src)
+	}
+	if err != nil {
+		return err
+	}
+	r.gz.Multistream(false)
+	r.rr = &recordReader{br: bufio.NewReader(r.gz)}
+	return nil
+}
+
+type countingReader struct {
+	br *bufio.Reader
+	n  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.br.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.br.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+type recordReader struct {
+	br    *bufio.Reader
+	pos   int64
+	block *io.LimitedReader
+}
+
+func (rr *recordReader) readLine() (string, error) {
+	s, err := rr.br.ReadString('\n')
+	rr.pos += int64(len(s))
+	if err == io.EOF && s != "" {
+		err = nil
+	}
+	return s, err
+}
+
+func (rr *recordReader) next() (*Record, error) {
+	// Drain the remaining of previous block.
+	if rr.block != nil {
+		n, err := io.Copy(io.Discard, rr.block)
+		rr.pos += n
+		rr.block = nil
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var offset int64
+	for {
+		offset = rr.pos
+		line, err := rr.readLine()
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "WARC/") {
+			return nil, fmt.Errorf("invalid WARC record at %d: %q", offset, line)
+		}
+		break
+	}
+
+	header := make(textproto.MIMEHeader)
+	for {
+		line, err := rr.readLine()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		header.Add(strings.TrimSpace(key), strings.TrimSpace(val))
+	}
+
+	length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length of WARC record at %d", offset)
+	}
+	rr.block = &io.LimitedReader{R: rr.br, N: length}
+
+	return &Record{Header: header, Offset: offset, Block: &blockReader{rr: rr}}, nil
+}
+
+type blockReader struct {
+	rr *recordReader
+}
+
+func (b *blockReader) Read(p []byte) (int, error) {
+	if b.rr.block == nil {
+		return 0, io.EOF
+	}
+	n, err := b.rr.block.Read(p)
+	b.rr.pos += int64(n)
+	return n, err
+}
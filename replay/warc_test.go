@@ -0,0 +1,99 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package replay // import "github.com/wabarc/wayback/replay"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type record struct {
+	id, uri, date, body string
+}
+
+func (r record) String() string {
+	block := "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n" +
+		fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(r.body), r.body)
+	return fmt.Sprintf("WARC/1.0\r\nWARC-Type: response\r\nWARC-Record-ID: %s\r\n"+
+		"WARC-Target-URI: %s\r\nWARC-Date: %s\r\nContent-Type: application/http;msgtype=response\r\n"+
+		"Content-Length: %d\r\n\r\n%s\r\n\r\n", r.id, r.uri, r.date, len(block), block)
+}
+
+var records = []record{
+	{id: "<urn:uuid:1>", uri: "https://example.com/", date: "2021-01-01T00:00:00Z", body: `<html><body><a href="/about">About</a></body></html>`},
+	{id: "<urn:uuid:2>", uri: "<https://example.com/>", date: "2022-01-01T00:00:00Z", body: `<html><body>Hello</body></html>`},
+	{id: "<urn:uuid:3>", uri: "https://example.com/about", date: "2022-01-01T00:00:01Z", body: `<html><body>About</body></html>`},
+}
+
+func warcFile(t *testing.T, gzipped bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	warcinfo := "WARC/1.0\r\nWARC-Type: warcinfo\r\nWARC-Record-ID: <urn:uuid:0>\r\nContent-Length: 0\r\n\r\n\r\n\r\n"
+	for _, s := range append([]string{warcinfo}, stringify(records)...) {
+		if !gzipped {
+			buf.WriteString(s)
+			continue
+		}
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		zw.Close()
+	}
+	return buf.Bytes()
+}
+
+func stringify(list []record) (s []string) {
+	for _, r := range list {
+		s = append(s, r.String())
+	}
+	return
+}
+
+func TestReader(t *testing.T) {
+	for _, gzipped := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzipped=%t", gzipped), func(t *testing.T) {
+			data := warcFile(t, gzipped)
+			rd := NewReader(bytes.NewReader(data), gzipped)
+
+			var got []*Record
+			for {
+				rec, err := rd.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected read record: %v", err)
+				}
+				got = append(got, rec)
+			}
+			if len(got) != len(records)+1 {
+				t.Fatalf("unexpected records number, got %d instead of %d", len(got), len(records)+1)
+			}
+			for i, rec := range got[1:] {
+				if rec.ID() != records[i].id {
+					t.Errorf("unexpected record id, got %s instead of %s", rec.ID(), records[i].id)
+				}
+				if rec.TargetURI() != "https://example.com/" && rec.TargetURI() != "https://example.com/about" {
+					t.Errorf("unexpected target uri: %s", rec.TargetURI())
+				}
+
+				// Record must be read from its offset.
+				rd := NewReader(bytes.NewReader(data[rec.Offset:]), gzipped)
+				r, err := rd.Next()
+				if err != nil {
+					t.Fatalf("unexpected read record at offset %d: %v", rec.Offset, err)
+				}
+				if r.ID() != rec.ID() {
+					t.Errorf("unexpected record at offset %d, got %s instead of %s", rec.Offset, r.ID(), rec.ID())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+/*
+Package schedule implements the scheduler that archives the watched URLs
+periodically, the watches are stored in the bolt database and their runs
+are put into the worker pool as background jobs.
+*/
+package schedule // import "github.com/wabarc/wayback/schedule"
@@ -0,0 +1,255 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package schedule // import "github.com/wabarc/wayback/schedule"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/pooling"
+)
+
+// Kind is the kind of the buckets of the scheduled runs, see pooling.Bucket.
+const Kind = "schedule"
+
+// interval is the interval to check the due watches, cron schedules are
+// precise to the minute.
+const interval = 30 * time.Second
+
+// Store stores the watches and the items of the feeds seen. It is
+// implemented by storage.Storage.
+type Store interface {
+	Watch(id uint64) (*entity.Watch, error)
+	Watches() ([]*entity.Watch, error)
+	UpdateWatch(*entity.Watch) error
+	MarkSeen(feed string, items []string) error
+}
+
+// Func archives the URL of the watch.
+type Func func(ctx context.Context, w *entity.Watch) error
+
+// ExpandFunc returns the URLs of the new items of the feed of the watch, an
+// item is marked as seen once it is archived by Func.
+type ExpandFunc func(ctx context.Context, w *entity.Watch) ([]string, error)
+
+// Parse parses the schedule, which is a standard cron expression with five
+// fields, e.g. `0 8 * * *`, or a descriptor, e.g. `@daily` or `@every 6h`.
+// The schedule is in the local time zone unless it is prefixed with the
+// time zone, e.g. `CRON_TZ=Asia/Tokyo 0 8 * * *`.
+func Parse(spec string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	return sched, nil
+}
+
+// NewWatch returns a watch of the URL on the schedule created by the owner,
+// its first run is the next activation of the schedule.
+func NewWatch(rawURL, spec, owner string) (*entity.Watch, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", rawURL)
+	}
+	sched, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &entity.Watch{
+		URL:      u.String(),
+		Schedule: strings.TrimSpace(spec),
+		Owner:    owner,
+		Created:  now.UTC(),
+		NextRun:  sched.Next(now),
+	}, nil
+}
+
+// Scheduler puts the runs of the due watches into the worker pool.
+type Scheduler struct {
+	ctx    context.Context
+	store  Store
+	pool   *pooling.Pool
+	fn     Func
+	expand ExpandFunc
+
+	// pending holds the items of the feeds in the pool, so that the items
+	// are not put again by the next runs of the feeds before archived.
+	pending sync.Map
+}
+
+// New returns a Scheduler that archives the watches of the store by fn, the
+// new items of the feed watches are expanded by expand and archived by fn
+// separately, the feed watches are archived as pages if expand is nil.
+func New(ctx context.Context, store Store, pool *pooling.Pool, fn Func, expand ExpandFunc) *Scheduler {
+	return &Scheduler{ctx: ctx, store: store, pool: pool, fn: fn, expand: expand}
+}
+
+// Run resumes the runs interrupted by last shutdown and checks the due
+// watches until the context is done. It is blocking and should be handled
+// in a separate goroutine.
+func (s *Scheduler) Run() {
+	if err := s.pool.Resume(Kind, s.resume); err != nil {
+		logger.Error("resume scheduled runs failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.tick(time.Now())
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick puts the runs of the watches due at now into the pool, a watch
+// missed several activations while the service was down runs once.
+func (s *Scheduler) tick(now time.Time) {
+	watches, err := s.store.Watches()
+	if err != nil {
+		logger.Error("query watches failed: %v", err)
+		return
+	}
+
+	for _, w := range watches {
+		if w.NextRun.After(now) {
+			continue
+		}
+		sched, err := Parse(w.Schedule)
+		if err != nil {
+			logger.Error("watch %d: %v", w.ID, err)
+			continue
+		}
+		w.NextRun = sched.Next(now)
+		w.LastRun = now.UTC()
+		w.Runs++
+		if err = s.store.UpdateWatch(w); err != nil {
+			logger.Error("update watch %d failed: %v", w.ID, err)
+			continue
+		}
+
+		b, err := s.bucket(job{WatchID: w.ID})
+		if err != nil {
+			logger.Error("watch %d: %v", w.ID, err)
+			continue
+		}
+		logger.Info("run watch %d of %s, next run at %s", w.ID, w.URL, w.NextRun.Format(time.RFC3339))
+		s.pool.Put(b)
+	}
+}
+
+// job is the payload of the buckets, the URL is the item of a feed watch.
+type job struct {
+	WatchID uint64 `json:"watch_id"`
+	URL     string `json:"url,omitempty"`
+}
+
+// bucket returns the bucket running the job, the watch is loaded once the
+// bucket runs, so that a deleted watch does not run.
+func (s *Scheduler) bucket(j job) (pooling.Bucket, error) {
+	payload, err := json.Marshal(j)
+	if err != nil {
+		return pooling.Bucket{}, err
+	}
+	id := j.WatchID
+	done := func() {}
+	if j.URL != "" {
+		s.pending.Store(j, struct{}{})
+		done = func() { s.pending.Delete(j) }
+	}
+
+	return pooling.Bucket{
+		Kind:     Kind,
+		Payload:  payload,
+		Source:   Kind,
+		Priority: pooling.PriorityLow,
+		Request: func(ctx context.Context) error {
+			defer done()
+
+			w, err := s.store.Watch(id)
+			if err != nil {
+				logger.Warn("skipped run of watch %d: %v", id, err)
+				return nil
+			}
+
+			err = s.run(ctx, w, j.URL)
+
+			// Reload the watch, it may be updated while running.
+			if w, er := s.store.Watch(id); er == nil {
+				w.LastError = ""
+				if err != nil {
+					w.LastError = err.Error()
+				}
+				if er = s.store.UpdateWatch(w); er != nil {
+					logger.Warn("update watch %d failed: %v", id, er)
+				}
+			}
+			return err
+		},
+		Fallback: func(_ context.Context) error {
+			done()
+			return nil
+		},
+	}, nil
+}
+
+// run archives the item of the feed watch if given, or puts the new items
+// of the feed watch into the pool, or archives the URL of the watch.
+func (s *Scheduler) run(ctx context.Context, w *entity.Watch, item string) error {
+	switch {
+	case item != "":
+		feed := w.URL
+		w.URL, w.Feed = item, false
+		if err := s.fn(ctx, w); err != nil {
+			return err
+		}
+		// The items failed to archive are retried by the next runs.
+		if err := s.store.MarkSeen(feed, []string{item}); err != nil {
+			logger.Warn("watch %d: mark %s as seen failed: %v", w.ID, item, err)
+		}
+		return nil
+	case w.Feed && s.expand != nil:
+		items, err := s.expand(ctx, w)
+		if err != nil {
+			return err
+		}
+		logger.Info("watch %d: %d new items of %s", w.ID, len(items), w.URL)
+		for _, item := range items {
+			if _, ok := s.pending.Load(job{WatchID: w.ID, URL: item}); ok {
+				continue
+			}
+			b, err := s.bucket(job{WatchID: w.ID, URL: item})
+			if err != nil {
+				return err
+			}
+			s.pool.Put(b)
+		}
+		return nil
+	default:
+		return s.fn(ctx, w)
+	}
+}
+
+// resume rebuilds the bucket from the payload of a persisted job.
+func (s *Scheduler) resume(payload []byte) (pooling.Bucket, error) {
+	var j job
+	if err := json.Unmarshal(payload, &j); err != nil {
+		return pooling.Bucket{}, err
+	}
+	return s.bucket(j)
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package schedule // import "github.com/wabarc/wayback/schedule"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/pooling"
+)
+
+type store struct {
+	mu      sync.Mutex
+	watches map[uint64]*entity.Watch
+	seen    []string
+}
+
+func (s *store) Watch(id uint64) (*entity.Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.watches[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	c := *w
+	return &c, nil
+}
+
+func (s *store) Watches() ([]*entity.Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watches := make([]*entity.Watch, 0, len(s.watches))
+	for _, w := range s.watches {
+		c := *w
+		watches = append(watches, &c)
+	}
+	return watches, nil
+}
+
+func (s *store) UpdateWatch(w *entity.Watch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.watches[w.ID]; !ok {
+		return errors.New("not found")
+	}
+	c := *w
+	s.watches[w.ID] = &c
+	return nil
+}
+
+func (s *store) MarkSeen(_ string, items []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen = append(s.seen, items...)
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		fail bool
+	}{
+		{"0 8 * * *", false},
+		{"@daily", false},
+		{"@every 6h", false},
+		{"CRON_TZ=Asia/Tokyo 0 8 * * *", false},
+		{"0 0 8 * * *", true},
+		{"daily", true},
+		{"", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			_, err := Parse(test.spec)
+			if (err != nil) != test.fail {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewWatch(t *testing.T) {
+	w, err := NewWatch("https://example.com", " @every 1h ", "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if w.Schedule != "@every 1h" || w.Owner != "test" {
+		t.Errorf("Unexpected watch: %+v", w)
+	}
+	if d := w.NextRun.Sub(w.Created); d < 59*time.Minute || d > time.Hour+time.Minute {
+		t.Errorf("Unexpected next run %s after creation", d)
+	}
+
+	for _, u := range []string{"example.com", "ftp://example.com", "https://"} {
+		if _, err := NewWatch(u, "@daily", "test"); err == nil {
+			t.Errorf("Unexpected watch of %q", u)
+		}
+	}
+	if _, err := NewWatch("https://example.com", "daily", "test"); err == nil {
+		t.Error("Unexpected watch on invalid schedule")
+	}
+}
+
+func TestTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The delay of @every is rounded to the second.
+	now := time.Now().Truncate(time.Second)
+	s := &store{watches: map[uint64]*entity.Watch{
+		1: {ID: 1, URL: "https://example.com/due", Schedule: "@every 1h", NextRun: now.Add(-time.Minute)},
+		2: {ID: 2, URL: "https://example.com/later", Schedule: "@every 1h", NextRun: now.Add(time.Minute)},
+		3: {ID: 3, URL: "https://example.com/failed", Schedule: "@every 1h", NextRun: now.Add(-48 * time.Hour)},
+	}}
+
+	var mu sync.Mutex
+	done := make(chan struct{}, 3)
+	runs := []string{}
+	fn := func(_ context.Context, w *entity.Watch) error {
+		mu.Lock()
+		runs = append(runs, w.URL)
+		mu.Unlock()
+		defer func() { done <- struct{}{} }()
+		if w.ID == 3 {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	pool := pooling.New(ctx, pooling.Capacity(1), pooling.Timeout(time.Second))
+	go pool.Roll()
+	defer pool.Close()
+
+	New(ctx, s, pool, fn, nil).tick(now)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for runs")
+		}
+	}
+	// Wait for the last error to be updated after the run.
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(runs) != 2 {
+		t.Fatalf("Unexpected runs: %v", runs)
+	}
+
+	due, _ := s.Watch(1)
+	if due.Runs != 1 || !due.NextRun.Equal(now.Add(time.Hour)) || due.LastError != "" {
+		t.Errorf("Unexpected due watch: %+v", due)
+	}
+	later, _ := s.Watch(2)
+	if later.Runs != 0 {
+		t.Errorf("Unexpected run of later watch: %+v", later)
+	}
+	failed, _ := s.Watch(3)
+	if failed.Runs != 1 || !failed.NextRun.Equal(now.Add(time.Hour)) || failed.LastError != "failed" {
+		t.Errorf("Unexpected failed watch: %+v", failed)
+	}
+}
+
+func TestRunFeed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed := &entity.Watch{ID: 1, URL: "https://example.com/feed.xml", Schedule: "@hourly", Feed: true}
+	s := &store{watches: map[uint64]*entity.Watch{1: feed}}
+	items := []string{"https://example.com/posts/1", "https://example.com/posts/2"}
+	expand := func(_ context.Context, w *entity.Watch) ([]string, error) {
+		if w.URL != feed.URL {
+			t.Errorf("Unexpected expanded url %s", w.URL)
+		}
+		return items, nil
+	}
+
+	archived := make(chan *entity.Watch, len(items))
+	fn := func(_ context.Context, w *entity.Watch) error {
+		archived <- w
+		return nil
+	}
+
+	pool := pooling.New(ctx, pooling.Capacity(1), pooling.Timeout(time.Second))
+	go pool.Roll()
+	defer pool.Close()
+
+	if err := New(ctx, s, pool, fn, expand).run(ctx, feed, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := map[string]bool{}
+	for range items {
+		select {
+		case w := <-archived:
+			if w.Feed || w.ID != feed.ID {
+				t.Errorf("Unexpected archived watch: %+v", w)
+			}
+			got[w.URL] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the items")
+		}
+	}
+	for _, item := range items {
+		if !got[item] {
+			t.Errorf("Item %s not archived", item)
+		}
+	}
+}
+
+func TestRunFeedSeen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed := &entity.Watch{ID: 1, URL: "https://example.com/feed.xml", Schedule: "@hourly", Feed: true}
+	s := &store{watches: map[uint64]*entity.Watch{1: feed}}
+	items := []string{"https://example.com/posts/1", "https://example.com/posts/2"}
+	expand := func(_ context.Context, _ *entity.Watch) ([]string, error) {
+		return items, nil
+	}
+	fn := func(_ context.Context, w *entity.Watch) error {
+		if w.URL == items[1] {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	// The pool is not rolling, so the items stay pending.
+	pool := pooling.New(ctx, pooling.Capacity(1), pooling.Timeout(time.Second))
+	sched := New(ctx, s, pool, fn, expand)
+	for i := 0; i < 2; i++ {
+		if err := sched.run(ctx, &entity.Watch{ID: feed.ID, URL: feed.URL, Feed: true}, ""); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if jobs := pool.Jobs(); len(jobs) != len(items) {
+		t.Fatalf("Unexpected jobs of the pending items: %d", len(jobs))
+	}
+
+	for _, item := range items {
+		sched.run(ctx, &entity.Watch{ID: feed.ID, URL: feed.URL}, item) // nolint:errcheck
+	}
+	if len(s.seen) != 1 || s.seen[0] != items[0] {
+		t.Fatalf("Unexpected seen items: %v", s.seen)
+	}
+}
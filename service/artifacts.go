@@ -0,0 +1,43 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/reduxer"
+)
+
+// ArtifactsTag is the tag in a message to select the artifacts produced by
+// reduxer instead of the configured profile, e.g.
+// "https://example.com #artifacts=minimal" or "#artifacts=img,txt".
+const ArtifactsTag = "#artifacts="
+
+// Artifacts returns the profile of the artifacts specified by the
+// ArtifactsTag in the text, it is empty if not specified.
+func Artifacts(text string) string {
+	for _, field := range strings.Fields(text) {
+		if len(field) > len(ArtifactsTag) && strings.EqualFold(field[:len(ArtifactsTag)], ArtifactsTag) {
+			return field[len(ArtifactsTag):]
+		}
+	}
+	return ""
+}
+
+// WithArtifacts returns a copy of ctx which produces the artifacts of the
+// profile, ctx is returned as is if the profile is empty or invalid.
+func WithArtifacts(ctx context.Context, profile string) context.Context {
+	if profile == "" {
+		return ctx
+	}
+	p, err := reduxer.ParseProfile(profile)
+	if err != nil {
+		logger.Warn("ignore artifact profile %q: %v", profile, err)
+		return ctx
+	}
+	return reduxer.WithProfile(ctx, p)
+}
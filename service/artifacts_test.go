@@ -0,0 +1,44 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"testing"
+)
+
+func TestArtifacts(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"https://example.com", ""},
+		{"https://example.com #artifacts=minimal", "minimal"},
+		{"#ARTIFACTS=img,txt https://example.com", "img,txt"},
+		{"https://example.com #artifacts=", ""},
+		{"https://example.com/#artifacts=full", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := Artifacts(test.text); got != test.want {
+				t.Errorf("Unexpected artifacts, got %q instead of %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithArtifacts(t *testing.T) {
+	ctx := context.Background()
+	if got := WithArtifacts(ctx, ""); got != ctx {
+		t.Error("Unexpected context of the empty profile")
+	}
+	if got := WithArtifacts(ctx, "foo"); got != ctx {
+		t.Error("Unexpected context of the invalid profile")
+	}
+	if got := WithArtifacts(ctx, "minimal"); got == ctx {
+		t.Error("Unexpected context without the profile")
+	}
+}
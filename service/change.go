@@ -0,0 +1,134 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/change"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/storage"
+)
+
+// detect compares the pages of the collects held by rdx with the previous
+// captures of the same URLs stored in the database, and sets the changes of
+// the pages with the text diff and the visual diff beside their artifacts.
+func detect(ctx context.Context, opts *config.Options, store *storage.Storage, rdx reduxer.Reduxer, cols []wayback.Collect) {
+	if !opts.ChangeDetection() || store == nil || opts.IsDefaultDatabaseURL() || rdx == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, col := range cols {
+		if seen[col.Src] {
+			continue
+		}
+		seen[col.Src] = true
+
+		bundle, ok := rdx.Load(reduxer.Src(col.Src))
+		if !ok {
+			continue
+		}
+		prev, err := store.LastCapture(ctx, col.Src)
+		if err != nil {
+			if err != storage.ErrWaybackNotFound {
+				logger.Warn("lookup previous capture of %s failed: %v", col.Src, err)
+			}
+			continue
+		}
+
+		changed, diff, diffImg := compare(opts, prev, bundle.Artifact(), bundle.Article().TextContent)
+		logger.Info("compared %s with the capture at %s, changed: %t", col.Src, prev.Created.Format(time.RFC3339), changed)
+		bundle.SetChange(changed, diff, diffImg)
+	}
+}
+
+// compare compares the page of the artifact and the text with the previous
+// capture, it reports whether the page changed and returns the files of the
+// text diff and the visual diff if the page changed.
+func compare(opts *config.Options, prev *entity.Wayback, art reduxer.Artifact, text string) (changed bool, diff, diffImg string) {
+	base := basePath(art)
+	from := "capture at " + prev.Created.Format(time.RFC3339)
+	to := "capture at " + time.Now().UTC().Format(time.RFC3339)
+
+	var prevText string
+	if prev.Page != nil {
+		prevText = prev.Page.Text
+	}
+	unified, err := change.Text(prevText, text, from, to)
+	if err != nil {
+		logger.Warn("diff text of %s failed: %v", prev.Source, err)
+	}
+	changed = unified != ""
+
+	visual, ratio := visualDiff(prev, art)
+	changed = changed || ratio > opts.ChangeThreshold()
+	if !changed || base == "" {
+		return changed, "", ""
+	}
+
+	if unified != "" {
+		diff = base + ".diff"
+		if err = os.WriteFile(diff, helper.String2Byte(unified), 0o600); err != nil {
+			logger.Warn("write text diff failed: %v", err)
+			diff = ""
+		}
+	}
+	if visual != nil && ratio > 0 {
+		diffImg = base + ".diff.png"
+		if err = change.Save(diffImg, visual); err != nil {
+			logger.Warn("write visual diff failed: %v", err)
+			diffImg = ""
+		}
+	}
+	return changed, diff, diffImg
+}
+
+// visualDiff returns the visual diff and the ratio of the changed pixels of
+// the screenshots, or nil if either screenshot is not available.
+func visualDiff(prev *entity.Wayback, art reduxer.Artifact) (*image.NRGBA, float64) {
+	var prevImg string
+	for _, a := range prev.Artifacts {
+		if a.Kind == "img" {
+			prevImg = a.Path
+		}
+	}
+	if prevImg == "" || art.Img.Local == "" || !helper.Exists(prevImg) || !helper.Exists(art.Img.Local) {
+		return nil, 0
+	}
+
+	a, err := change.Open(prevImg)
+	if err != nil {
+		logger.Warn("open screenshot %s failed: %v", prevImg, err)
+		return nil, 0
+	}
+	b, err := change.Open(art.Img.Local)
+	if err != nil {
+		logger.Warn("open screenshot %s failed: %v", art.Img.Local, err)
+		return nil, 0
+	}
+	return change.Visual(a, b)
+}
+
+// basePath returns the path of the artifacts without the extension, or an
+// empty string if no artifacts on the local disk.
+func basePath(art reduxer.Artifact) string {
+	for _, asset := range []reduxer.Asset{art.Img, art.Txt, art.Raw} {
+		if asset.Local != "" {
+			return strings.TrimSuffix(asset.Local, filepath.Ext(asset.Local))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/change"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/storage"
+)
+
+func screenshot(t *testing.T, name string, black int) string {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+			if y*10+x < black {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	if err := change.Save(name, img); err != nil {
+		t.Fatalf("Save screenshot failed: %v", err)
+	}
+	return name
+}
+
+func TestCompare(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_CHANGE_THRESHOLD", "5")
+	defer os.Clearenv()
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	dir := t.TempDir()
+	prev := &entity.Wayback{
+		Source:    "https://example.com/",
+		Created:   time.Now().Add(-time.Hour),
+		Page:      &entity.Page{Text: "Terms of service"},
+		Artifacts: []entity.Artifact{{Kind: "img", Path: screenshot(t, filepath.Join(dir, "prev.png"), 0)}},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		black   int
+		changed bool
+		diff    bool
+		diffImg bool
+	}{
+		{"unchanged", "Terms of  service", 0, false, false, false},
+		{"below threshold", "Terms of service", 3, false, false, false},
+		{"text changed", "Terms of service updated", 0, true, true, false},
+		{"visual changed", "Terms of service", 10, true, false, true},
+	}
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			art := reduxer.Artifact{Img: reduxer.Asset{Local: screenshot(t, filepath.Join(dir, test.name+".png"), test.black)}}
+			changed, diff, diffImg := compare(opts, prev, art, test.text)
+			if changed != test.changed {
+				t.Errorf("Unexpected changed of test %d, got %t instead of %t", i, changed, test.changed)
+			}
+			if (diff != "") != test.diff || (diffImg != "") != test.diffImg {
+				t.Fatalf("Unexpected diff files %q and %q", diff, diffImg)
+			}
+			if diff != "" {
+				buf, _ := os.ReadFile(diff)
+				if !strings.Contains(string(buf), "+Terms of service updated") {
+					t.Errorf("Unexpected text diff:\n%s", buf)
+				}
+			}
+			if diffImg != "" && diffImg != filepath.Join(dir, test.name+".diff.png") {
+				t.Errorf("Unexpected visual diff file %s", diffImg)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "wayback.db")
+	os.Clearenv()
+	os.Setenv("WAYBACK_DATABASE_URL", dsn)
+	os.Setenv("WAYBACK_CHANGE_DETECTION", "true")
+	defer os.Clearenv()
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	db, err := storage.NewConnectionPool(dsn, 1, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Connect to database failed: %v", err)
+	}
+	if err = storage.Migrate(db); err != nil {
+		t.Fatalf("Migrate database failed: %v", err)
+	}
+	store := storage.NewStorage(db, nil)
+	defer store.Close()
+
+	ctx := context.Background()
+	src := "https://example.com/"
+	cols := []wayback.Collect{{Arc: config.SLOT_IA, Src: src, Status: wayback.StatusSuccess}}
+
+	// The first capture has nothing to compare.
+	rdx := reduxer.BundleExample()
+	detect(ctx, opts, store, rdx, cols)
+	if bundle, _ := rdx.Load(reduxer.Src(src)); !bundle.Changed() {
+		t.Fatal("Unexpected unchanged first capture")
+	}
+	if err = store.CreateWayback(ctx, cols, rdx); err != nil {
+		t.Fatalf("Create wayback failed: %v", err)
+	}
+
+	rdx = reduxer.BundleExample()
+	detect(ctx, opts, store, rdx, cols)
+	if bundle, _ := rdx.Load(reduxer.Src(src)); bundle.Changed() {
+		t.Fatal("Unexpected changed capture of the same page")
+	}
+}
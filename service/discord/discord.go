@@ -22,6 +22,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -40,11 +41,12 @@ var ErrServiceClosed = errors.New("discord: Service closed")
 type Discord struct {
 	ctx context.Context
 
-	bot   *discord.Session
-	store *storage.Storage
-	opts  *config.Options
-	pool  *pooling.Pool
-	pub   *publish.Publish
+	bot     *discord.Session
+	store   *storage.Storage
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	pub     *publish.Publish
 }
 
 // New returns a Discord struct.
@@ -66,12 +68,13 @@ func New(ctx context.Context, opts service.Options) (*Discord, error) {
 	}
 
 	return &Discord{
-		ctx:   ctx,
-		bot:   bot,
-		store: opts.Storage,
-		opts:  opts.Config,
-		pool:  opts.Pool,
-		pub:   opts.Publish,
+		ctx:     ctx,
+		bot:     bot,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -192,6 +195,37 @@ func (d *Discord) commandHandlers() map[string]func(*discord.Session, *discord.I
 				},
 			})
 		},
+		service.CommandStatus: func(s *discord.Session, i *discord.InteractionCreate) {
+			// nolint:errcheck
+			s.InteractionRespond(i.Interaction, &discord.InteractionResponse{
+				Type: discord.InteractionResponseChannelMessageWithSource,
+				Data: &discord.InteractionResponseData{
+					Content: service.JobStatus(d.pool, metrics.ServiceDiscord+":"+i.ChannelID),
+				},
+			})
+		},
+		service.CommandSearch: func(s *discord.Session, i *discord.InteractionCreate) {
+			var query string
+			if options := i.ApplicationCommandData().Options; len(options) > 0 {
+				query = options[0].StringValue()
+			}
+			// nolint:errcheck
+			s.InteractionRespond(i.Interaction, &discord.InteractionResponse{
+				Type: discord.InteractionResponseChannelMessageWithSource,
+				Data: &discord.InteractionResponseData{
+					Content: service.Search(d.ctx, d.opts, d.store, query),
+				},
+			})
+		},
+		service.CommandWatch: func(s *discord.Session, i *discord.InteractionCreate) {
+			d.respond(s, i, service.Watch(d.store, metrics.ServiceDiscord+":"+i.ChannelID, optionValues(i)))
+		},
+		service.CommandUnwatch: func(s *discord.Session, i *discord.InteractionCreate) {
+			d.respond(s, i, service.Unwatch(d.store, metrics.ServiceDiscord+":"+i.ChannelID, optionValues(i)))
+		},
+		service.CommandWatches: func(s *discord.Session, i *discord.InteractionCreate) {
+			d.respond(s, i, service.Watches(d.store, metrics.ServiceDiscord+":"+i.ChannelID))
+		},
 		service.CommandPrivacy: func(s *discord.Session, i *discord.InteractionCreate) {
 			// nolint:errcheck
 			s.InteractionRespond(i.Interaction, &discord.InteractionResponse{
@@ -204,6 +238,34 @@ func (d *Discord) commandHandlers() map[string]func(*discord.Session, *discord.I
 	}
 }
 
+// respond responds the interaction with the content.
+func (d *Discord) respond(s *discord.Session, i *discord.InteractionCreate, content string) {
+	// nolint:errcheck
+	s.InteractionRespond(i.Interaction, &discord.InteractionResponse{
+		Type: discord.InteractionResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	})
+}
+
+// optionValues returns the values of the string options of the application
+// command joined by spaces, the enabled boolean options are given by tags.
+func optionValues(i *discord.InteractionCreate) string {
+	var values []string
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Type {
+		case discord.ApplicationCommandOptionString:
+			values = append(values, option.StringValue())
+		case discord.ApplicationCommandOptionBoolean:
+			if option.BoolValue() {
+				values = append(values, "#"+option.Name)
+			}
+		}
+	}
+	return strings.Join(values, " ")
+}
+
 func (d *Discord) buttonHandlers() map[string]func(*discord.Session, *discord.InteractionCreate) {
 	return map[string]func(s *discord.Session, i *discord.InteractionCreate){
 		service.CommandPlayback: func(s *discord.Session, i *discord.InteractionCreate) {
@@ -269,6 +331,9 @@ func (d *Discord) process(m *discord.MessageCreate) (err error) {
 		logger.Warn("archives failure, URL no found.")
 		metrics.IncrementWayback(metrics.ServiceDiscord, metrics.StatusRequest)
 		d.reply(m, "URL no found.") // nolint:errcheck
+	case !d.limiter.Allow(metrics.ServiceDiscord, m.Author.ID):
+		logger.Warn("archives rejected, rate limit exceeded.")
+		d.reply(m, service.MsgRateLimited) // nolint:errcheck
 	default:
 		metrics.IncrementWayback(metrics.ServiceDiscord, metrics.StatusRequest)
 		if m, err = d.reply(m, "Queue..."); err != nil {
@@ -276,8 +341,13 @@ func (d *Discord) process(m *discord.MessageCreate) (err error) {
 			return
 		}
 		bucket := pooling.Bucket{
+			Source: metrics.ServiceDiscord + ":" + m.ChannelID,
 			Request: func(ctx context.Context) error {
 				logger.Debug("content: %v", urls)
+				if service.Forced(content) {
+					ctx = service.Force(ctx)
+				}
+				ctx = service.WithArtifacts(ctx, service.Artifacts(content))
 				if err := d.wayback(ctx, m, urls); err != nil {
 					logger.Error("archives failed: %v", err)
 					// nolint:errcheck
@@ -340,7 +410,7 @@ func (d *Discord) wayback(ctx context.Context, m *discord.MessageCreate, urls []
 		return nil
 	}
 
-	return service.Wayback(ctx, d.opts, urls, do)
+	return service.Wayback(ctx, d.opts, d.store, urls, do)
 }
 
 func (d *Discord) playback(s *discord.Session, i *discord.InteractionCreate) error {
@@ -465,6 +535,62 @@ func (d *Discord) requires() (commands []*discord.ApplicationCommand) {
 			Description: "Read our privacy policy",
 		})
 	}
+	commands = append(commands, &discord.ApplicationCommand{
+		Name:        service.CommandStatus,
+		Description: "Show archiving jobs",
+	})
+	commands = append(commands, &discord.ApplicationCommand{
+		Name:        service.CommandWatch,
+		Description: "Archive a URL on a schedule",
+		Options: []*discord.ApplicationCommandOption{
+			{
+				Type:        discord.ApplicationCommandOptionString,
+				Name:        "url",
+				Description: "URL to archive",
+				Required:    true,
+			},
+			{
+				Type:        discord.ApplicationCommandOptionString,
+				Name:        "schedule",
+				Description: "Cron expression or descriptor, defaults to " + service.DefaultSchedule,
+			},
+			{
+				Type:        discord.ApplicationCommandOptionBoolean,
+				Name:        "feed",
+				Description: "Archive the new items of the sitemap, feed or OPML list",
+			},
+		},
+	})
+	commands = append(commands, &discord.ApplicationCommand{
+		Name:        service.CommandUnwatch,
+		Description: "Stop archiving a watched URL",
+		Options: []*discord.ApplicationCommandOption{
+			{
+				Type:        discord.ApplicationCommandOptionString,
+				Name:        "id",
+				Description: "ID of the watch",
+				Required:    true,
+			},
+		},
+	})
+	commands = append(commands, &discord.ApplicationCommand{
+		Name:        service.CommandWatches,
+		Description: "List watched URLs",
+	})
+	if !d.opts.IsDefaultDatabaseURL() {
+		commands = append(commands, &discord.ApplicationCommand{
+			Name:        service.CommandSearch,
+			Description: "Search archived pages",
+			Options: []*discord.ApplicationCommandOption{
+				{
+					Type:        discord.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "Words in the title, summary or content",
+					Required:    true,
+				},
+			},
+		})
+	}
 	commands = append(commands, &discord.ApplicationCommand{
 		Name:        service.CommandPlayback,
 		Description: "Playback archived url",
@@ -0,0 +1,83 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/storage"
+)
+
+// ForceTag is the tag in a message to archive the URLs again regardless
+// of the freshness window, e.g. "https://example.com #force".
+const ForceTag = "#force"
+
+type ctxForceKey struct{}
+
+// Force returns a copy of ctx which archives the URLs again regardless of
+// the freshness window.
+func Force(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxForceKey{}, true)
+}
+
+func forced(ctx context.Context) bool {
+	force, _ := ctx.Value(ctxForceKey{}).(bool)
+	return force
+}
+
+// Forced reports whether the text contains the ForceTag.
+func Forced(text string) bool {
+	for _, field := range strings.Fields(text) {
+		if strings.EqualFold(field, ForceTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// recent returns the collects of the recent captures of the URLs within the
+// freshness window, it reports false unless all the URLs are fresh.
+func recent(ctx context.Context, opts *config.Options, store *storage.Storage, urls []*url.URL) ([]wayback.Collect, bool) {
+	window := opts.FreshnessWindow()
+	if window <= 0 || store == nil || opts.IsDefaultDatabaseURL() || forced(ctx) {
+		return nil, false
+	}
+
+	since := time.Now().Add(-window)
+	cols := []wayback.Collect{}
+	for _, u := range urls {
+		w, err := store.RecentWayback(ctx, u.String(), since)
+		if err != nil {
+			if err != storage.ErrWaybackNotFound {
+				logger.Warn("lookup recent captures of %s failed: %v", u, err)
+			}
+			return nil, false
+		}
+		for _, a := range w.Archives {
+			col := wayback.Collect{
+				Arc:      a.Slot,
+				Dst:      a.Dest,
+				Src:      w.Source,
+				Ext:      a.Slot,
+				Status:   wayback.ParseStatus(a.Status),
+				Elapsed:  time.Duration(a.Elapsed) * time.Millisecond,
+				Attempts: a.Attempts,
+				Cached:   true,
+			}
+			if a.Error != "" {
+				col.Err = errors.New(a.Error)
+			}
+			cols = append(cols, col)
+		}
+	}
+	return cols, len(cols) > 0
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestForced(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"https://example.com", false},
+		{"https://example.com #force", true},
+		{"#FORCE https://example.com", true},
+		{"https://example.com/#force", false},
+		{"#forced https://example.com", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := Forced(test.text); got != test.want {
+				t.Errorf("Unexpected forced, got %t instead of %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestForce(t *testing.T) {
+	ctx := context.Background()
+	if forced(ctx) {
+		t.Fatal("Unexpected forced context")
+	}
+	if !forced(Force(ctx)) {
+		t.Fatal("Unexpected not forced context")
+	}
+}
+
+func TestRecentDisabled(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("WAYBACK_FRESHNESS_WINDOW", "600")
+	defer os.Clearenv()
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	u, _ := url.Parse("https://example.com")
+	store := storage.NewStorage(nil, nil)
+
+	// The freshness window requires the database, it is not set here.
+	if _, ok := recent(context.Background(), opts, store, []*url.URL{u}); ok {
+		t.Fatal("Unexpected recent captures without database")
+	}
+	if _, ok := recent(Force(context.Background()), opts, nil, []*url.URL{u}); ok {
+		t.Fatal("Unexpected recent captures of forced request")
+	}
+}
@@ -0,0 +1,679 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/errors"
+	"github.com/wabarc/wayback/metrics"
+	"github.com/wabarc/wayback/pooling"
+	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/service"
+	"github.com/wabarc/wayback/storage"
+	"github.com/wabarc/wayback/template"
+)
+
+const (
+	apiPrefix = "/api/v1"
+
+	// maxBodySize is the maximum size of request bodies of the API.
+	maxBodySize = 1 << 20
+
+	// maxResults is the number of archiving results kept for the jobs of the API.
+	maxResults = 256
+
+	defPerPage = 20
+	maxPerPage = 100
+)
+
+// apiRoute describes an endpoint of the API, both the router and the
+// OpenAPI document are built from it.
+type apiRoute struct {
+	Method  string
+	Path    string // Path template relative to apiPrefix, e.g. /jobs/{id:[0-9]+}
+	Summary string
+	Scope   entity.Scope // Scope required if the authentication is enabled, empty for public
+	Query   []apiParam
+
+	Request  interface{} // Zero value of the request body, nil if there is none
+	Response interface{} // Zero value of the response body, nil if there is none
+	Status   int         // Status code of the successful response
+
+	Handler http.HandlerFunc
+}
+
+// apiParam describes a query parameter of an endpoint.
+type apiParam struct {
+	Name        string
+	Type        string // Schema type, e.g. string, integer
+	Description string
+}
+
+// apiError is the error object of all failed responses of the API.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+// archiveRequest is the request body to archive or playback URLs.
+type archiveRequest struct {
+	URLs      []string `json:"urls"`
+	Force     bool     `json:"force,omitempty"`     // Archive again regardless of the freshness window
+	Artifacts string   `json:"artifacts,omitempty"` // Profile of the artifacts, e.g. minimal or img,txt
+}
+
+// apiJob represents an archiving job, the results and artifacts are
+// present once the job is done.
+type apiJob struct {
+	pooling.Job
+
+	Results   template.Collector `json:"results,omitempty"`
+	Artifacts []artifact         `json:"artifacts,omitempty"`
+}
+
+// artifact represents a file produced by reduxer for a source URL.
+type artifact struct {
+	Src    string `json:"src"`
+	Kind   string `json:"kind"` // One of img, pdf, raw, txt, har, htm, warc, media, diff and diff-img
+	File   string `json:"file,omitempty"`
+	URL    string `json:"url,omitempty"`    // URL served by the local capture slot
+	Remote string `json:"remote,omitempty"` // URL of the first location on the artifact stores
+
+	Locations []entity.Location `json:"locations,omitempty"`
+}
+
+type playbackResponse struct {
+	Results template.Collector `json:"results"`
+}
+
+type searchResponse struct {
+	Items   []entity.SearchHit `json:"items"`
+	Page    int                `json:"page"`
+	PerPage int                `json:"per_page"`
+	Total   int                `json:"total"`
+}
+
+type historyResponse struct {
+	Items   []entity.Wayback `json:"items"`
+	Page    int              `json:"page"`
+	PerPage int              `json:"per_page"`
+	Total   int              `json:"total"`
+}
+
+// results holds the archiving results of the jobs submitted by the API.
+type results struct {
+	mu    sync.RWMutex
+	items map[uint64]*archived
+	order []uint64
+}
+
+type archived struct {
+	cols      template.Collector
+	artifacts []artifact
+}
+
+// add registers the archived of the job, the oldest one is dropped if full.
+func (rs *results) add(id uint64, a *archived) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.items == nil {
+		rs.items = make(map[uint64]*archived)
+	}
+	rs.items[id] = a
+	rs.order = append(rs.order, id)
+	if len(rs.order) > maxResults {
+		delete(rs.items, rs.order[0])
+		rs.order = rs.order[1:]
+	}
+}
+
+// set sets the results of the archived, it is called by the job.
+func (rs *results) set(a *archived, cols template.Collector, artifacts []artifact) {
+	rs.mu.Lock()
+	a.cols = cols
+	a.artifacts = artifacts
+	rs.mu.Unlock()
+}
+
+func (rs *results) get(id uint64) (cols template.Collector, artifacts []artifact) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	if a, ok := rs.items[id]; ok {
+		return a.cols, a.artifacts
+	}
+	return nil, nil
+}
+
+func (web *web) apiRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			Method:   http.MethodPost,
+			Path:     "/archives",
+			Summary:  "Submit URLs to archive, the archiving runs as a job in the background",
+			Scope:    entity.ScopeArchive,
+			Request:  archiveRequest{},
+			Response: apiJob{},
+			Status:   http.StatusAccepted,
+			Handler:  web.apiArchive,
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/playback",
+			Summary:  "Search the archived URLs from the archive services",
+			Scope:    entity.ScopePlayback,
+			Request:  archiveRequest{},
+			Response: playbackResponse{},
+			Status:   http.StatusOK,
+			Handler:  web.apiPlayback,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/jobs",
+			Summary: "List the archiving jobs of the requester",
+			Scope:   entity.ScopeArchive,
+			Query: []apiParam{
+				{Name: "state", Type: "string", Description: "Filter jobs by state, e.g. queued, running, done"},
+			},
+			Response: []apiJob{},
+			Status:   http.StatusOK,
+			Handler:  web.apiListJobs,
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/jobs/{id:[0-9]+}",
+			Summary:  "Show an archiving job with its results and artifacts",
+			Scope:    entity.ScopeArchive,
+			Response: apiJob{},
+			Status:   http.StatusOK,
+			Handler:  web.apiShowJob,
+		},
+		{
+			Method:   http.MethodDelete,
+			Path:     "/jobs/{id:[0-9]+}",
+			Summary:  "Cancel a queued or running archiving job of the requester",
+			Scope:    entity.ScopeArchive,
+			Response: apiJob{},
+			Status:   http.StatusOK,
+			Handler:  web.apiCancelJob,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/history",
+			Summary: "List the archiving history stored in the database",
+			Scope:   entity.ScopeReadHistory,
+			Query: []apiParam{
+				{Name: "page", Type: "integer", Description: "Page number, starts from 1"},
+				{Name: "per_page", Type: "integer", Description: "Number of items per page, up to 100"},
+				{Name: "domain", Type: "string", Description: "Domain of the URLs, including subdomains"},
+				{Name: "slot", Type: "string", Description: "Slot the URLs archived to, e.g. ia"},
+				{Name: "since", Type: "string", Description: "Start date in the form of 2006-01-02 or RFC 3339"},
+				{Name: "until", Type: "string", Description: "End date in the form of 2006-01-02 or RFC 3339, inclusive"},
+				{Name: "url", Type: "string", Description: "Source URL"},
+				{Name: "q", Type: "string", Description: "Words in the title, summary or content of the pages"},
+			},
+			Response: historyResponse{},
+			Status:   http.StatusOK,
+			Handler:  web.apiHistory,
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/history/{id:[0-9]+}",
+			Summary:  "Show an archiving request with its page metadata and artifacts",
+			Scope:    entity.ScopeReadHistory,
+			Response: entity.Wayback{},
+			Status:   http.StatusOK,
+			Handler:  web.apiShowHistory,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/history/timeline",
+			Summary: "List all captures of a URL in chronological order",
+			Scope:   entity.ScopeReadHistory,
+			Query: []apiParam{
+				{Name: "url", Type: "string", Description: "Source URL, required"},
+			},
+			Response: []entity.Wayback{},
+			Status:   http.StatusOK,
+			Handler:  web.apiTimeline,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/search",
+			Summary: "Search the title, summary and content of the archived pages in order of relevance",
+			Scope:   entity.ScopeReadHistory,
+			Query: []apiParam{
+				{Name: "q", Type: "string", Description: "Words in the title, summary or content of the pages, required"},
+				{Name: "page", Type: "integer", Description: "Page number, starts from 1"},
+				{Name: "per_page", Type: "integer", Description: "Number of items per page, up to 100"},
+				{Name: "domain", Type: "string", Description: "Domain of the URLs, including subdomains"},
+				{Name: "slot", Type: "string", Description: "Slot the URLs archived to, e.g. ia"},
+				{Name: "since", Type: "string", Description: "Start date in the form of 2006-01-02 or RFC 3339"},
+				{Name: "until", Type: "string", Description: "End date in the form of 2006-01-02 or RFC 3339, inclusive"},
+			},
+			Response: searchResponse{},
+			Status:   http.StatusOK,
+			Handler:  web.apiSearch,
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/watches",
+			Summary:  "List the URLs archived on schedules",
+			Scope:    entity.ScopeArchive,
+			Response: []entity.Watch{},
+			Status:   http.StatusOK,
+			Handler:  web.apiListWatches,
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/watches",
+			Summary:  "Archive a URL on a schedule, e.g. a cron expression 0 8 * * * or @daily",
+			Scope:    entity.ScopeArchive,
+			Request:  watchRequest{},
+			Response: entity.Watch{},
+			Status:   http.StatusCreated,
+			Handler:  web.apiCreateWatch,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/watches/{id:[0-9]+}",
+			Summary: "Stop archiving a watched URL",
+			Scope:   entity.ScopeAdmin,
+			Status:  http.StatusNoContent,
+			Handler: web.apiDeleteWatch,
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/tokens",
+			Summary:  "List the API tokens",
+			Scope:    entity.ScopeAdmin,
+			Response: []apiToken{},
+			Status:   http.StatusOK,
+			Handler:  web.apiListTokens,
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     "/tokens",
+			Summary:  "Create an API token, the secret is only responded once",
+			Scope:    entity.ScopeAdmin,
+			Request:  tokenRequest{},
+			Response: createdToken{},
+			Status:   http.StatusCreated,
+			Handler:  web.apiCreateToken,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/tokens/{id:[0-9]+}",
+			Summary: "Revoke an API token",
+			Scope:   entity.ScopeAdmin,
+			Status:  http.StatusNoContent,
+			Handler: web.apiDeleteToken,
+		},
+	}
+}
+
+// handleAPI registers the routes of the API under apiPrefix.
+func (web *web) handleAPI() {
+	router := web.router.PathPrefix(apiPrefix).Subrouter()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, "endpoint not found")
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+	})
+
+	routes := web.apiRoutes()
+	for _, route := range routes {
+		handler := route.Handler
+		if route.Scope != "" {
+			handler = web.authorize(route.Scope, handler)
+		}
+		router.HandleFunc(route.Path, handler).Methods(route.Method)
+	}
+
+	doc := openAPIDocument(routes)
+	router.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, doc)
+	}).Methods(http.MethodGet)
+}
+
+func (web *web) apiArchive(w http.ResponseWriter, r *http.Request) {
+	metrics.IncrementWayback(metrics.ServiceWeb, metrics.StatusRequest)
+
+	if web.pool == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, pooling.ErrPoolNotExist.Error())
+		return
+	}
+	req, urls, err := web.decodeURLs(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user := requester(r)
+	if delay := web.limiter.Reserve(metrics.ServiceWeb, user); delay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		writeAPIError(w, http.StatusTooManyRequests, service.MsgRateLimited)
+		return
+	}
+
+	a := &archived{}
+	bucket := pooling.Bucket{
+		Source: metrics.ServiceWeb + ":" + user,
+		Request: func(ctx context.Context) error {
+			do := func(cols []wayback.Collect, rdx reduxer.Reduxer) error {
+				web.results.set(a, transform(cols), web.artifacts(rdx, urls))
+				metrics.IncrementWayback(metrics.ServiceWeb, metrics.StatusSuccess)
+				if web.pub != nil {
+					web.pub.Spread(context.Background(), rdx, cols, publish.FlagWeb)
+				}
+				return nil
+			}
+			if req.Force {
+				ctx = service.Force(ctx)
+			}
+			ctx = service.WithArtifacts(ctx, req.Artifacts)
+			return service.Wayback(ctx, web.opts, web.store, urls, do)
+		},
+		Fallback: func(_ context.Context) error {
+			metrics.IncrementWayback(metrics.ServiceWeb, metrics.StatusFailure)
+			return nil
+		},
+	}
+	id := web.pool.Put(bucket)
+	web.results.add(id, a)
+	logger.Info("api: archiving job %d submitted", id)
+
+	job, err := web.pool.Job(id)
+	if err != nil {
+		writeAPIJobError(w, err)
+		return
+	}
+	w.Header().Set("Location", apiPrefix+"/jobs/"+strconv.FormatUint(id, 10))
+	writeJSON(w, http.StatusAccepted, apiJob{Job: job})
+}
+
+func (web *web) apiPlayback(w http.ResponseWriter, r *http.Request) {
+	metrics.IncrementPlayback(metrics.ServiceWeb, metrics.StatusRequest)
+
+	_, urls, err := web.decodeURLs(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cols, err := wayback.Playback(r.Context(), web.opts, urls...)
+	if err != nil {
+		metrics.IncrementPlayback(metrics.ServiceWeb, metrics.StatusFailure)
+		writeAPIError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	metrics.IncrementPlayback(metrics.ServiceWeb, metrics.StatusSuccess)
+	writeJSON(w, http.StatusOK, playbackResponse{Results: transform(cols)})
+}
+
+func (web *web) apiListJobs(w http.ResponseWriter, r *http.Request) {
+	if web.pool == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, pooling.ErrPoolNotExist.Error())
+		return
+	}
+	state := entity.JobState(r.URL.Query().Get("state"))
+
+	jobs := []apiJob{}
+	for _, job := range web.pool.Jobs() {
+		if (state == "" || job.State == state) && owns(r, job) {
+			jobs = append(jobs, web.apiJob(job))
+		}
+	}
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func (web *web) apiShowJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(routeParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+	if web.pool == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, pooling.ErrPoolNotExist.Error())
+		return
+	}
+
+	job, err := web.pool.Job(id)
+	if err == nil && !owns(r, job) {
+		err = pooling.ErrJobNotFound
+	}
+	if err != nil {
+		writeAPIJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, web.apiJob(job))
+}
+
+func (web *web) apiCancelJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(routeParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+	if web.pool == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, pooling.ErrPoolNotExist.Error())
+		return
+	}
+	if job, err := web.pool.Job(id); err == nil && !owns(r, job) {
+		writeAPIError(w, http.StatusForbidden, "job submitted by others")
+		return
+	}
+	logger.Info("api: cancel job %d", id)
+
+	if err = web.pool.Cancel(id); err != nil {
+		writeAPIJobError(w, err)
+		return
+	}
+	job, err := web.pool.Job(id)
+	if err != nil {
+		writeAPIJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, web.apiJob(job))
+}
+
+// owns reports whether the job was submitted by the requester, the admin
+// tokens own all the jobs, including the ones of the other services.
+func owns(r *http.Request, job pooling.Job) bool {
+	if token, ok := r.Context().Value(ctxTokenKey{}).(*entity.Token); ok && token.Allows(entity.ScopeAdmin) {
+		return true
+	}
+	return job.Source == metrics.ServiceWeb+":"+requester(r)
+}
+
+func (web *web) apiHistory(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		writeAPIError(w, http.StatusServiceUnavailable, "history requires WAYBACK_DATABASE_URL")
+		return
+	}
+
+	filter, page, err := historyQuery(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := web.store.CountWaybacks(r.Context(), filter)
+	if err != nil {
+		logger.Error("api: count history failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "query history failed")
+		return
+	}
+	items, err := web.store.Waybacks(r.Context(), filter)
+	if err != nil {
+		logger.Error("api: query history failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "query history failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, historyResponse{Items: items, Page: page, PerPage: filter.Limit, Total: total})
+}
+
+func (web *web) apiShowHistory(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		writeAPIError(w, http.StatusServiceUnavailable, "history requires WAYBACK_DATABASE_URL")
+		return
+	}
+
+	id, err := strconv.ParseInt(routeParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid history id")
+		return
+	}
+	item, err := web.store.Wayback(r.Context(), id)
+	if err == storage.ErrWaybackNotFound {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("api: query history %d failed: %v", id, err)
+		writeAPIError(w, http.StatusInternalServerError, "query history failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (web *web) apiSearch(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		writeAPIError(w, http.StatusServiceUnavailable, "search requires WAYBACK_DATABASE_URL")
+		return
+	}
+
+	filter, page, err := historyQuery(r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Query == "" {
+		writeAPIError(w, http.StatusBadRequest, "q required")
+		return
+	}
+
+	total, err := web.store.CountWaybacks(r.Context(), filter)
+	if err != nil {
+		logger.Error("api: count search hits failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+	items, err := web.store.Search(r.Context(), filter)
+	if err != nil {
+		logger.Error("api: search %q failed: %v", filter.Query, err)
+		writeAPIError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, searchResponse{Items: items, Page: page, PerPage: filter.Limit, Total: total})
+}
+
+func (web *web) apiTimeline(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		writeAPIError(w, http.StatusServiceUnavailable, "history requires WAYBACK_DATABASE_URL")
+		return
+	}
+
+	source := strings.TrimSpace(r.URL.Query().Get("url"))
+	if source == "" {
+		writeAPIError(w, http.StatusBadRequest, "url required")
+		return
+	}
+	items, err := web.store.Timeline(r.Context(), source)
+	if err != nil {
+		logger.Error("api: query timeline failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "query timeline failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// decodeURLs decodes the archiveRequest of the request body and returns it
+// with the URLs matched by the configuration.
+func (web *web) decodeURLs(r *http.Request) (req archiveRequest, urls []*url.URL, err error) {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&req); err != nil {
+		return req, nil, errors.New("invalid request body: " + err.Error())
+	}
+
+	if req.Artifacts != "" {
+		if _, err = reduxer.ParseProfile(req.Artifacts); err != nil {
+			return req, nil, errors.New("invalid artifacts: " + err.Error())
+		}
+	}
+
+	urls = service.MatchURL(web.opts, strings.Join(req.URLs, " "))
+	if len(urls) == 0 {
+		return req, nil, service.ErrMissingURL
+	}
+	return req, urls, nil
+}
+
+func (web *web) apiJob(job pooling.Job) apiJob {
+	cols, artifacts := web.results.get(job.ID)
+	return apiJob{Job: job, Results: cols, Artifacts: artifacts}
+}
+
+// artifacts returns the artifacts produced by reduxer for the URLs, the
+// local files are linked if the local capture slot is enabled.
+func (web *web) artifacts(rdx reduxer.Reduxer, urls []*url.URL) []artifact {
+	if rdx == nil {
+		return nil
+	}
+	served := web.opts.EnabledReduxer() && web.opts.Slots()[config.SLOT_LC]
+
+	artifacts := []artifact{}
+	for _, u := range urls {
+		bundle, ok := rdx.Load(reduxer.Src(u.String()))
+		if !ok {
+			continue
+		}
+		for _, a := range bundle.Artifact().Assets() {
+			item := artifact{Src: u.String(), Kind: a.Kind, Remote: a.Remote.URL()}
+			for _, l := range a.Remote {
+				item.Locations = append(item.Locations, entity.Location{Store: l.Store, URL: l.URL})
+			}
+			if a.Local != "" {
+				item.File = filepath.Base(a.Local)
+				rel, err := filepath.Rel(web.opts.StorageDir(), a.Local)
+				if served && err == nil && !strings.HasPrefix(rel, "..") {
+					item.URL = web.opts.PublicURL() + config.LC_SLUG + "/" + filepath.ToSlash(rel)
+				}
+			}
+			artifacts = append(artifacts, item)
+		}
+	}
+	return artifacts
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	code := strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	writeJSON(w, status, apiErrorResponse{Error: apiError{Code: code, Message: message}})
+}
+
+func writeAPIJobError(w http.ResponseWriter, err error) {
+	writeAPIError(w, jobErrorStatus(err), err.Error())
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/pooling"
+	"github.com/wabarc/wayback/template"
+)
+
+func TestAPI(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	// The pool is not rolling, so the submitted jobs stay queued.
+	pool := pooling.New(context.Background(), pooling.Capacity(1), pooling.Timeout(time.Second))
+	server := httptest.NewServer(newWeb(context.Background(), opts, pool, nil).handle())
+	defer server.Close()
+
+	var tests = []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+		state  entity.JobState
+	}{
+		{"submit", http.MethodPost, "/archives", `{"urls":["https://example.com"]}`, http.StatusAccepted, "", entity.JobQueued},
+		{"submit invalid body", http.MethodPost, "/archives", `{"url":"https://example.com"}`, http.StatusBadRequest, "bad_request", ""},
+		{"submit without url", http.MethodPost, "/archives", `{"urls":["foo"]}`, http.StatusBadRequest, "bad_request", ""},
+		{"submit invalid artifacts", http.MethodPost, "/archives", `{"urls":["https://example.com"],"artifacts":"foo"}`, http.StatusBadRequest, "bad_request", ""},
+		{"show", http.MethodGet, "/jobs/1", "", http.StatusOK, "", entity.JobQueued},
+		{"cancel", http.MethodDelete, "/jobs/1", "", http.StatusOK, "", entity.JobCancelled},
+		{"cancel finished", http.MethodDelete, "/jobs/1", "", http.StatusConflict, "conflict", ""},
+		{"show not found", http.MethodGet, "/jobs/100", "", http.StatusNotFound, "not_found", ""},
+		{"history without database", http.MethodGet, "/history", "", http.StatusServiceUnavailable, "service_unavailable", ""},
+		{"timeline without database", http.MethodGet, "/history/timeline?url=https://example.com", "", http.StatusServiceUnavailable, "service_unavailable", ""},
+		{"search without database", http.MethodGet, "/search?q=example", "", http.StatusServiceUnavailable, "service_unavailable", ""},
+		{"show history without database", http.MethodGet, "/history/1", "", http.StatusServiceUnavailable, "service_unavailable", ""},
+		{"endpoint not found", http.MethodGet, "/foo", "", http.StatusNotFound, "not_found", ""},
+		{"method not allowed", http.MethodPut, "/jobs/1", "", http.StatusMethodNotAllowed, "method_not_allowed", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(test.method, server.URL+apiPrefix+test.path, strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected response: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+				t.Fatalf("Unexpected content type got %q instead of application/json", ct)
+			}
+
+			if test.code != "" {
+				var e apiErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+					t.Fatalf("Unexpected decode error: %v", err)
+				}
+				if e.Error.Code != test.code || e.Error.Message == "" {
+					t.Fatalf("Unexpected error object got %+v instead of code %s", e.Error, test.code)
+				}
+				return
+			}
+
+			var job apiJob
+			if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+				t.Fatalf("Unexpected decode job: %v", err)
+			}
+			if job.ID != 1 || job.State != test.state {
+				t.Fatalf("Unexpected job got #%d %s instead of #1 %s", job.ID, job.State, test.state)
+			}
+			if test.status == http.StatusAccepted && resp.Header.Get("Location") != apiPrefix+"/jobs/1" {
+				t.Fatalf("Unexpected location got %q", resp.Header.Get("Location"))
+			}
+		})
+	}
+}
+
+func TestAPIResults(t *testing.T) {
+	var rs results
+
+	a := &archived{}
+	rs.add(1, a)
+	rs.set(a, template.Collector{{Slot: "ia", Src: "https://example.com"}}, []artifact{{Src: "https://example.com", Kind: "pdf"}})
+
+	cols, artifacts := rs.get(1)
+	if len(cols) != 1 || len(artifacts) != 1 {
+		t.Fatalf("Unexpected results got %d collects and %d artifacts", len(cols), len(artifacts))
+	}
+
+	for i := uint64(2); i <= maxResults+1; i++ {
+		rs.add(i, &archived{})
+	}
+	if cols, _ := rs.get(1); cols != nil {
+		t.Fatal("Unexpected oldest results not dropped")
+	}
+	if len(rs.items) != maxResults {
+		t.Fatalf("Unexpected number of results got %d instead of %d", len(rs.items), maxResults)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	server := httptest.NewServer(newWeb(context.Background(), opts, nil, nil).handle())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + apiPrefix + "/openapi.json")
+	if err != nil {
+		t.Fatalf("Unexpected response: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+		Comps   struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("Unexpected decode document: %v", err)
+	}
+
+	if doc.OpenAPI == "" {
+		t.Error("Unexpected empty openapi version")
+	}
+	if _, ok := doc.Paths["/jobs/{id}"]["delete"]; !ok {
+		t.Errorf("Unexpected paths without DELETE /jobs/{id}: %v", doc.Paths)
+	}
+	if _, ok := doc.Paths["/archives"]["post"]; !ok {
+		t.Errorf("Unexpected paths without POST /archives: %v", doc.Paths)
+	}
+
+	job, ok := doc.Comps.Schemas["ApiJob"]
+	if !ok {
+		t.Fatalf("Unexpected schemas without ApiJob")
+	}
+	for _, name := range []string{"id", "state", "results", "artifacts"} {
+		if _, ok := job.Properties[name]; !ok {
+			t.Errorf("Unexpected ApiJob schema without property %s", name)
+		}
+	}
+	if typ := job.Properties["priority"]["type"]; typ != "string" {
+		t.Errorf("Unexpected type of priority got %v instead of string", typ)
+	}
+	if format := job.Properties["created"]["format"]; format != "date-time" {
+		t.Errorf("Unexpected format of created got %v instead of date-time", format)
+	}
+}
+
+func TestAPIPath(t *testing.T) {
+	path, params := apiPath("/jobs/{id:[0-9]+}")
+	if path != "/jobs/{id}" || len(params) != 1 || params[0] != "id" {
+		t.Fatalf("Unexpected api path got %s %v", path, params)
+	}
+	if id := operationID(http.MethodGet, path); id != "getJobsId" {
+		t.Fatalf("Unexpected operation id got %s", id)
+	}
+}
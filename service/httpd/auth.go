@@ -0,0 +1,101 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+type ctxTokenKey struct{}
+
+// tokenParam is the query parameter of the token secret for the pages
+// visited by browsers, which can not send the headers. The secret is kept
+// in the cookie of the same name for the following pages.
+const tokenParam = "token"
+
+// authorize returns a handler that requires a token granted the scope if
+// the authentication is enabled, the request is accounted to the token.
+func (web *web) authorize(scope entity.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !web.opts.EnabledWebAuth() {
+			next(w, r)
+			return
+		}
+
+		secret := bearer(r)
+		if secret == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="wayback"`)
+			writeAPIError(w, http.StatusUnauthorized, "token required")
+			return
+		}
+		if web.store == nil {
+			writeAPIError(w, http.StatusServiceUnavailable, "token storage not available")
+			return
+		}
+
+		token, err := web.store.AuthenticateToken(secret)
+		switch {
+		case err == storage.ErrTokenNotFound:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="wayback", error="invalid_token"`)
+			writeAPIError(w, http.StatusUnauthorized, "invalid token")
+			return
+		case err != nil:
+			logger.Error("authenticate token failed: %v", err)
+			writeAPIError(w, http.StatusInternalServerError, "authenticate token failed")
+			return
+		case !token.Allows(scope):
+			writeAPIError(w, http.StatusForbidden, "token is not granted the "+string(scope)+" scope")
+			return
+		}
+
+		if r.URL.Query().Get(tokenParam) == secret {
+			http.SetCookie(w, &http.Cookie{Name: tokenParam, Value: secret, Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+		}
+		if err = web.store.UseToken(token.ID, scope); err != nil {
+			logger.Warn("account usage of token %d failed: %v", token.ID, err)
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), ctxTokenKey{}, token)))
+	}
+}
+
+// bearer returns the token secret from the Authorization header or the
+// X-Api-Key header of the request, or from the token query parameter or
+// the cookie of the browsers.
+func bearer(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, secret, ok := strings.Cut(auth, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(secret)
+		}
+		return ""
+	}
+	if key := strings.TrimSpace(r.Header.Get("X-Api-Key")); key != "" {
+		return key
+	}
+	if secret := strings.TrimSpace(r.URL.Query().Get(tokenParam)); secret != "" {
+		return secret
+	}
+	if c, err := r.Cookie(tokenParam); err == nil {
+		return strings.TrimSpace(c.Value)
+	}
+	return ""
+}
+
+// requester returns the identity of the requester for rate limiting and
+// fair scheduling, which is the token if authenticated, otherwise the
+// client IP address.
+func requester(r *http.Request) string {
+	if token, ok := r.Context().Value(ctxTokenKey{}).(*entity.Token); ok {
+		return "token-" + strconv.FormatUint(token.ID, 10)
+	}
+	return clientIP(r)
+}
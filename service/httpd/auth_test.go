@@ -0,0 +1,211 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/metrics"
+	"github.com/wabarc/wayback/pooling"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestAuthorize(t *testing.T) {
+	os.Setenv("WAYBACK_WEB_AUTH", "true")
+	defer os.Unsetenv("WAYBACK_WEB_AUTH")
+	dir := t.TempDir()
+	t.Setenv("WAYBACK_STORAGE_DIR", dir)
+	t.Setenv("WAYBACK_ENABLE_LC", "true")
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	db, err := storage.Open(opts, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("Unexpected open storage: %v", err)
+	}
+	store := storage.NewStorage(nil, db)
+	defer store.Close()
+
+	archiver, token, _ := store.CreateToken("archiver", []entity.Scope{entity.ScopeArchive}, time.Time{})
+	admin, _, _ := store.CreateToken("admin", []entity.Scope{entity.ScopeAdmin}, time.Time{})
+	reader, _, _ := store.CreateToken("reader", []entity.Scope{entity.ScopeReadHistory}, time.Time{})
+
+	pool := pooling.New(context.Background(), pooling.Capacity(1), pooling.Timeout(time.Second))
+	web := newWeb(context.Background(), opts, pool, nil)
+	web.store = store
+	server := httptest.NewServer(web.handle())
+	defer server.Close()
+
+	do := func(method, path string, header http.Header, body string) *http.Response {
+		req, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected response: %v", err)
+		}
+		return resp
+	}
+	bearer := func(secret string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + secret}}
+	}
+
+	var tests = []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		status int
+	}{
+		{"without token", http.MethodGet, "/api/v1/jobs", nil, http.StatusUnauthorized},
+		{"invalid token", http.MethodGet, "/api/v1/jobs", bearer("foo"), http.StatusUnauthorized},
+		{"bearer token", http.MethodGet, "/api/v1/jobs", bearer(archiver), http.StatusOK},
+		{"api key header", http.MethodGet, "/api/v1/jobs", http.Header{"X-Api-Key": {archiver}}, http.StatusOK},
+		{"scope not granted", http.MethodGet, "/api/v1/history", bearer(archiver), http.StatusForbidden},
+		{"admin not granted", http.MethodGet, "/api/v1/tokens", bearer(archiver), http.StatusForbidden},
+		{"admin granted all", http.MethodGet, "/jobs", bearer(admin), http.StatusOK},
+		{"form without token", http.MethodPost, "/wayback", nil, http.StatusUnauthorized},
+		{"token query parameter", http.MethodGet, "/api/v1/jobs?token=" + archiver, nil, http.StatusOK},
+		{"token cookie", http.MethodGet, "/api/v1/jobs", http.Header{"Cookie": {"token=" + archiver}}, http.StatusOK},
+		{"history page scope not granted", http.MethodGet, "/history?token=" + archiver, nil, http.StatusForbidden},
+		{"replay is public", http.MethodGet, "/replay", nil, http.StatusOK},
+		{"capture is public", http.MethodGet, config.LC_SLUG + "/202501/example.warc", nil, http.StatusNotFound},
+		{"openapi is public", http.MethodGet, "/api/v1/openapi.json", nil, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := do(test.method, test.path, test.header, "")
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+			if test.status == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Fatal("Unexpected response without WWW-Authenticate header")
+			}
+		})
+	}
+
+	tokens, _ := store.Tokens()
+	if got := tokens[token.ID-1].Usage[entity.ScopeArchive]; got != 4 {
+		t.Fatalf("Unexpected usage of token got %d instead of 4", got)
+	}
+
+	// The token of the query parameter is kept in the cookie for browsers.
+	resp := do(http.MethodGet, "/history?token="+reader, nil, "")
+	defer resp.Body.Close()
+	if cookies := resp.Cookies(); len(cookies) != 1 || cookies[0].Name != tokenParam || cookies[0].Value != reader || !cookies[0].HttpOnly {
+		t.Fatalf("Unexpected cookies of token query parameter: %v", resp.Cookies())
+	}
+
+	// The links of local captures published to the channels resolve without tokens.
+	block := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>example</p>"
+	record := fmt.Sprintf("WARC/1.0\r\nWARC-Type: response\r\nWARC-Record-ID: <urn:uuid:1>\r\n"+
+		"WARC-Target-URI: https://example.com/\r\nWARC-Date: 2025-01-01T00:00:00Z\r\n"+
+		"Content-Type: application/http;msgtype=response\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", len(block), block)
+	warc := filepath.Join(dir, "202501", "example.warc")
+	if err = os.MkdirAll(filepath.Dir(warc), 0o755); err != nil {
+		t.Fatalf("Unexpected create directory: %v", err)
+	}
+	if err = os.WriteFile(warc, []byte(record), 0o600); err != nil {
+		t.Fatalf("Unexpected write file: %v", err)
+	}
+	captures, err := web.index.Add(warc)
+	if err != nil || len(captures) != 1 {
+		t.Fatalf("Unexpected index published capture: %v", err)
+	}
+	for _, link := range []string{
+		config.RP_SLUG + "/" + captures[0].Timestamp + "/" + captures[0].Original,
+		config.LC_SLUG + "/202501/example.warc",
+	} {
+		resp := do(http.MethodGet, link, nil, "")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Unexpected response code of published link %s got %d instead of %d", link, resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	// Manage tokens with the admin token.
+	resp = do(http.MethodPost, "/api/v1/tokens", bearer(admin), `{"name":"partner","scopes":["playback"]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Unexpected response code of create token got %d instead of %d", resp.StatusCode, http.StatusCreated)
+	}
+	var created createdToken
+	if err = json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Unexpected decode created token: %v", err)
+	}
+	if created.Secret == "" || created.Name != "partner" {
+		t.Fatalf("Unexpected created token: %#v", created)
+	}
+
+	resp = do(http.MethodPost, "/api/v1/tokens", bearer(admin), `{"name":"partner","scopes":["foo"]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Unexpected response code of create token with unknown scope got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodDelete, "/api/v1/tokens/"+strconv.FormatUint(created.ID, 10), bearer(admin), "")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Unexpected response code of revoke token got %d instead of %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if _, err = store.AuthenticateToken(created.Secret); err != storage.ErrTokenNotFound {
+		t.Fatalf("Unexpected revoked token authenticated: %v", err)
+	}
+}
+
+func TestRequester(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	if got := requester(r); got != "192.0.2.1" {
+		t.Fatalf("Unexpected requester got %s instead of 192.0.2.1", got)
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), ctxTokenKey{}, &entity.Token{ID: 3}))
+	if got := requester(r); got != "token-3" {
+		t.Fatalf("Unexpected requester got %s instead of token-3", got)
+	}
+}
+
+func TestOwns(t *testing.T) {
+	job := pooling.Job{Source: metrics.ServiceWeb + ":token-3"}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if owns(r, job) {
+		t.Fatal("Unexpected job owned by the client address")
+	}
+
+	var tests = []struct {
+		token *entity.Token
+		owned bool
+	}{
+		{&entity.Token{ID: 3, Scopes: []entity.Scope{entity.ScopeArchive}}, true},
+		{&entity.Token{ID: 4, Scopes: []entity.Scope{entity.ScopeArchive}}, false},
+		{&entity.Token{ID: 5, Scopes: []entity.Scope{entity.ScopeAdmin}}, true},
+	}
+	for _, test := range tests {
+		r := r.WithContext(context.WithValue(r.Context(), ctxTokenKey{}, test.token))
+		if got := owns(r, job); got != test.owned {
+			t.Errorf("Unexpected job owned by token %d got %t instead of %t", test.token.ID, got, test.owned)
+		}
+	}
+}
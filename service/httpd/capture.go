@@ -0,0 +1,52 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+)
+
+// contentTypes holds the content types of artifacts that are
+// not recognized by the mime package.
+var contentTypes = map[string]string{
+	".warc": "application/warc",
+	".har":  "application/json",
+	".htm":  "text/html; charset=utf-8",
+	".txt":  "text/plain; charset=utf-8",
+}
+
+// showCapture serves the artifacts produced by reduxer under the storage
+// directory, it is the destination of the local capture slot.
+func (web *web) showCapture() http.Handler {
+	fs := http.FileServer(http.Dir(web.opts.StorageDir()))
+
+	return http.StripPrefix(config.LC_SLUG, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Clean("/" + r.URL.Path)
+		logger.Debug("access local capture %s", name)
+
+		// Directory listing is not allowed.
+		if strings.HasSuffix(r.URL.Path, "/") || path.Ext(name) == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if ct, ok := contentTypes[path.Ext(name)]; ok {
+			w.Header().Set("Content-Type", ct)
+		}
+		w.Header().Set("Cache-Control", "max-age=2592000")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		// The PDF viewers of the browsers are not available in sandbox.
+		if path.Ext(name) != ".pdf" {
+			w.Header().Set("Content-Security-Policy", sandboxPolicy)
+		}
+
+		r.URL.Path = name
+		fs.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestShowCapture(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WAYBACK_STORAGE_DIR", dir)
+	t.Setenv("WAYBACK_ENABLE_LC", "true")
+
+	if err := os.MkdirAll(filepath.Join(dir, "202501"), 0o755); err != nil {
+		t.Fatalf("Unexpected create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "202501", "example.warc"), []byte("WARC/1.1"), 0o600); err != nil {
+		t.Fatalf("Unexpected write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "202501", "example.htm"), []byte("<script>alert(1)</script>"), 0o600); err != nil {
+		t.Fatalf("Unexpected write file: %v", err)
+	}
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	server := httptest.NewServer(newWeb(context.Background(), opts, nil, nil).handle())
+	defer server.Close()
+
+	var tests = []struct {
+		name   string
+		path   string
+		status int
+		ctype  string
+	}{
+		{"warc", "/202501/example.warc", http.StatusOK, "application/warc"},
+		{"single file", "/202501/example.htm", http.StatusOK, "text/html; charset=utf-8"},
+		{"directory", "/202501/", http.StatusNotFound, ""},
+		{"not exists", "/202501/missing.warc", http.StatusNotFound, ""},
+		{"traversal", "/../../etc/passwd", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + config.LC_SLUG + test.path)
+			if err != nil {
+				t.Fatalf("Unexpected response: %v", err)
+			}
+			defer resp.Body.Close()
+			io.Copy(io.Discard, resp.Body) // nolint:errcheck
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+			if test.ctype != "" && resp.Header.Get("Content-Type") != test.ctype {
+				t.Errorf("Unexpected content type got %s instead of %s", resp.Header.Get("Content-Type"), test.ctype)
+			}
+			if test.status == http.StatusOK && resp.Header.Get("Content-Security-Policy") != sandboxPolicy {
+				t.Errorf("Unexpected content security policy %q", resp.Header.Get("Content-Security-Policy"))
+			}
+		})
+	}
+}
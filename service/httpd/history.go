@@ -0,0 +1,311 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+const historyDate = "2006-01-02"
+
+// historyQuery parses the query parameters of the history into the filter
+// with the page number, the dates are in the form of 2006-01-02 or RFC 3339
+// and the until date is inclusive.
+func historyQuery(query url.Values) (f storage.WaybackFilter, page int, err error) {
+	page, perPage := 1, defPerPage
+	if v := query.Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return f, 0, fmt.Errorf("invalid page")
+		}
+	}
+	if v := query.Get("per_page"); v != "" {
+		if perPage, err = strconv.Atoi(v); err != nil || perPage < 1 || perPage > maxPerPage {
+			return f, 0, fmt.Errorf("invalid per_page")
+		}
+	}
+
+	f.Domain = strings.TrimSpace(query.Get("domain"))
+	f.Source = strings.TrimSpace(query.Get("url"))
+	f.Query = strings.TrimSpace(query.Get("q"))
+	if f.Slot = query.Get("slot"); f.Slot != "" {
+		if _, ok := config.LookupSlot(f.Slot); !ok {
+			return f, 0, fmt.Errorf("unknown slot: %s", f.Slot)
+		}
+	}
+	if f.Since, err = historyTime(query.Get("since"), false); err != nil {
+		return f, 0, fmt.Errorf("invalid since")
+	}
+	if f.Until, err = historyTime(query.Get("until"), true); err != nil {
+		return f, 0, fmt.Errorf("invalid until")
+	}
+	f.Limit, f.Offset = perPage, (page-1)*perPage
+
+	return f, page, nil
+}
+
+// historyTime parses the time of the history query, the next day of the
+// date is returned if end is true.
+func historyTime(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(historyDate, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// hasHistory reports whether the history is stored in the database.
+func (web *web) hasHistory() bool {
+	return web.store != nil && !web.opts.IsDefaultDatabaseURL()
+}
+
+// history responds the archiving history filtered by the domain, slot,
+// date range and source URL, the query parameters are the same as the API.
+func (web *web) history(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		http.Error(w, "History requires WAYBACK_DATABASE_URL", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, page, err := historyQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := web.store.CountWaybacks(r.Context(), filter)
+	if err != nil {
+		logger.Error("count history failed: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	items, err := web.store.Waybacks(r.Context(), filter)
+	if err != nil {
+		logger.Error("query history failed: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	data := historyPage{
+		Query: query,
+		Slots: config.RegisteredSlots(),
+		Items: items,
+		Page:  page,
+		Total: total,
+	}
+	if page > 1 {
+		data.Prev = historyPageURL(query, page-1)
+	}
+	if filter.Offset+len(items) < total {
+		data.Next = historyPageURL(query, page+1)
+	}
+	web.renderPage(w, historyTmpl, data)
+}
+
+// search responds the requests whose pages matched the full-text query given
+// by the q query parameter in order of relevance, the other query parameters
+// are the same as the history.
+func (web *web) search(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		http.Error(w, "Search requires WAYBACK_DATABASE_URL", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, page, err := historyQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data := searchPage{Query: query, Page: page}
+	if filter.Query != "" {
+		if data.Total, err = web.store.CountWaybacks(r.Context(), filter); err != nil {
+			logger.Error("count search hits failed: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if data.Items, err = web.store.Search(r.Context(), filter); err != nil {
+			logger.Error("search %q failed: %v", filter.Query, err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	if page > 1 {
+		data.Prev = pageURL("/search", query, page-1)
+	}
+	if filter.Offset+len(data.Items) < data.Total {
+		data.Next = pageURL("/search", query, page+1)
+	}
+	web.renderPage(w, searchTmpl, data)
+}
+
+// timeline responds all captures of the URL given by the url query parameter.
+func (web *web) timeline(w http.ResponseWriter, r *http.Request) {
+	if !web.hasHistory() {
+		http.Error(w, "History requires WAYBACK_DATABASE_URL", http.StatusServiceUnavailable)
+		return
+	}
+
+	source := strings.TrimSpace(r.URL.Query().Get("url"))
+	if source == "" {
+		http.Error(w, "url required", http.StatusBadRequest)
+		return
+	}
+	items, err := web.store.Timeline(r.Context(), source)
+	if err != nil {
+		logger.Error("query timeline of %s failed: %v", source, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	web.renderPage(w, timelineTmpl, struct {
+		URL   string
+		Days  []timelineDay
+		Total int
+	}{source, timelineDays(items), len(items)})
+}
+
+type historyPage struct {
+	Query url.Values
+	Slots []config.Slot
+	Items []entity.Wayback
+	Page  int
+	Total int
+	Prev  string
+	Next  string
+}
+
+type searchPage struct {
+	Query url.Values
+	Items []entity.SearchHit
+	Page  int
+	Total int
+	Prev  string
+	Next  string
+}
+
+type timelineDay struct {
+	Date  string
+	Items []entity.Wayback
+}
+
+// timelineDays groups the captures by the date of creation.
+func timelineDays(items []entity.Wayback) (days []timelineDay) {
+	for _, item := range items {
+		date := item.Created.Format(historyDate)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, timelineDay{Date: date})
+		}
+		d := &days[len(days)-1]
+		d.Items = append(d.Items, item)
+	}
+	return days
+}
+
+func historyPageURL(query url.Values, page int) string {
+	return pageURL("/history", query, page)
+}
+
+// pageURL returns the URL of the path with the query of the page number.
+func pageURL(path string, query url.Values, page int) string {
+	q := url.Values{}
+	for key, vals := range query {
+		q[key] = vals
+	}
+	q.Set("page", strconv.Itoa(page))
+	return path + "?" + q.Encode()
+}
+
+func timelineURL(source string) string {
+	return "/history/timeline?url=" + url.QueryEscape(source)
+}
+
+var historyFuncs = template.FuncMap{
+	"slotName":    config.SlotName,
+	"timelineURL": timelineURL,
+	"datetime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05 MST")
+	},
+	"elapsed": func(ms int64) string {
+		return (time.Duration(ms) * time.Millisecond).String()
+	},
+}
+
+const historyArchives = `{{ define "archives" }}{{ range .Archives }}<div>{{ slotName .Slot }}: ` +
+	`{{ if .Dest }}<a href="{{ .Dest }}">{{ .Dest }}</a>{{ else }}<span class="failed">{{ .Status }}{{ if .Error }} ({{ .Error }}){{ end }}</span>{{ end }}` +
+	` <small>{{ elapsed .Elapsed }}</small></div>{{ end }}{{ end }}`
+
+var (
+	historyTmpl = template.Must(template.New("history").Funcs(historyFuncs).Parse(historyArchives + `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>History - Wayback Archiver</title>` + pageStyle + `</head>
+<body><h1>History</h1>
+<p><a href="/search">Full-text search</a></p>
+<form method="get" action="/history">
+<input type="text" name="domain" placeholder="example.com" value="{{ .Query.Get "domain" }}">
+<select name="slot"><option value="">All slots</option>{{ $slot := .Query.Get "slot" }}{{ range .Slots }}
+<option value="{{ .Name }}"{{ if eq .Name $slot }} selected{{ end }}>{{ .Title }}</option>{{ end }}
+</select>
+<input type="date" name="since" value="{{ .Query.Get "since" }}">
+<input type="date" name="until" value="{{ .Query.Get "until" }}">
+<button type="submit">Search</button>
+</form>
+<p>{{ .Total }} request(s)</p>
+{{ if .Items }}<table>
+<tr><th>Time</th><th>URL</th><th>Archives</th></tr>{{ range .Items }}
+<tr><td>{{ datetime .Created }}</td><td><a href="{{ timelineURL .Source }}">{{ .Source }}</a></td><td>{{ template "archives" . }}</td></tr>{{ end }}
+</table>{{ else }}<p>No history.</p>{{ end }}
+<p>{{ if .Prev }}<a href="{{ .Prev }}">&laquo; Newer</a>{{ end }} {{ if .Next }}<a href="{{ .Next }}">Older &raquo;</a>{{ end }}</p>
+</body></html>`))
+
+	searchTmpl = template.Must(template.New("search").Funcs(historyFuncs).Parse(historyArchives + `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Search - Wayback Archiver</title>` + pageStyle + `</head>
+<body><h1>Search</h1>
+<form method="get" action="/search">
+<input type="search" name="q" placeholder="Words in the title, summary or content" value="{{ .Query.Get "q" }}" required>
+<input type="text" name="domain" placeholder="example.com" value="{{ .Query.Get "domain" }}">
+<button type="submit">Search</button>
+</form>
+{{ if .Query.Get "q" }}<p>{{ .Total }} result(s), <a href="/history">all history</a></p>
+{{ range .Items }}<div class="hit">
+<h2><a href="{{ timelineURL .Source }}">{{ if and .Page .Page.Title }}{{ .Page.Title }}{{ else }}{{ .Source }}{{ end }}</a></h2>
+<p><small>{{ .Source }}, {{ datetime .Created }}</small></p>
+{{ with .Page }}{{ if .Summary }}<p>{{ .Summary }}</p>{{ end }}{{ end }}
+{{ if .Snippet }}<p><small>{{ .Snippet }}</small></p>{{ end }}
+{{ template "archives" . }}
+</div>{{ else }}<p>No results.</p>{{ end }}
+<p>{{ if .Prev }}<a href="{{ .Prev }}">&laquo; Previous</a>{{ end }} {{ if .Next }}<a href="{{ .Next }}">Next &raquo;</a>{{ end }}</p>{{ end }}
+</body></html>`))
+
+	timelineTmpl = template.Must(template.New("timeline").Funcs(historyFuncs).Parse(historyArchives + `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{ .URL }} - Wayback Archiver</title>` + pageStyle + `</head>
+<body><h1>{{ .URL }}</h1>
+<p>{{ .Total }} capture(s), <a href="/history">all history</a></p>
+{{ range .Days }}<h2>{{ .Date }}</h2>
+<table>{{ range .Items }}
+<tr><td>{{ .Created.Format "15:04:05 MST" }}</td><td>{{ template "archives" . }}</td></tr>{{ end }}
+</table>{{ else }}<p>Not archived.</p>{{ end }}
+</body></html>`))
+)
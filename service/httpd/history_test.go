@@ -0,0 +1,176 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestHistoryQuery(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		query  string
+		filter storage.WaybackFilter
+		page   int
+		err    bool
+	}{
+		{
+			name:   "default",
+			query:  "",
+			filter: storage.WaybackFilter{Limit: defPerPage},
+			page:   1,
+		},
+		{
+			name:   "pagination",
+			query:  "page=3&per_page=10",
+			filter: storage.WaybackFilter{Limit: 10, Offset: 20},
+			page:   3,
+		},
+		{
+			name:   "filters",
+			query:  "domain=example.com&slot=ia&url=https://example.com/&q=+hello+&since=2025-03-01&until=2025-03-01",
+			filter: storage.WaybackFilter{Domain: "example.com", Slot: "ia", Source: "https://example.com/", Query: "hello", Since: day, Until: day.AddDate(0, 0, 1), Limit: defPerPage},
+			page:   1,
+		},
+		{
+			name:   "rfc3339",
+			query:  "until=2025-03-01T00:00:00Z",
+			filter: storage.WaybackFilter{Until: day, Limit: defPerPage},
+			page:   1,
+		},
+		{name: "invalid page", query: "page=0", err: true},
+		{name: "invalid per_page", query: "per_page=1000", err: true},
+		{name: "unknown slot", query: "slot=foo", err: true},
+		{name: "invalid since", query: "since=yesterday", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.query)
+			filter, page, err := historyQuery(query)
+			if test.err {
+				if err == nil {
+					t.Fatal("Unexpected history query, expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected history query error: %v", err)
+			}
+			if page != test.page {
+				t.Errorf("Unexpected page got %d instead of %d", page, test.page)
+			}
+			if filter != test.filter {
+				t.Errorf("Unexpected filter got %+v instead of %+v", filter, test.filter)
+			}
+		})
+	}
+}
+
+func TestHistoryWithoutDatabase(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	server := httptest.NewServer(newWeb(context.Background(), opts, nil, nil).handle())
+	defer server.Close()
+
+	for _, path := range []string{"/history", "/history/timeline?url=https://example.com", "/search?q=example"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected response: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected response code of %s got %d instead of %d", path, resp.StatusCode, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestHistoryTemplates(t *testing.T) {
+	created := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	items := []entity.Wayback{
+		{ID: 1, Source: "https://example.com/?a=1&b=2", Domain: "example.com", Created: created, Archives: []entity.Archive{
+			{Slot: config.SLOT_IA, Dest: "https://web.archive.org/web/https://example.com/", Status: "success"},
+			{Slot: config.SLOT_IS, Status: "failed", Error: "<timeout>"},
+		}},
+		{ID: 2, Source: "https://example.com/?a=1&b=2", Domain: "example.com", Created: created.Add(time.Hour)},
+		{ID: 3, Source: "https://example.com/?a=1&b=2", Domain: "example.com", Created: created.AddDate(0, 0, 1)},
+	}
+
+	var buf bytes.Buffer
+	query, _ := url.ParseQuery("domain=example.com&slot=ia")
+	data := historyPage{Query: query, Slots: config.RegisteredSlots(), Items: items, Page: 2, Total: 100, Prev: historyPageURL(query, 1)}
+	if err := historyTmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Unexpected execute history template error: %v", err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		`href="/history/timeline?url=https%3A%2F%2Fexample.com%2F%3Fa%3D1%26b%3D2"`,
+		`<option value="ia" selected>`,
+		`value="example.com"`,
+		`&lt;timeout&gt;`,
+		`href="/history?domain=example.com&amp;page=1&amp;slot=ia"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Unexpected history page, %q not found", want)
+		}
+	}
+
+	days := timelineDays(items)
+	if len(days) != 2 || len(days[0].Items) != 2 || days[1].Date != "2025-03-02" {
+		t.Fatalf("Unexpected timeline days: %+v", days)
+	}
+
+	buf.Reset()
+	err := timelineTmpl.Execute(&buf, struct {
+		URL   string
+		Days  []timelineDay
+		Total int
+	}{items[0].Source, days, len(items)})
+	if err != nil {
+		t.Fatalf("Unexpected execute timeline template error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "3 capture(s)") {
+		t.Errorf("Unexpected timeline page: %s", buf.String())
+	}
+
+	buf.Reset()
+	query, _ = url.ParseQuery("q=<example>&page=2")
+	hits := []entity.SearchHit{
+		{Wayback: items[0], Snippet: "An <example> domain"},
+		{Wayback: entity.Wayback{ID: 4, Source: "https://example.org/", Created: created, Page: &entity.Page{Title: "Example Org"}}},
+	}
+	err = searchTmpl.Execute(&buf, searchPage{Query: query, Items: hits, Page: 2, Total: 30, Prev: pageURL("/search", query, 1)})
+	if err != nil {
+		t.Fatalf("Unexpected execute search template error: %v", err)
+	}
+	page = buf.String()
+	for _, want := range []string{
+		`value="&lt;example&gt;"`,
+		`30 result(s)`,
+		`>https://example.com/?a=1&amp;b=2</a></h2>`,
+		`>Example Org</a></h2>`,
+		`An &lt;example&gt; domain`,
+		`href="/search?page=1&amp;q=%3Cexample%3E"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Unexpected search page, %q not found", want)
+		}
+	}
+}
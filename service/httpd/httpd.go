@@ -17,6 +17,7 @@ import (
 	"github.com/wabarc/wayback/errors"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
 )
@@ -31,12 +32,13 @@ var ErrServiceClosed = errors.New("httpd: Service closed")
 type Httpd struct {
 	ctx context.Context
 
-	pub    *publish.Publish
-	opts   *config.Options
-	pool   *pooling.Pool
-	store  *storage.Storage
-	tor    *tor.Tor
-	server *http.Server
+	pub     *publish.Publish
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	store   *storage.Storage
+	tor     *tor.Tor
+	server  *http.Server
 
 	sync.RWMutex
 }
@@ -48,11 +50,12 @@ func New(ctx context.Context, opts service.Options) (*Httpd, error) {
 	}
 
 	return &Httpd{
-		ctx:   ctx,
-		store: opts.Storage,
-		opts:  opts.Config,
-		pool:  opts.Pool,
-		pub:   opts.Publish,
+		ctx:     ctx,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -65,7 +68,13 @@ func (h *Httpd) Serve() error {
 	// Start tor with some defaults + elevated verbosity
 	logger.Info("starting and registering onion service, please wait a bit...")
 
-	handler := newWeb(h.ctx, h.opts, h.pool, h.pub).handle()
+	web := newWeb(h.ctx, h.opts, h.pool, h.pub)
+	web.limiter = h.limiter
+	web.store = h.store
+	if h.opts.EnabledReduxer() {
+		go web.index.Run(h.ctx, replayRefreshInterval)
+	}
+	handler := web.handle()
 	server := &http.Server{
 		ReadTimeout:  5 * time.Minute,
 		WriteTimeout: 5 * time.Minute,
@@ -0,0 +1,38 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/pooling"
+)
+
+// jobErrorStatus returns the status code of the errors of the pool jobs.
+func jobErrorStatus(err error) int {
+	switch err {
+	case pooling.ErrJobNotFound:
+		return http.StatusNotFound
+	case pooling.ErrJobFinished:
+		return http.StatusConflict
+	case pooling.ErrPoolNotExist:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Error("encode for response failed, %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data) // nolint:errcheck
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/metrics"
+	"github.com/wabarc/wayback/pooling"
+)
+
+func TestJobs(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	pool := pooling.New(context.Background(), pooling.Capacity(1), pooling.Timeout(time.Second))
+	// The jobs submitted by the test client are accounted to its address.
+	owner := metrics.ServiceWeb + ":127.0.0.1"
+	queued := pool.Put(pooling.Bucket{Source: owner})
+	cancelled := pool.Put(pooling.Bucket{Source: owner})
+	other := pool.Put(pooling.Bucket{Source: "telegram:1"})
+
+	server := httptest.NewServer(newWeb(context.Background(), opts, pool, nil).handle())
+	defer server.Close()
+
+	var tests = []struct {
+		name   string
+		method string
+		path   string
+		status int
+		state  entity.JobState
+	}{
+		{"cancel", http.MethodDelete, "/jobs/2", http.StatusOK, entity.JobCancelled},
+		{"cancel finished", http.MethodDelete, "/jobs/2", http.StatusConflict, ""},
+		{"show", http.MethodGet, "/jobs/1", http.StatusOK, entity.JobQueued},
+		{"not found", http.MethodGet, "/jobs/100", http.StatusNotFound, ""},
+		{"show others", http.MethodGet, "/jobs/3", http.StatusNotFound, ""},
+		{"cancel others", http.MethodDelete, "/jobs/3", http.StatusForbidden, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(test.method, server.URL+apiPrefix+test.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected response: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+			if test.state == "" {
+				return
+			}
+			var job apiJob
+			if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+				t.Fatalf("Unexpected decode job: %v", err)
+			}
+			if job.State != test.state {
+				t.Errorf("Unexpected job state got %s instead of %s", job.State, test.state)
+			}
+		})
+	}
+
+	resp, err := http.Get(server.URL + apiPrefix + "/jobs?state=queued")
+	if err != nil {
+		t.Fatalf("Unexpected response: %v", err)
+	}
+	defer resp.Body.Close()
+	var jobs []apiJob
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		t.Fatalf("Unexpected decode jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != queued || jobs[0].ID == cancelled || jobs[0].ID == other {
+		t.Errorf("Unexpected queued jobs: %v", jobs)
+	}
+}
+
+func TestJobsAliases(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	pool := pooling.New(context.Background(), pooling.Capacity(1), pooling.Timeout(time.Second))
+	pool.Put(pooling.Bucket{Source: metrics.ServiceWeb + ":127.0.0.1"})
+
+	server := httptest.NewServer(newWeb(context.Background(), opts, pool, nil).handle())
+	defer server.Close()
+
+	var tests = []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"list", http.MethodGet, "/jobs", http.StatusOK},
+		{"show", http.MethodGet, "/jobs/1", http.StatusOK},
+		{"not found", http.MethodGet, "/jobs/100", http.StatusNotFound},
+		{"cancel", http.MethodDelete, "/jobs/1", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(test.method, server.URL+test.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected response: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wabarc/wayback/version"
+)
+
+type object = map[string]interface{}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// openAPIDocument returns the OpenAPI 3 document of the given routes, the
+// schemas of request and response bodies are derived from their Go types.
+func openAPIDocument(routes []apiRoute) object {
+	schemas := object{}
+	errorRef := schemaOf(reflect.TypeOf(apiErrorResponse{}), schemas)
+
+	paths := object{}
+	for _, route := range routes {
+		path, names := apiPath(route.Path)
+
+		params := []object{}
+		for _, name := range names {
+			params = append(params, object{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   object{"type": "integer"},
+			})
+		}
+		for _, p := range route.Query {
+			params = append(params, object{
+				"name":        p.Name,
+				"in":          "query",
+				"description": p.Description,
+				"schema":      object{"type": p.Type},
+			})
+		}
+
+		success := object{"description": http.StatusText(route.Status)}
+		if route.Response != nil {
+			success["content"] = object{
+				"application/json": object{"schema": schemaOf(reflect.TypeOf(route.Response), schemas)},
+			}
+		}
+		op := object{
+			"summary":     route.Summary,
+			"operationId": operationID(route.Method, path),
+			"responses": object{
+				strconv.Itoa(route.Status): success,
+				"default": object{
+					"description": "Error",
+					"content": object{
+						"application/json": object{"schema": errorRef},
+					},
+				},
+			},
+		}
+		if route.Scope != "" {
+			op["description"] = "Requires a token granted the `" + string(route.Scope) + "` scope if the authentication is enabled."
+			op["security"] = []object{{"bearerAuth": []string{}}}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if route.Request != nil {
+			op["requestBody"] = object{
+				"required": true,
+				"content": object{
+					"application/json": object{"schema": schemaOf(reflect.TypeOf(route.Request), schemas)},
+				},
+			}
+		}
+
+		item, ok := paths[path].(object)
+		if !ok {
+			item = object{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "Wayback API",
+			"version": version.Version,
+		},
+		"servers": []object{{"url": apiPrefix}},
+		"paths":   paths,
+		"components": object{
+			"schemas": schemas,
+			"securitySchemes": object{
+				"bearerAuth": object{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// schemaOf returns the schema of the type, the schemas of named structs
+// are registered to the components and referenced.
+func schemaOf(t reflect.Type, schemas object) object {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	case t.Implements(textMarshalerType):
+		return object{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return object{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return object{"type": "string", "format": "byte"}
+		}
+		return object{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			// Register before walking the fields for recursive types.
+			schemas[name] = object{}
+			schemas[name] = structSchema(t, schemas)
+		}
+		return object{"$ref": "#/components/schemas/" + name}
+	}
+	return object{}
+}
+
+func structSchema(t reflect.Type, schemas object) object {
+	properties := object{}
+	required := []string{}
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				walk(field.Type)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = schemaOf(field.Type, schemas)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	walk(t)
+
+	schema := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaName returns the name of the schema of the struct type, e.g. Job
+// for pooling.Job and ApiJob for apiJob.
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// operationID returns the identifier of the operation, e.g. getJobsId for
+// GET /jobs/{id}.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.Split(path, "/") {
+		part = strings.Trim(part, "{}")
+		if part == "" {
+			continue
+		}
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+// apiPath converts the path template of mux to the one of OpenAPI,
+// e.g. /jobs/{id:[0-9]+} to /jobs/{id}, and returns the path parameters.
+func apiPath(tpl string) (path string, params []string) {
+	parts := strings.Split(tpl, "/")
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.Trim(part, "{}"), ":")
+		parts[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(parts, "/"), params
+}
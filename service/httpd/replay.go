@@ -0,0 +1,269 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/replay"
+)
+
+// hopHeaders holds the captured response headers that must not be replayed.
+var hopHeaders = []string{
+	"Connection",
+	"Content-Length",
+	"Content-Security-Policy",
+	"Keep-Alive",
+	"Set-Cookie",
+	"Strict-Transport-Security",
+	"Transfer-Encoding",
+}
+
+// sandboxPolicy is the Content-Security-Policy of the archived contents, it
+// runs them in a unique origin without scripts, so the archived pages cannot
+// act on the origin of the service.
+const sandboxPolicy = "sandbox"
+
+// replayRefreshInterval is the interval to pick up the WARC files written
+// since last refresh of the replay index, the storage directory is walked
+// in background once per interval.
+const replayRefreshInterval = 30 * time.Second
+
+// replay replays the captured webpages of the WARC files under the
+// storage directory, the paths are:
+//
+//	/replay                      list of captured URLs
+//	/replay/*/<url>              calendar of the captures of the URL
+//	/replay/<timestamp>/<url>    the capture closest to the timestamp
+func (web *web) replay(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.EscapedPath(), config.RP_SLUG), "/")
+	if rest == "" {
+		web.renderPage(w, replayIndexTmpl, web.index.Latest())
+		return
+	}
+
+	timestamp, target, ok := strings.Cut(rest, "/")
+	if !ok || target == "" {
+		http.NotFound(w, r)
+		return
+	}
+	target = originalURL(target, r.URL.RawQuery)
+	logger.Debug("access replay %s of %s", timestamp, target)
+
+	if timestamp == "*" {
+		web.renderPage(w, replayCalendarTmpl, struct {
+			URL      string
+			Captures []replay.Capture
+		}{target, web.index.Lookup(target)})
+		return
+	}
+
+	capture, ok := web.index.Closest(target, timestamp)
+	if !ok {
+		http.Error(w, "Not archived", http.StatusNotFound)
+		return
+	}
+	if capture.Timestamp != timestamp {
+		http.Redirect(w, r, replayURL(capture.Timestamp, capture.Original), http.StatusFound)
+		return
+	}
+
+	if err := web.replayCapture(w, capture); err != nil {
+		logger.Error("replay capture %s failed: %v", capture, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (web *web) replayCapture(w http.ResponseWriter, capture replay.Capture) error {
+	resp, err := web.index.Open(capture)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	base, err := url.Parse(capture.Original)
+	if err != nil {
+		return err
+	}
+	rw := &replay.Rewriter{Prefix: config.RP_SLUG, Timestamp: capture.Timestamp, Base: base}
+
+	header := w.Header()
+	for key, vals := range resp.Header {
+		header[key] = vals
+	}
+	for _, key := range hopHeaders {
+		header.Del(key)
+	}
+	if loc := resp.Header.Get("Location"); loc != "" {
+		header.Set("Location", rw.URL(loc))
+	}
+	header.Set("Memento-Datetime", capture.Time().Format(http.TimeFormat))
+	header.Set("Link", `<`+capture.Original+`>; rel="original"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", sandboxPolicy)
+
+	if capture.MIME != "text/html" && capture.MIME != "text/css" {
+		w.WriteHeader(resp.StatusCode)
+		_, err = io.Copy(w, resp.Body)
+		return err
+	}
+
+	// Text resources are rewritten and served uncompressed.
+	body := resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	header.Del("Content-Encoding")
+
+	var buf bytes.Buffer
+	if capture.MIME == "text/css" {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(rw.CSS(string(data)))
+	} else {
+		var banner bytes.Buffer
+		if err = replayBannerTmpl.Execute(&banner, capture); err != nil {
+			return err
+		}
+		if err = rw.HTML(&buf, body, banner.String()); err != nil {
+			return err
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (web *web) renderPage(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		logger.Error("render page template failed: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes()) // nolint:errcheck
+}
+
+// originalURL restores the URL from the path of replay request, the double
+// slashes of the scheme might be merged by path cleaning.
+func originalURL(target, query string) string {
+	for _, scheme := range []string{"http:/", "https:/"} {
+		if strings.HasPrefix(target, scheme) && !strings.HasPrefix(target, scheme+"/") {
+			target = scheme + "/" + strings.TrimPrefix(target, scheme)
+		}
+	}
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	if query != "" {
+		target += "?" + query
+	}
+	return target
+}
+
+func replayURL(timestamp, uri string) string {
+	return config.RP_SLUG + "/" + timestamp + "/" + uri
+}
+
+// calendar groups the captures by year, month and day.
+func calendar(captures []replay.Capture) (years []captureYear) {
+	for _, c := range captures {
+		t := c.Time()
+		if len(years) == 0 || years[len(years)-1].Year != t.Year() {
+			years = append(years, captureYear{Year: t.Year()})
+		}
+		y := &years[len(years)-1]
+		if len(y.Months) == 0 || y.Months[len(y.Months)-1].Month != t.Month() {
+			y.Months = append(y.Months, captureMonth{Month: t.Month()})
+		}
+		m := &y.Months[len(y.Months)-1]
+		if len(m.Days) == 0 || m.Days[len(m.Days)-1].Day != t.Day() {
+			m.Days = append(m.Days, captureDay{Day: t.Day()})
+		}
+		d := &m.Days[len(m.Days)-1]
+		d.Captures = append(d.Captures, c)
+	}
+	return years
+}
+
+type captureYear struct {
+	Year   int
+	Months []captureMonth
+}
+
+type captureMonth struct {
+	Month time.Month
+	Days  []captureDay
+}
+
+type captureDay struct {
+	Day      int
+	Captures []replay.Capture
+}
+
+var replayFuncs = template.FuncMap{
+	"replayURL": replayURL,
+	"calendar":  calendar,
+	"calendarURL": func(uri string) string {
+		return replayURL("*", uri)
+	},
+	"datetime": func(c replay.Capture) string {
+		return c.Time().Format("2006-01-02 15:04:05 MST")
+	},
+}
+
+// pageStyle is the shared style of the replay and history pages.
+const pageStyle = `<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;color:#333;background:#f7f7f7;margin:2em}
+a{color:#0366d6;text-decoration:none}
+li{margin:.3em 0;word-break:break-all}
+.day{display:inline-block;margin:.2em .5em .2em 0}
+table{border-collapse:collapse;width:100%;background:#fff}
+th,td{padding:.4em .6em;border-bottom:1px solid #e1e4e8;text-align:left;vertical-align:top;word-break:break-all}
+form input,form select{margin:0 .5em .5em 0}
+.failed{color:#cb2431}
+</style>`
+
+var (
+	replayIndexTmpl = template.Must(template.New("replay-index").Funcs(replayFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Replay - Wayback Archiver</title>` + pageStyle + `</head>
+<body><h1>Captures</h1>
+{{ if . }}<ul>{{ range . }}
+<li><a href="{{ calendarURL .Original }}">{{ .Original }}</a> <small>latest <a href="{{ replayURL .Timestamp .Original }}">{{ datetime . }}</a></small></li>{{ end }}
+</ul>{{ else }}<p>No captures.</p>{{ end }}
+</body></html>`))
+
+	replayCalendarTmpl = template.Must(template.New("replay-calendar").Funcs(replayFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{ .URL }} - Wayback Archiver</title>` + pageStyle + `</head>
+<body><h1>{{ .URL }}</h1>
+<p>{{ len .Captures }} capture(s), <a href="` + config.RP_SLUG + `">all captures</a></p>
+{{ range calendar .Captures }}<h2>{{ .Year }}</h2>
+{{ range .Months }}<h3>{{ .Month }}</h3><ul>
+{{ range .Days }}<li>{{ .Day }}: {{ range .Captures }}<a class="day" href="{{ replayURL .Timestamp .Original }}">{{ .Time.Format "15:04:05" }}</a>{{ end }}</li>
+{{ end }}</ul>{{ end }}{{ end }}
+</body></html>`))
+
+	replayBannerTmpl = template.Must(template.New("replay-banner").Funcs(replayFuncs).Parse(`<div id="wayback-replay-banner" ` +
+		`style="position:relative;z-index:2147483647;padding:6px 12px;font:13px/1.5 sans-serif;color:#333;background:#fffbe6;border-bottom:1px solid #e6d9a3">` +
+		`Captured {{ datetime . }} from <a href="{{ .Original }}">{{ .Original }}</a> &middot; ` +
+		`<a href="{{ calendarURL .Original }}">all captures</a></div>`))
+)
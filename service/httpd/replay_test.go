@@ -0,0 +1,81 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WAYBACK_STORAGE_DIR", dir)
+
+	body := `<html><body><a href="/about">About</a></body></html>`
+	block := fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+	record := fmt.Sprintf("WARC/1.0\r\nWARC-Type: response\r\nWARC-Record-ID: <urn:uuid:1>\r\n"+
+		"WARC-Target-URI: https://example.com/\r\nWARC-Date: 2025-01-01T00:00:00Z\r\n"+
+		"Content-Type: application/http;msgtype=response\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", len(block), block)
+	if err := os.MkdirAll(filepath.Join(dir, "202501"), 0o755); err != nil {
+		t.Fatalf("Unexpected create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "202501", "example.warc"), []byte(record), 0o600); err != nil {
+		t.Fatalf("Unexpected write file: %v", err)
+	}
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	web := newWeb(context.Background(), opts, nil, nil)
+	if err := web.index.Refresh(); err != nil {
+		t.Fatalf("Unexpected refresh replay index: %v", err)
+	}
+	server := httptest.NewServer(web.handle())
+	defer server.Close()
+
+	var tests = []struct {
+		name     string
+		path     string
+		status   int
+		contains string
+	}{
+		{"index", "", http.StatusOK, `/replay/*/https://example.com/`},
+		{"calendar", "/*/https://example.com/", http.StatusOK, `/replay/20250101000000/https://example.com/`},
+		{"replay", "/20250101000000/https://example.com/", http.StatusOK, `href="/replay/20250101000000/https://example.com/about"`},
+		{"closest", "/2024/https://example.com/", http.StatusOK, `wayback-replay-banner`},
+		{"not archived", "/2025/https://example.org/", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + config.RP_SLUG + test.path)
+			if err != nil {
+				t.Fatalf("Unexpected response: %v", err)
+			}
+			if test.name == "replay" && resp.Header.Get("Content-Security-Policy") != sandboxPolicy {
+				t.Errorf("Unexpected content security policy %q", resp.Header.Get("Content-Security-Policy"))
+			}
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != test.status {
+				t.Fatalf("Unexpected response code got %d instead of %d", resp.StatusCode, test.status)
+			}
+			if !strings.Contains(string(data), test.contains) {
+				t.Errorf("Unexpected response body, %s not found in %s", test.contains, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+// apiToken represents an API token without its secret.
+type apiToken struct {
+	ID       uint64                  `json:"id"`
+	Name     string                  `json:"name"`
+	Scopes   []entity.Scope          `json:"scopes"`
+	Usage    map[entity.Scope]uint64 `json:"usage"`
+	Created  time.Time               `json:"created"`
+	Expires  *time.Time              `json:"expires,omitempty"`
+	LastUsed *time.Time              `json:"last_used,omitempty"`
+}
+
+// tokenRequest is the request body to create a token, a zero expires
+// means the token never expires.
+type tokenRequest struct {
+	Name    string         `json:"name"`
+	Scopes  []entity.Scope `json:"scopes"`
+	Expires time.Time      `json:"expires,omitempty"`
+}
+
+// createdToken is the response of the created token, the secret is only
+// responded once.
+type createdToken struct {
+	apiToken
+
+	Secret string `json:"secret"`
+}
+
+func newAPIToken(t *entity.Token) apiToken {
+	token := apiToken{
+		ID:      t.ID,
+		Name:    t.Name,
+		Scopes:  t.Scopes,
+		Usage:   t.Usage,
+		Created: t.Created,
+	}
+	if !t.Expires.IsZero() {
+		token.Expires = &t.Expires
+	}
+	if !t.LastUsed.IsZero() {
+		token.LastUsed = &t.LastUsed
+	}
+	return token
+}
+
+func (web *web) apiListTokens(w http.ResponseWriter, r *http.Request) {
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "token storage not available")
+		return
+	}
+
+	tokens, err := web.store.Tokens()
+	if err != nil {
+		logger.Error("api: query tokens failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "query tokens failed")
+		return
+	}
+	items := []apiToken{}
+	for _, t := range tokens {
+		items = append(items, newAPIToken(t))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (web *web) apiCreateToken(w http.ResponseWriter, r *http.Request) {
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "token storage not available")
+		return
+	}
+
+	var req tokenRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeAPIError(w, http.StatusBadRequest, "name required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "scopes required")
+		return
+	}
+	for _, scope := range req.Scopes {
+		if _, err := entity.ParseScope(string(scope)); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	secret, token, err := web.store.CreateToken(req.Name, req.Scopes, req.Expires)
+	if err != nil {
+		logger.Error("api: create token failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "create token failed")
+		return
+	}
+	logger.Info("api: token %d created", token.ID)
+
+	writeJSON(w, http.StatusCreated, createdToken{apiToken: newAPIToken(token), Secret: secret})
+}
+
+func (web *web) apiDeleteToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(routeParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "token storage not available")
+		return
+	}
+
+	switch err = web.store.DeleteToken(id); err {
+	case nil:
+		logger.Info("api: token %d revoked", id)
+		w.WriteHeader(http.StatusNoContent)
+	case storage.ErrTokenNotFound:
+		writeAPIError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error("api: revoke token failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "revoke token failed")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/metrics"
+	"github.com/wabarc/wayback/schedule"
+	"github.com/wabarc/wayback/service"
+	"github.com/wabarc/wayback/storage"
+)
+
+// watchRequest is the request body to create a watch, the schedule is a
+// cron expression or descriptor and defaults to service.DefaultSchedule.
+// The new items of the URL are archived if it is a feed.
+type watchRequest struct {
+	URL      string `json:"url"`
+	Schedule string `json:"schedule,omitempty"`
+	Feed     bool   `json:"feed,omitempty"`
+}
+
+func (web *web) apiListWatches(w http.ResponseWriter, r *http.Request) {
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "watch storage not available")
+		return
+	}
+
+	watches, err := web.store.Watches()
+	if err != nil {
+		logger.Error("api: query watches failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "query watches failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, watches)
+}
+
+func (web *web) apiCreateWatch(w http.ResponseWriter, r *http.Request) {
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "watch storage not available")
+		return
+	}
+
+	var req watchRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Schedule == "" {
+		req.Schedule = service.DefaultSchedule
+	}
+
+	owner := metrics.ServiceWeb
+	if token, ok := r.Context().Value(ctxTokenKey{}).(*entity.Token); ok {
+		owner += ":" + token.Name
+	}
+	watch, err := schedule.NewWatch(req.URL, req.Schedule, owner)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	watch.Feed = req.Feed
+	if err = web.store.CreateWatch(watch); err != nil {
+		logger.Error("api: create watch failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "create watch failed")
+		return
+	}
+	logger.Info("api: watch %d created", watch.ID)
+
+	writeJSON(w, http.StatusCreated, watch)
+}
+
+func (web *web) apiDeleteWatch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(routeParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid watch id")
+		return
+	}
+	if web.store == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "watch storage not available")
+		return
+	}
+
+	switch err = web.store.DeleteWatch(id); err {
+	case nil:
+		logger.Info("api: watch %d deleted", id)
+		w.WriteHeader(http.StatusNoContent)
+	case storage.ErrWatchNotFound:
+		writeAPIError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error("api: delete watch failed: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "delete watch failed")
+	}
+}
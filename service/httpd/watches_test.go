@@ -0,0 +1,77 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package httpd // import "github.com/wabarc/wayback/service/httpd"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestWatches(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	db, err := storage.Open(opts, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("Unexpected open storage: %v", err)
+	}
+	store := storage.NewStorage(nil, db)
+	defer store.Close()
+
+	web := newWeb(context.Background(), opts, nil, nil)
+	web.store = store
+	server := httptest.NewServer(web.handle())
+	defer server.Close()
+
+	do := func(method, path, body string, status int) *http.Response {
+		req, _ := http.NewRequest(method, server.URL+apiPrefix+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected response: %v", err)
+		}
+		if resp.StatusCode != status {
+			t.Fatalf("Unexpected response code of %s %s got %d instead of %d", method, path, resp.StatusCode, status)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/watches", `{"url":"https://example.com/","schedule":"0 8 * * *"}`, http.StatusCreated)
+	defer resp.Body.Close()
+	var created entity.Watch
+	if err = json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Unexpected decode created watch: %v", err)
+	}
+	if created.ID == 0 || created.Owner != "web" || created.NextRun.IsZero() {
+		t.Fatalf("Unexpected created watch: %#v", created)
+	}
+
+	do(http.MethodPost, "/watches", `{"url":"https://example.com/feed.xml","feed":true}`, http.StatusCreated).Body.Close()
+	do(http.MethodPost, "/watches", `{"url":"https://example.com/","schedule":"every day"}`, http.StatusBadRequest).Body.Close()
+	do(http.MethodPost, "/watches", `{"url":"example"}`, http.StatusBadRequest).Body.Close()
+
+	resp = do(http.MethodGet, "/watches", "", http.StatusOK)
+	defer resp.Body.Close()
+	var watches []entity.Watch
+	if err = json.NewDecoder(resp.Body).Decode(&watches); err != nil {
+		t.Fatalf("Unexpected decode watches: %v", err)
+	}
+	if len(watches) != 2 || watches[1].Schedule != "@daily" || !watches[1].Feed {
+		t.Fatalf("Unexpected watches: %#v", watches)
+	}
+
+	do(http.MethodDelete, "/watches/"+strconv.FormatUint(created.ID, 10), "", http.StatusNoContent).Body.Close()
+	do(http.MethodDelete, "/watches/"+strconv.FormatUint(created.ID, 10), "", http.StatusNotFound).Body.Close()
+}
@@ -7,9 +7,13 @@ package httpd // import "github.com/wabarc/wayback/service/httpd"
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
 	"mime"
+	"net"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -18,12 +22,16 @@ import (
 	"github.com/wabarc/logger"
 	"github.com/wabarc/wayback"
 	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
 	"github.com/wabarc/wayback/errors"
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/replay"
 	"github.com/wabarc/wayback/service"
+	"github.com/wabarc/wayback/storage"
 	"github.com/wabarc/wayback/template"
 	"github.com/wabarc/wayback/version"
 )
@@ -36,6 +44,10 @@ type web struct {
 	pool     *pooling.Pool
 	router   *mux.Router
 	template *template.Template
+	index    *replay.Index
+	limiter  *ratelimit.Limiter
+	store    *storage.Storage
+	results  results
 }
 
 func newWeb(ctx context.Context, opts *config.Options, pool *pooling.Pool, pub *publish.Publish) *web {
@@ -47,6 +59,7 @@ func newWeb(ctx context.Context, opts *config.Options, pool *pooling.Pool, pub *
 		pool:     pool,
 		router:   router,
 		template: template.New(router, opts),
+		index:    replay.IndexOf(opts.StorageDir()),
 	}
 	if err := web.template.ParseTemplates(); err != nil {
 		logger.Fatal("unable to parse templates: %v", err)
@@ -65,20 +78,38 @@ func (web *web) handle() http.Handler {
 	web.router.HandleFunc("/manifest.json", web.showWebManifest).Name("manifest").Methods(http.MethodGet)
 	web.router.HandleFunc("/offline.html", web.showOfflinePage).Methods(http.MethodGet)
 
-	web.router.HandleFunc("/wayback", func(w http.ResponseWriter, r *http.Request) {
+	web.router.HandleFunc("/wayback", web.authorize(entity.ScopeArchive, func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(web.ctx, web.opts.WaybackTimeout())
 		defer cancel()
 
 		if err := web.process(ctx, w, r); err != nil {
 			logger.Error("httpd: process retrying: %v", err)
 		}
-	}).Methods(http.MethodPost)
+	})).Methods(http.MethodPost)
 
-	web.router.HandleFunc("/playback", web.playback).Methods(http.MethodPost)
+	web.router.HandleFunc("/playback", web.authorize(entity.ScopePlayback, web.playback)).Methods(http.MethodPost)
 
-	web.router.HandleFunc("/healthcheck", func(w http.ResponseWriter, r *http.Request) {
-		w.Write(helper.String2Byte("OK")) // nolint:errcheck
-	}).Name("healthcheck")
+	web.handleAPI()
+
+	// The unversioned endpoints of jobs are aliases of the API.
+	web.router.HandleFunc("/jobs", web.authorize(entity.ScopeArchive, web.apiListJobs)).Methods(http.MethodGet)
+	web.router.HandleFunc("/jobs/{id:[0-9]+}", web.authorize(entity.ScopeArchive, web.apiShowJob)).Methods(http.MethodGet)
+	web.router.HandleFunc("/jobs/{id:[0-9]+}", web.authorize(entity.ScopeArchive, web.apiCancelJob)).Methods(http.MethodDelete)
+
+	web.router.HandleFunc("/history", web.authorize(entity.ScopeReadHistory, web.history)).Methods(http.MethodGet)
+	web.router.HandleFunc("/history/timeline", web.authorize(entity.ScopeReadHistory, web.timeline)).Methods(http.MethodGet)
+	web.router.HandleFunc("/search", web.authorize(entity.ScopeReadHistory, web.search)).Methods(http.MethodGet)
+
+	// The artifacts and the replayed pages are public, so that the links of
+	// local captures published to the channels resolve for the recipients.
+	if web.opts.EnabledReduxer() && web.opts.Slots()[config.SLOT_LC] {
+		web.router.PathPrefix(config.LC_SLUG + "/").Handler(web.showCapture()).Methods(http.MethodGet)
+	}
+	if web.opts.EnabledReduxer() {
+		web.router.PathPrefix(config.RP_SLUG).HandlerFunc(web.replay).Methods(http.MethodGet)
+	}
+
+	web.router.HandleFunc("/healthcheck", web.healthcheck).Name("healthcheck")
 
 	web.router.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		w.Write(helper.String2Byte(version.Version)) // nolint:errcheck
@@ -100,6 +131,18 @@ func (web *web) handle() http.Handler {
 	return web.router
 }
 
+// healthcheck reports the service is alive, followed by the circuit
+// breaker states of the slots, one slot per line.
+func (web *web) healthcheck(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	b.WriteString("OK")
+	for _, brk := range wayback.Breakers() {
+		fmt.Fprintf(&b, "\n%s: %s", brk.Slot, brk.State)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(helper.String2Byte(b.String())) // nolint:errcheck
+}
+
 func (web *web) home(w http.ResponseWriter, r *http.Request) {
 	logger.Debug("access home")
 	w.Header().Set("Cache-Control", "max-age=2592000")
@@ -206,7 +249,6 @@ func (web *web) showJavascript(w http.ResponseWriter, r *http.Request) {
 }
 
 func (web *web) process(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	// TODO: rate limit https://pkg.go.dev/golang.org/x/time/rate
 	logger.Info("process request start...")
 	metrics.IncrementWayback(metrics.ServiceWeb, metrics.StatusRequest)
 
@@ -215,6 +257,12 @@ func (web *web) process(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		return errors.New("httpd: request method no specific.")
 	}
 
+	if delay := web.limiter.Reserve(metrics.ServiceWeb, requester(r)); delay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		http.Error(w, service.MsgRateLimited, http.StatusTooManyRequests)
+		return errors.New("httpd: too many requests")
+	}
+
 	if err := r.ParseForm(); err != nil {
 		logger.Error("parse form error, %v", err)
 		http.Redirect(w, r, "/", http.StatusNotModified)
@@ -266,7 +314,15 @@ func (web *web) process(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		return nil
 	}
 
-	return service.Wayback(ctx, web.opts, urls, do)
+	if force, _ := strconv.ParseBool(r.PostFormValue("force")); force || service.Forced(text) {
+		ctx = service.Force(ctx)
+	}
+	if artifacts := r.PostFormValue("artifacts"); artifacts != "" {
+		ctx = service.WithArtifacts(ctx, artifacts)
+	} else {
+		ctx = service.WithArtifacts(ctx, service.Artifacts(text))
+	}
+	return service.Wayback(ctx, web.opts, web.store, urls, do)
 }
 
 func (web *web) playback(w http.ResponseWriter, r *http.Request) {
@@ -328,11 +384,18 @@ func (web *web) playback(w http.ResponseWriter, r *http.Request) {
 func transform(cols []wayback.Collect) template.Collector {
 	collects := []template.Collect{}
 	for _, col := range cols {
-		collects = append(collects, template.Collect{
-			Slot: col.Arc,
-			Src:  col.Src,
-			Dst:  col.Dst,
-		})
+		collect := template.Collect{
+			Slot:     col.Arc,
+			Src:      col.Src,
+			Dst:      col.Dst,
+			Status:   col.Status.String(),
+			Elapsed:  col.Elapsed.Milliseconds(),
+			Attempts: col.Attempts,
+		}
+		if col.Err != nil {
+			collect.Error = col.Err.Error()
+		}
+		collects = append(collects, collect)
 	}
 	return collects
 }
@@ -341,3 +404,23 @@ func routeParam(r *http.Request, param string) string {
 	vars := mux.Vars(r)
 	return vars[param]
 }
+
+// clientIP returns the IP address of the client, the forwarded address is
+// only trusted from a reverse proxy on the loopback interface.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		addrs := strings.Split(xff, ",")
+		return strings.TrimSpace(addrs[len(addrs)-1])
+	}
+	if xri := r.Header.Get("X-Real-Ip"); xri != "" {
+		return strings.TrimSpace(xri)
+	}
+	return host
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 )
@@ -200,3 +202,62 @@ func TestProcessContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessRateLimit(t *testing.T) {
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	web := newWeb(context.Background(), opts, nil, nil)
+	web.limiter = ratelimit.New(1, 0, 1)
+
+	var tests = []struct {
+		addr   string
+		status int
+	}{
+		{"192.0.2.1:1234", http.StatusFound},
+		{"192.0.2.1:5678", http.StatusTooManyRequests},
+		{"192.0.2.2:1234", http.StatusFound},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/wayback", strings.NewReader("text="))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.RemoteAddr = test.addr
+		w := httptest.NewRecorder()
+		web.process(context.Background(), w, r) // nolint:errcheck
+
+		if w.Code != test.status {
+			t.Fatalf("Unexpected response code from %s got %d instead of %d", test.addr, w.Code, test.status)
+		}
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "60" {
+			t.Errorf("Unexpected Retry-After header got %q instead of 60", w.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	var tests = []struct {
+		addr   string
+		header http.Header
+		expect string
+	}{
+		{"192.0.2.1:1234", nil, "192.0.2.1"},
+		{"192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "192.0.2.1"},
+		{"127.0.0.1:1234", nil, "127.0.0.1"},
+		{"127.0.0.1:1234", http.Header{"X-Forwarded-For": {"203.0.113.1, 198.51.100.1"}}, "198.51.100.1"},
+		{"[::1]:1234", http.Header{"X-Real-Ip": {"198.51.100.2"}}, "198.51.100.2"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/wayback", nil)
+		r.RemoteAddr = test.addr
+		for k, v := range test.header {
+			r.Header[k] = v
+		}
+		if got := clientIP(r); got != test.expect {
+			t.Errorf("Unexpected client IP of %s got %s instead of %s", test.addr, got, test.expect)
+		}
+	}
+}
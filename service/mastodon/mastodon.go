@@ -19,6 +19,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -37,6 +38,7 @@ type Mastodon struct {
 	ctx        context.Context
 	opts       *config.Options
 	pool       *pooling.Pool
+	limiter    *ratelimit.Limiter
 	client     *mastodon.Client
 	store      *storage.Storage
 	pub        *publish.Publish
@@ -63,12 +65,13 @@ func New(ctx context.Context, opts service.Options) (*Mastodon, error) {
 		AccessToken:  opts.Config.MastodonAccessToken(),
 	})
 	return &Mastodon{
-		ctx:    ctx,
-		client: client,
-		store:  opts.Storage,
-		opts:   opts.Config,
-		pool:   opts.Pool,
-		pub:    opts.Publish,
+		ctx:     ctx,
+		client:  client,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -136,7 +139,15 @@ func (m *Mastodon) Serve() error {
 						m.archiving[n.Status.ID] = true
 						m.Unlock()
 						metrics.IncrementWayback(metrics.ServiceMastodon, metrics.StatusRequest)
+						if !m.limiter.Allow(metrics.ServiceMastodon, n.Account.Acct) {
+							m.ToMastodon(m.ctx, service.MsgRateLimited, string(n.Status.ID))
+							if err := m.client.DismissNotification(m.ctx, n.ID); err != nil {
+								logger.Warn("dismiss notification failed: %v", err)
+							}
+							return
+						}
 						bucket := pooling.Bucket{
+							Source: metrics.ServiceMastodon + ":" + n.Account.Acct,
 							Request: func(ctx context.Context) error {
 								m.Lock()
 								m.processing[n.Status.ID] += 1
@@ -183,6 +194,10 @@ func (m *Mastodon) process(ctx context.Context, id mastodon.ID, status *mastodon
 		logger.Warn("no status or conversation")
 		return errors.New("Mastodon: no status or conversation")
 	}
+	// The requester of the status command is the author of the mention,
+	// rather than the one of the status it replies to.
+	source := metrics.ServiceMastodon + ":" + status.Account.Acct
+	asked := service.AskedStatus(textContent(status.Content))
 	if inReplyToID, ok := status.InReplyToID.(string); ok {
 		logger.Debug("inReplyToID %s", inReplyToID)
 		if status, err = m.client.GetStatus(ctx, mastodon.ID(inReplyToID)); err != nil {
@@ -201,6 +216,11 @@ func (m *Mastodon) process(ctx context.Context, id mastodon.ID, status *mastodon
 		}
 	}()
 
+	if asked {
+		m.ToMastodon(ctx, service.JobStatus(m.pool, source), string(status.ID))
+		return nil
+	}
+
 	// Process playback request if message has prefix `/playback`
 	if strings.Contains(text, config.PB_SLUG) {
 		return m.playback(status)
@@ -224,7 +244,11 @@ func (m *Mastodon) process(ctx context.Context, id mastodon.ID, status *mastodon
 		return nil
 	}
 
-	return service.Wayback(ctx, m.opts, urls, do)
+	if service.Forced(text) {
+		ctx = service.Force(ctx)
+	}
+	ctx = service.WithArtifacts(ctx, service.Artifacts(text))
+	return service.Wayback(ctx, m.opts, m.store, urls, do)
 }
 
 func (m *Mastodon) playback(status *mastodon.Status) error {
@@ -12,8 +12,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/mattn/go-mastodon"
 	"github.com/wabarc/helper"
 	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
 	"github.com/wabarc/wayback/service"
@@ -167,3 +169,50 @@ func TestPlayback(t *testing.T) {
 	}
 	pool.Close()
 }
+
+func TestStatus(t *testing.T) {
+	_, mux, server := helper.MockServer()
+	defer server.Close()
+
+	var reply string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.ParseForm() != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/statuses":
+			reply = r.FormValue("status")
+			fmt.Fprintln(w, `{}`)
+		case "/api/v1/notifications/dismiss":
+			fmt.Fprintln(w, `{}`)
+		}
+	})
+
+	os.Setenv("WAYBACK_MASTODON_SERVER", server.URL)
+	os.Setenv("WAYBACK_MASTODON_KEY", "foo")
+	os.Setenv("WAYBACK_MASTODON_SECRET", "bar")
+	os.Setenv("WAYBACK_MASTODON_TOKEN", "zoo")
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	opts.EnableServices(config.ServiceMastodon.String())
+
+	ctx := context.Background()
+	pool := pooling.New(ctx, pooling.Capacity(1))
+	id := pool.Put(pooling.Bucket{Source: metrics.ServiceMastodon + ":alice"})
+	pool.Put(pooling.Bucket{Source: metrics.ServiceMastodon + ":bob"})
+
+	o := service.ParseOptions(service.Config(opts), service.Storage(&storage.Storage{}), service.Pool(pool))
+	m, _ := New(ctx, o)
+	status := &mastodon.Status{ID: "1", Content: "<p>@wayback /status</p>", Account: mastodon.Account{Acct: "alice"}}
+	if err := m.process(ctx, "1", status); err != nil {
+		t.Fatalf("Unexpected process status command: %v", err)
+	}
+	if !strings.Contains(reply, fmt.Sprintf("#%d queued", id)) || strings.Contains(reply, "#2") {
+		t.Errorf("Unexpected status reply: %s", reply)
+	}
+}
@@ -6,6 +6,7 @@ package matrix // import "github.com/wabarc/wayback/service/matrix"
 
 import (
 	"context"
+	"html"
 	"strings"
 	"sync"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -34,12 +36,13 @@ var ErrServiceClosed = errors.New("matrix: Service closed")
 
 // Matrix represents a Matrix service in the application
 type Matrix struct {
-	ctx    context.Context
-	opts   *config.Options
-	pool   *pooling.Pool
-	client *matrix.Client
-	store  *storage.Storage
-	pub    *publish.Publish
+	ctx     context.Context
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	client  *matrix.Client
+	store   *storage.Storage
+	pub     *publish.Publish
 	sync.RWMutex
 }
 
@@ -67,12 +70,13 @@ func New(ctx context.Context, opts service.Options) (*Matrix, error) {
 	}
 
 	return &Matrix{
-		ctx:    ctx,
-		client: client,
-		store:  opts.Storage,
-		opts:   opts.Config,
-		pool:   opts.Pool,
-		pub:    opts.Publish,
+		ctx:     ctx,
+		client:  client,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -108,7 +112,13 @@ func (m *Matrix) Serve() error {
 			}
 
 			metrics.IncrementWayback(metrics.ServiceMatrix, metrics.StatusRequest)
+			if !m.limiter.Allow(metrics.ServiceMatrix, ev.Sender.String()) {
+				// nolint:errcheck
+				m.reply(ev, service.MsgRateLimited)
+				return
+			}
 			bucket := pooling.Bucket{
+				Source: metrics.ServiceMatrix + ":" + ev.RoomID.String(),
 				Request: func(ctx context.Context) error {
 					if err := m.process(ctx, ev); err != nil {
 						logger.Error("process request failure, error: %v", err)
@@ -176,6 +186,15 @@ func (m *Matrix) process(ctx context.Context, ev *event.Event) error {
 	text := ev.Content.AsMessage().Body
 	logger.Debug("from: %s message: %s", ev.Sender, text)
 
+	if strings.HasPrefix(text, "/"+service.CommandStatus) {
+		return m.status(ev)
+	}
+	if strings.HasPrefix(text, "/"+service.CommandSearch) {
+		return m.search(ev)
+	}
+	if strings.HasPrefix(text, "/"+service.CommandWatch) || strings.HasPrefix(text, "/"+service.CommandUnwatch) {
+		return m.watch(ev)
+	}
 	if strings.Contains(text, config.PB_SLUG) {
 		return m.playback(ev)
 	}
@@ -207,7 +226,11 @@ func (m *Matrix) process(ctx context.Context, ev *event.Event) error {
 		return nil
 	}
 
-	return service.Wayback(ctx, m.opts, urls, do)
+	if service.Forced(text) {
+		ctx = service.Force(ctx)
+	}
+	ctx = service.WithArtifacts(ctx, service.Artifacts(text))
+	return service.Wayback(ctx, m.opts, m.store, urls, do)
 }
 
 func (m *Matrix) playback(ev *event.Event) error {
@@ -233,6 +256,50 @@ func (m *Matrix) playback(ev *event.Event) error {
 	return nil
 }
 
+func (m *Matrix) search(ev *event.Event) error {
+	text := service.CommandArgs(ev.Content.AsMessage().Body)
+	reply := service.Search(m.ctx, m.opts, m.store, text)
+
+	body := strings.ReplaceAll(html.EscapeString(reply), "\n", "<br>")
+	if err := m.reply(ev, body); err != nil {
+		return errors.Wrap(err, "send to Matrix room failed")
+	}
+	return nil
+}
+
+func (m *Matrix) status(ev *event.Event) error {
+	reply := service.JobStatus(m.pool, metrics.ServiceMatrix+":"+ev.RoomID.String())
+
+	body := strings.ReplaceAll(html.EscapeString(reply), "\n", "<br>")
+	if err := m.reply(ev, body); err != nil {
+		return errors.Wrap(err, "send to Matrix room failed")
+	}
+	return nil
+}
+
+func (m *Matrix) watch(ev *event.Event) error {
+	text := ev.Content.AsMessage().Body
+	owner := metrics.ServiceMatrix + ":" + ev.RoomID.String()
+
+	var reply string
+	switch fields := strings.Fields(text); fields[0] {
+	case "/" + service.CommandWatches:
+		reply = service.Watches(m.store, owner)
+	case "/" + service.CommandWatch:
+		reply = service.Watch(m.store, owner, service.CommandArgs(text))
+	case "/" + service.CommandUnwatch:
+		reply = service.Unwatch(m.store, owner, service.CommandArgs(text))
+	default:
+		return errors.New("Matrix: unknown command " + fields[0])
+	}
+
+	body := strings.ReplaceAll(html.EscapeString(reply), "\n", "<br>")
+	if err := m.reply(ev, body); err != nil {
+		return errors.Wrap(err, "send to Matrix room failed")
+	}
+	return nil
+}
+
 func (m *Matrix) reply(ev *event.Event, msg string) error {
 	content := &event.MessageEventContent{
 		FormattedBody: msg,
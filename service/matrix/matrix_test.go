@@ -6,16 +6,19 @@ package matrix // import "github.com/wabarc/wayback/service/matrix"
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/wabarc/helper"
 	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
 	"github.com/wabarc/wayback/service"
@@ -298,3 +301,54 @@ func (m *Matrix) destroyRoomForTest(roomID id.RoomID) {
 	m.client.LeaveRoom(m.ctx, roomID)
 	m.client.ForgetRoom(m.ctx, roomID)
 }
+
+func TestStatus(t *testing.T) {
+	_, mux, server := helper.MockServer()
+	defer server.Close()
+
+	var reply event.MessageEventContent
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPut || !strings.Contains(r.URL.Path, "/send/m.room.message/") {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reply); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"event_id": "$reply"}`)
+	})
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	client, err := matrix.NewClient(server.URL, "@wayback:example.com", "foo")
+	if err != nil {
+		t.Fatalf("Unexpected new Matrix client: %v", err)
+	}
+
+	ctx := context.Background()
+	pool := pooling.New(ctx, pooling.Capacity(1))
+	room := id.RoomID("!foo:example.com")
+	jobID := pool.Put(pooling.Bucket{Source: metrics.ServiceMatrix + ":" + room.String()})
+	pool.Put(pooling.Bucket{Source: metrics.ServiceMatrix + ":!bar:example.com"})
+
+	m := &Matrix{ctx: ctx, opts: opts, pool: pool, client: client}
+	ev := &event.Event{
+		ID:     "$status",
+		Sender: "@alice:example.com",
+		RoomID: room,
+		Type:   event.EventMessage,
+		Content: event.Content{
+			Parsed: &event.MessageEventContent{MsgType: event.MsgText, Body: "/status"},
+		},
+	}
+	if err := m.process(ctx, ev); err != nil {
+		t.Fatalf("Unexpected process status command: %v", err)
+	}
+	if !strings.Contains(reply.FormattedBody, fmt.Sprintf("#%d queued", jobID)) || strings.Contains(reply.FormattedBody, "#2") {
+		t.Errorf("Unexpected status reply: %s", reply.FormattedBody)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/storage"
 )
 
@@ -24,6 +25,9 @@ type Options struct {
 
 	// Storage holds the storage service to be used.
 	Storage *storage.Storage
+
+	// Limiter holds the rate limiter of archiving requests to be used.
+	Limiter *ratelimit.Limiter
 }
 
 // Option is a function that modifies the provided Options instance.
@@ -64,3 +68,10 @@ func Storage(s *storage.Storage) Option {
 		opts.Storage = s
 	}
 }
+
+// Limiter returns an Option function that sets the Limiter field of Options.
+func Limiter(l *ratelimit.Limiter) Option {
+	return func(opts *Options) {
+		opts.Limiter = l
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -37,12 +38,13 @@ var ErrServiceClosed = errors.New("irc: Service closed")
 
 // IRC represents an IRC service in the application.
 type IRC struct {
-	ctx   context.Context
-	opts  *config.Options
-	pool  *pooling.Pool
-	conn  *irc.Client
-	store *storage.Storage
-	pub   *publish.Publish
+	ctx     context.Context
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	conn    *irc.Client
+	store   *storage.Storage
+	pub     *publish.Publish
 	sync.RWMutex
 }
 
@@ -56,11 +58,12 @@ func New(ctx context.Context, opts service.Options) (*IRC, error) {
 	}
 
 	return &IRC{
-		ctx:   ctx,
-		store: opts.Storage,
-		opts:  opts.Config,
-		pool:  opts.Pool,
-		pub:   opts.Publish,
+		ctx:     ctx,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -167,6 +170,9 @@ func (i *IRC) process(m *irc.Message) error {
 	case strings.HasPrefix(text, service.CommandHelp):
 		return i.reply(m.Name, i.helper()...)
 
+	case strings.HasPrefix(text, service.CommandStatus):
+		return i.reply(m.Name, strings.Split(service.JobStatus(i.pool, metrics.ServiceIRC+":"+m.Name), "\n")...)
+
 	case len(urls) == 0:
 		metrics.IncrementWayback(metrics.ServiceIRC, metrics.StatusRequest)
 		logger.Warn("archives failure, URL no found.")
@@ -178,11 +184,20 @@ func (i *IRC) process(m *irc.Message) error {
 	case strings.HasPrefix(text, service.CommandPrivacy):
 		return i.reply(m.Name, i.privacy()...)
 
+	case !i.limiter.Allow(metrics.ServiceIRC, m.Name):
+		metrics.IncrementWayback(metrics.ServiceIRC, metrics.StatusRequest)
+		return i.reply(m.Name, service.MsgRateLimited)
+
 	default:
 		metrics.IncrementWayback(metrics.ServiceIRC, metrics.StatusRequest)
 		i.reply(m.Name, "I'll help you archive the URL and return the results promptly.") // nolint:errcheck
 		bucket := pooling.Bucket{
+			Source: metrics.ServiceIRC + ":" + m.Name,
 			Request: func(ctx context.Context) error {
+				if service.Forced(text) {
+					ctx = service.Force(ctx)
+				}
+				ctx = service.WithArtifacts(ctx, service.Artifacts(text))
 				if err := i.wayback(ctx, m, urls); err != nil {
 					return errors.Wrap(err, "archives failed")
 				}
@@ -213,7 +228,7 @@ func (i *IRC) wayback(ctx context.Context, m *irc.Message, urls []*url.URL) erro
 		return i.reply(m.Name, txt...)
 	}
 
-	return service.Wayback(ctx, i.opts, urls, do)
+	return service.Wayback(ctx, i.opts, i.store, urls, do)
 }
 
 func (i *IRC) playback(m *irc.Message, urls []*url.URL) error {
@@ -261,6 +276,7 @@ func (i *IRC) helper() []string {
 		"Examples:",
 		"    /msg " + i.conn.CurrentNick() + " https://example.com",
 		"    /msg " + i.conn.CurrentNick() + " playback https://example.com",
+		"    /msg " + i.conn.CurrentNick() + " status",
 		" ",
 		"Documentation:",
 		"    https://docs.wabarc.eu.org",
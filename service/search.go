@@ -0,0 +1,64 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/storage"
+)
+
+// maxSearchHits is the number of hits replied by the search command.
+const maxSearchHits = 5
+
+// CommandArgs returns the words following the command of the text, e.g.
+// the arguments of `/search hello world` are `hello world`.
+func CommandArgs(text string) string {
+	words := strings.Fields(text)
+	if len(words) < 2 {
+		return ""
+	}
+	return strings.Join(words[1:], " ")
+}
+
+// Search returns the archived pages matched the full-text query, it is the
+// reply of the search command.
+func Search(ctx context.Context, opts *config.Options, store *storage.Storage, query string) string {
+	if store == nil || opts.IsDefaultDatabaseURL() {
+		return "Search requires the database, it is not enabled."
+	}
+	if strings.TrimSpace(query) == "" {
+		return "Usage: search <words>"
+	}
+
+	hits, err := store.Search(ctx, storage.WaybackFilter{Query: query, Limit: maxSearchHits})
+	if err != nil {
+		logger.Error("search %q failed: %v", query, err)
+		return "Search failed, please try later."
+	}
+	if len(hits) == 0 {
+		return "No results."
+	}
+
+	var sb strings.Builder
+	for i, hit := range hits {
+		title := hit.Source
+		if hit.Page != nil && hit.Page.Title != "" {
+			title = hit.Page.Title
+		}
+		fmt.Fprintf(&sb, "%d. %s\n%s\n", i+1, title, hit.Source)
+		for _, a := range hit.Archives {
+			if a.Dest != "" {
+				fmt.Fprintf(&sb, "%s: %s\n", config.SlotName(a.Slot), a.Dest)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/search hello  world", "hello world"},
+		{"/search@wayback_bot hello", "hello"},
+		{"search", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := CommandArgs(test.text); got != test.want {
+				t.Errorf("Unexpected command args, got %q instead of %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "wayback.db")
+	os.Clearenv()
+	os.Setenv("WAYBACK_DATABASE_URL", dsn)
+	defer os.Clearenv()
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+	ctx := context.Background()
+
+	if got := Search(ctx, opts, nil, "example"); !strings.Contains(got, "requires the database") {
+		t.Fatalf("Unexpected search reply without database: %s", got)
+	}
+
+	db, err := storage.NewConnectionPool(dsn, 1, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Connect to database failed: %v", err)
+	}
+	if err = storage.Migrate(db); err != nil {
+		t.Fatalf("Migrate database failed: %v", err)
+	}
+	store := storage.NewStorage(db, nil)
+	defer store.Close()
+
+	cols := []wayback.Collect{
+		{Arc: config.SLOT_IA, Src: "https://example.com/", Dst: "https://web.archive.org/web/https://example.com/", Status: wayback.StatusSuccess},
+	}
+	if err = store.CreateWayback(ctx, cols, reduxer.BundleExample()); err != nil {
+		t.Fatalf("Create wayback failed: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "Usage: search <words>"},
+		{"nothing", "No results."},
+		{"illustrative examples", "1. Example\nhttps://example.com/\nInternet Archive: https://web.archive.org/web/https://example.com/"},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			if got := Search(ctx, opts, store, test.query); got != test.want {
+				t.Errorf("Unexpected search reply, got %q instead of %q", got, test.want)
+			}
+		})
+	}
+}
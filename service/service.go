@@ -15,6 +15,7 @@ import (
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/errors"
 	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/storage"
 )
 
 const (
@@ -22,9 +23,15 @@ const (
 	CommandMetrics  = "metrics"
 	CommandPlayback = "playback"
 	CommandPrivacy  = "privacy"
+	CommandSearch   = "search"
+	CommandStatus   = "status"
+	CommandWatch    = "watch"
+	CommandUnwatch  = "unwatch"
+	CommandWatches  = "watches"
 
 	MsgWaybackRetrying = "wayback timeout, retrying."
 	MsgWaybackTimeout  = "wayback timeout, please try later."
+	MsgRateLimited     = "too many requests, please slow down."
 )
 
 var ErrMissingURL = fmt.Errorf("URL no found")
@@ -87,8 +94,14 @@ func Shutdown() (errs error) {
 	return
 }
 
-// Wayback in a separate goroutine.
-func Wayback(ctx context.Context, opts *config.Options, urls []*url.URL, do doFunc) error {
+// Wayback in a separate goroutine, the recent captures stored in the store
+// are reused if all the URLs were archived within the freshness window.
+func Wayback(ctx context.Context, opts *config.Options, store *storage.Storage, urls []*url.URL, do doFunc) error {
+	if cols, ok := recent(ctx, opts, store, urls); ok {
+		logger.Info("reuse recent captures of %d URL(s)", len(urls))
+		return do(cols, reduxer.NewReduxer())
+	}
+
 	var done = make(chan error, 1)
 	var cols []wayback.Collect
 	var rdx reduxer.Reduxer
@@ -120,6 +133,7 @@ func Wayback(ctx context.Context, opts *config.Options, urls []*url.URL, do doFu
 		// Keep reduxer for publish
 		// defer rdx.Flush()
 
+		detect(ctx, opts, store, rdx, cols)
 		return do(cols, rdx)
 	}
 }
@@ -45,7 +45,7 @@ func TestWayback(t *testing.T) {
 		time.Sleep(3 * time.Second)
 		return nil
 	}
-	w := Wayback(ctx, opts, urls, do)
+	w := Wayback(ctx, opts, nil, urls, do)
 
 	if w != nil {
 		t.Logf("Unexpected wayback exceeded: %v", w)
@@ -80,7 +80,7 @@ func TestWaybackWithoutReduxer(t *testing.T) {
 		time.Sleep(3 * time.Second)
 		return nil
 	}
-	w := Wayback(ctx, opts, urls, do)
+	w := Wayback(ctx, opts, nil, urls, do)
 
 	if w != nil {
 		t.Logf("Unexpected wayback exceeded: %v", w)
@@ -21,6 +21,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -60,12 +61,13 @@ var ErrServiceClosed = errors.New("slack: Service closed")
 type Slack struct {
 	ctx context.Context
 
-	bot    *slack.Client
-	client *socketmode.Client
-	store  *storage.Storage
-	opts   *config.Options
-	pool   *pooling.Pool
-	pub    *publish.Publish
+	bot     *slack.Client
+	client  *socketmode.Client
+	store   *storage.Storage
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	pub     *publish.Publish
 }
 
 type event struct {
@@ -97,13 +99,14 @@ func New(ctx context.Context, opts service.Options) (*Slack, error) {
 	}
 
 	return &Slack{
-		ctx:    ctx,
-		bot:    bot,
-		client: client,
-		store:  opts.Storage,
-		opts:   opts.Config,
-		pool:   opts.Pool,
-		pub:    opts.Publish,
+		ctx:     ctx,
+		bot:     bot,
+		client:  client,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -256,6 +259,47 @@ func (s *Slack) handleCommand(evt socketmode.Event) {
 	case service.CommandPlayback:
 		// nolint:errcheck
 		s.playback(cmd.ChannelID, cmd.Text, cmd.TriggerID)
+	case service.CommandStatus:
+		payload = map[string]interface{}{
+			"blocks": []slack.Block{
+				slack.NewSectionBlock(
+					&slack.TextBlockObject{
+						Type: slack.PlainTextType,
+						Text: service.JobStatus(s.pool, metrics.ServiceSlack+":"+cmd.ChannelID),
+					},
+					nil, nil,
+				),
+			}}
+	case service.CommandSearch:
+		payload = map[string]interface{}{
+			"blocks": []slack.Block{
+				slack.NewSectionBlock(
+					&slack.TextBlockObject{
+						Type: slack.PlainTextType,
+						Text: service.Search(s.ctx, s.opts, s.store, cmd.Text),
+					},
+					nil, nil,
+				),
+			}}
+	case service.CommandWatch, service.CommandUnwatch, service.CommandWatches:
+		owner := metrics.ServiceSlack + ":" + cmd.ChannelID
+		text := service.Watches(s.store, owner)
+		switch strings.TrimPrefix(cmd.Command, "/") {
+		case service.CommandWatch:
+			text = service.Watch(s.store, owner, cmd.Text)
+		case service.CommandUnwatch:
+			text = service.Unwatch(s.store, owner, cmd.Text)
+		}
+		payload = map[string]interface{}{
+			"blocks": []slack.Block{
+				slack.NewSectionBlock(
+					&slack.TextBlockObject{
+						Type: slack.PlainTextType,
+						Text: text,
+					},
+					nil, nil,
+				),
+			}}
 	case service.CommandPrivacy:
 		payload = map[string]interface{}{
 			"blocks": []slack.Block{
@@ -285,13 +329,24 @@ func (s *Slack) process(ev *event) (err error) {
 		return errors.New("URL no found")
 	}
 
+	if !s.limiter.Allow(metrics.ServiceSlack, ev.User) {
+		// nolint:errcheck
+		s.reply(ev, service.MsgRateLimited)
+		return errors.New("rate limit exceeded")
+	}
+
 	ev, err = s.reply(ev, "Queue...")
 	if err != nil {
 		logger.Error("reply queue failed: %v", err)
 		return
 	}
 	bucket := pooling.Bucket{
+		Source: metrics.ServiceSlack + ":" + ev.Channel,
 		Request: func(ctx context.Context) error {
+			if service.Forced(content) {
+				ctx = service.Force(ctx)
+			}
+			ctx = service.WithArtifacts(ctx, service.Artifacts(content))
 			if err := s.wayback(ctx, ev, urls); err != nil {
 				logger.Error("archives failed: %v", err)
 				// nolint:errcheck
@@ -346,7 +401,7 @@ func (s *Slack) wayback(ctx context.Context, ev *event, urls []*url.URL) error {
 		return nil
 	}
 
-	return service.Wayback(ctx, s.opts, urls, do)
+	return service.Wayback(ctx, s.opts, s.store, urls, do)
 }
 
 func (s *Slack) playback(channel, text, triggerID string) error {
@@ -0,0 +1,58 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wabarc/wayback/pooling"
+)
+
+// maxStatusJobs is the number of recent jobs replied by the status command.
+const maxStatusJobs = 10
+
+// JobStatus returns the states of recent jobs sent from the given source,
+// it is the reply of the status command.
+func JobStatus(pool *pooling.Pool, source string) string {
+	var jobs []pooling.Job
+	for _, job := range pool.Jobs() {
+		if job.Source == source {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return "No archiving jobs."
+	}
+	if len(jobs) > maxStatusJobs {
+		jobs = jobs[len(jobs)-maxStatusJobs:]
+	}
+
+	var sb strings.Builder
+	for i := len(jobs) - 1; i >= 0; i-- {
+		job := jobs[i]
+		fmt.Fprintf(&sb, "#%d %s, %s ago", job.ID, job.State, time.Since(job.Updated).Round(time.Second))
+		if job.Attempts > 0 {
+			fmt.Fprintf(&sb, ", %d failed attempts", job.Attempts)
+		}
+		if job.Error != "" {
+			fmt.Fprintf(&sb, ": %s", job.Error)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// AskedStatus reports whether the text contains the status command, it is
+// used by the services that receive the commands in mentions.
+func AskedStatus(text string) bool {
+	for _, field := range strings.Fields(text) {
+		if field == "/"+CommandStatus {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/pooling"
+)
+
+func TestJobStatus(t *testing.T) {
+	pool := pooling.New(context.Background(), pooling.Capacity(1), pooling.Timeout(time.Second))
+
+	if got := JobStatus(pool, "telegram:1"); got != "No archiving jobs." {
+		t.Fatalf("Unexpected status got %q", got)
+	}
+
+	pool.Put(pooling.Bucket{Source: "telegram:1"})
+	pool.Put(pooling.Bucket{Source: "telegram:2"})
+	id := pool.Put(pooling.Bucket{Source: "telegram:1"})
+	if err := pool.Cancel(id); err != nil {
+		t.Fatalf("Unexpected cancel job: %v", err)
+	}
+
+	lines := strings.Split(JobStatus(pool, "telegram:1"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected status lines got %d instead of 2: %v", len(lines), lines)
+	}
+	if !strings.HasPrefix(lines[0], "#3 cancelled") || !strings.HasPrefix(lines[1], "#1 queued") {
+		t.Errorf("Unexpected status: %v", lines)
+	}
+}
+
+func TestAskedStatus(t *testing.T) {
+	var tests = []struct {
+		text  string
+		asked bool
+	}{
+		{"/status", true},
+		{"@wayback /status", true},
+		{"https://twitter.com/foo/status/1", false},
+		{"/statuses", false},
+		{"https://example.com/", false},
+	}
+
+	for _, test := range tests {
+		if got := AskedStatus(test.text); got != test.asked {
+			t.Errorf("Unexpected asked status of %q, got %t", test.text, got)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package telegram // import "github.com/wabarc/wayback/service/telegram"
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -24,6 +25,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -47,11 +49,12 @@ var (
 type Telegram struct {
 	ctx context.Context
 
-	bot   *telegram.Bot
-	store *storage.Storage
-	opts  *config.Options
-	pool  *pooling.Pool
-	pub   *publish.Publish
+	bot     *telegram.Bot
+	store   *storage.Storage
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	pub     *publish.Publish
 }
 
 // New Telegram struct.
@@ -79,12 +82,13 @@ func New(ctx context.Context, opts service.Options) (*Telegram, error) {
 	}
 
 	return &Telegram{
-		ctx:   ctx,
-		bot:   bot,
-		store: opts.Storage,
-		opts:  opts.Config,
-		pool:  opts.Pool,
-		pub:   opts.Publish,
+		ctx:     ctx,
+		bot:     bot,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -173,6 +177,11 @@ func (t *Telegram) Serve() (err error) {
 		t.bot.Start()
 	}()
 
+	// Resume requests interrupted by last shutdown
+	if err := t.pool.Resume(metrics.ServiceTelegram, t.resume); err != nil {
+		logger.Error("resume requests failed: %v", err)
+	}
+
 	// Block until context done
 	<-t.ctx.Done()
 
@@ -228,6 +237,21 @@ func (t *Telegram) process(message *telegram.Message) (err error) {
 	case command == service.CommandPrivacy:
 		// nolint:errcheck
 		t.reply(message, fmt.Sprintf("To read our privacy policy, please visit %s.", t.opts.PrivacyURL()))
+	case command == service.CommandStatus:
+		// nolint:errcheck
+		t.reply(message, service.JobStatus(t.pool, source(message)))
+	case command == service.CommandSearch:
+		// nolint:errcheck
+		t.reply(message, service.Search(t.ctx, t.opts, t.store, service.CommandArgs(content)))
+	case command == service.CommandWatch:
+		// nolint:errcheck
+		t.reply(message, service.Watch(t.store, source(message), service.CommandArgs(content)))
+	case command == service.CommandUnwatch:
+		// nolint:errcheck
+		t.reply(message, service.Unwatch(t.store, source(message), service.CommandArgs(content)))
+	case command == service.CommandWatches:
+		// nolint:errcheck
+		t.reply(message, service.Watches(t.store, source(message)))
 	case command != "":
 		fallback := t.commandFallback()
 		if fallback != "" {
@@ -239,39 +263,101 @@ func (t *Telegram) process(message *telegram.Message) (err error) {
 		logger.Warn("archives failure, URL no found.")
 		metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusRequest)
 		t.reply(message, "URL no found.") // nolint:errcheck
+	case !t.limiter.Allow(metrics.ServiceTelegram, sender(message)):
+		logger.Warn("archives rejected, rate limit exceeded.")
+		t.reply(message, service.MsgRateLimited) // nolint:errcheck
 	default:
 		metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusRequest)
 		request, err := t.reply(message, "Queue...")
 		if err != nil {
 			return errors.Wrap(err, "reply message failed")
 		}
-		bucket := pooling.Bucket{
-			Request: func(ctx context.Context) error {
-				_, err := t.bot.Edit(request, "Archiving...")
-				if err != nil && err != telegram.ErrSameMessageContent {
-					return errors.Wrap(err, "telegram: send archiving message failed")
-				}
-
-				if err := t.wayback(ctx, request, urls); err != nil {
-					// nolint:errcheck
-					t.bot.Edit(request, service.MsgWaybackRetrying)
-					return errors.Wrap(err, "archives failed")
-				}
-				metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusSuccess)
-				return nil
-			},
-			Fallback: func(_ context.Context) error {
-				t.bot.Delete(request)                           // nolint:errcheck
-				t.bot.Reply(message, service.MsgWaybackTimeout) // nolint:errcheck
-				metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusFailure)
-				return nil
-			},
+		bucket, err := t.bucket(message, request, urls, service.Forced(content), service.Artifacts(content))
+		if err != nil {
+			return errors.Wrap(err, "create bucket failed")
 		}
 		t.pool.Put(bucket)
 	}
 	return nil
 }
 
+// job holds the data to resume a wayback request after restart.
+type job struct {
+	ChatID    int64    `json:"chat_id"`
+	MessageID int      `json:"message_id"`
+	RequestID int      `json:"request_id"`
+	URLs      []string `json:"urls"`
+	Force     bool     `json:"force,omitempty"`
+	Artifacts string   `json:"artifacts,omitempty"`
+}
+
+// bucket returns the bucket that archives the URLs of the message, the
+// request is the message replied to the message for the progress, force
+// archives the URLs again regardless of the freshness window, and artifacts
+// is the profile of the artifacts if specified.
+func (t *Telegram) bucket(message, request *telegram.Message, urls []*url.URL, force bool, artifacts string) (pooling.Bucket, error) {
+	j := job{ChatID: message.Chat.ID, MessageID: message.ID, RequestID: request.ID, Force: force, Artifacts: artifacts}
+	for _, u := range urls {
+		j.URLs = append(j.URLs, u.String())
+	}
+	payload, err := json.Marshal(j)
+	if err != nil {
+		return pooling.Bucket{}, err
+	}
+
+	return pooling.Bucket{
+		Kind:    metrics.ServiceTelegram,
+		Payload: payload,
+		Source:  source(message),
+		Request: func(ctx context.Context) error {
+			_, err := t.bot.Edit(request, "Archiving...")
+			if err != nil && err != telegram.ErrSameMessageContent {
+				return errors.Wrap(err, "telegram: send archiving message failed")
+			}
+
+			if force {
+				ctx = service.Force(ctx)
+			}
+			ctx = service.WithArtifacts(ctx, artifacts)
+			if err := t.wayback(ctx, request, urls); err != nil {
+				// nolint:errcheck
+				t.bot.Edit(request, service.MsgWaybackRetrying)
+				return errors.Wrap(err, "archives failed")
+			}
+			metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusSuccess)
+			return nil
+		},
+		Fallback: func(_ context.Context) error {
+			t.bot.Delete(request)                           // nolint:errcheck
+			t.bot.Reply(message, service.MsgWaybackTimeout) // nolint:errcheck
+			metrics.IncrementWayback(metrics.ServiceTelegram, metrics.StatusFailure)
+			return nil
+		},
+	}, nil
+}
+
+// resume rebuilds the bucket from the payload of a persisted job.
+func (t *Telegram) resume(payload []byte) (pooling.Bucket, error) {
+	var j job
+	if err := json.Unmarshal(payload, &j); err != nil {
+		return pooling.Bucket{}, err
+	}
+
+	urls := make([]*url.URL, 0, len(j.URLs))
+	for _, s := range j.URLs {
+		u, err := url.Parse(s)
+		if err != nil {
+			return pooling.Bucket{}, err
+		}
+		urls = append(urls, u)
+	}
+	chat := &telegram.Chat{ID: j.ChatID}
+	message := &telegram.Message{ID: j.MessageID, Chat: chat}
+	request := &telegram.Message{ID: j.RequestID, Chat: chat}
+
+	return t.bucket(message, request, urls, j.Force, j.Artifacts)
+}
+
 func (t *Telegram) wayback(ctx context.Context, request *telegram.Message, urls []*url.URL) error {
 	do := func(cols []wayback.Collect, rdx reduxer.Reduxer) error {
 		opts := &telegram.SendOptions{DisableWebPagePreview: true}
@@ -305,7 +391,7 @@ func (t *Telegram) wayback(ctx context.Context, request *telegram.Message, urls
 		return nil
 	}
 
-	return service.Wayback(ctx, t.opts, urls, do)
+	return service.Wayback(ctx, t.opts, t.store, urls, do)
 }
 
 func (t *Telegram) playback(message *telegram.Message) error {
@@ -444,6 +530,28 @@ func (t *Telegram) defaultCommands() []telegram.Command {
 			Text:        service.CommandPlayback,
 			Description: "Playback archived url",
 		},
+		{
+			Text:        service.CommandStatus,
+			Description: "Show archiving jobs",
+		},
+		{
+			Text:        service.CommandWatch,
+			Description: "Archive a URL on a schedule",
+		},
+		{
+			Text:        service.CommandUnwatch,
+			Description: "Stop archiving a watched URL",
+		},
+		{
+			Text:        service.CommandWatches,
+			Description: "List watched URLs",
+		},
+	}
+	if !t.opts.IsDefaultDatabaseURL() {
+		commands = append(commands, telegram.Command{
+			Text:        service.CommandSearch,
+			Description: "Search archived pages",
+		})
 	}
 	if t.opts.PrivacyURL() != "" {
 		commands = append(commands, telegram.Command{
@@ -483,11 +591,35 @@ func command(message string) string {
 		return service.CommandMetrics
 	case strings.HasPrefix(message, "/privacy"):
 		return service.CommandPrivacy
+	case strings.HasPrefix(message, "/status"):
+		return service.CommandStatus
+	case strings.HasPrefix(message, "/search"):
+		return service.CommandSearch
+	case strings.HasPrefix(message, "/watches"):
+		return service.CommandWatches
+	case strings.HasPrefix(message, "/watch"):
+		return service.CommandWatch
+	case strings.HasPrefix(message, "/unwatch"):
+		return service.CommandUnwatch
 	default:
 		return matchCmd(message)
 	}
 }
 
+// source returns the source of jobs sent from the chat of the message.
+func source(m *telegram.Message) string {
+	return fmt.Sprintf("%s:%d", metrics.ServiceTelegram, m.Chat.ID)
+}
+
+// sender returns the user who sent the message, or the chat for messages
+// of channels, which have no sender.
+func sender(m *telegram.Message) string {
+	if m.Sender != nil {
+		return strconv.FormatInt(m.Sender.ID, 10)
+	}
+	return strconv.FormatInt(m.Chat.ID, 10)
+}
+
 func transform(m *telegram.Message) {
 	entities := func(e telegram.Entities) (uri []string) {
 		for _, entity := range e {
@@ -17,6 +17,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -36,6 +37,7 @@ type Twitter struct {
 	ctx       context.Context
 	opts      *config.Options
 	pool      *pooling.Pool
+	limiter   *ratelimit.Limiter
 	client    *twitter.Client
 	store     *storage.Storage
 	pub       *publish.Publish
@@ -61,12 +63,13 @@ func New(ctx context.Context, opts service.Options) (*Twitter, error) {
 	client := twitter.NewClient(httpClient)
 
 	return &Twitter{
-		ctx:    ctx,
-		client: client,
-		store:  opts.Storage,
-		opts:   opts.Config,
-		pool:   opts.Pool,
-		pub:    opts.Publish,
+		ctx:     ctx,
+		client:  client,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -104,7 +107,12 @@ func (t *Twitter) Serve() error {
 					}
 					go func(event twitter.DirectMessageEvent) {
 						metrics.IncrementWayback(metrics.ServiceTwitter, metrics.StatusRequest)
+						if !t.limiter.Allow(metrics.ServiceTwitter, event.Message.SenderID) {
+							t.reply(event, service.MsgRateLimited) // nolint:errcheck
+							return
+						}
 						bucket := pooling.Bucket{
+							Source: metrics.ServiceTwitter + ":" + event.Message.SenderID,
 							Request: func(ctx context.Context) error {
 								if err := t.process(ctx, event); err != nil {
 									logger.Error("process failure, message: %#v, error: %v", event.Message, err)
@@ -167,6 +175,11 @@ func (t *Twitter) process(ctx context.Context, event twitter.DirectMessageEvent)
 		t.Unlock()
 	}()
 
+	if service.AskedStatus(text) {
+		_, err := t.reply(event, service.JobStatus(t.pool, metrics.ServiceTwitter+":"+msg.SenderID))
+		return err
+	}
+
 	urls := service.MatchURL(t.opts, text)
 	if len(urls) == 0 {
 		logger.Warn("archives failure, URL no found.")
@@ -200,7 +213,11 @@ func (t *Twitter) process(ctx context.Context, event twitter.DirectMessageEvent)
 		return nil
 	}
 
-	return service.Wayback(ctx, t.opts, urls, do)
+	if service.Forced(text) {
+		ctx = service.Force(ctx)
+	}
+	ctx = service.WithArtifacts(ctx, service.Artifacts(text))
+	return service.Wayback(ctx, t.opts, t.store, urls, do)
 }
 
 func (t *Twitter) reply(event twitter.DirectMessageEvent, body string) (*twitter.DirectMessageEvent, error) {
@@ -7,13 +7,16 @@ package twitter
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/dghubble/go-twitter/twitter"
 	"github.com/wabarc/helper"
 	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 )
 
@@ -104,3 +107,46 @@ func TestProcess(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func TestStatus(t *testing.T) {
+	httpClient, mux, server := helper.MockServer()
+	defer server.Close()
+
+	var reply string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/1.1/direct_messages/events/new.json":
+			body, _ := io.ReadAll(r.Body)
+			reply = string(body)
+			fmt.Fprintln(w, testDMEventShowJSON)
+		case "/1.1/direct_messages/events/destroy.json":
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("Parse environment variables or flags failed, error: %v", err)
+	}
+
+	ctx := context.Background()
+	pool := pooling.New(ctx, pooling.Capacity(1))
+	id := pool.Put(pooling.Bucket{Source: metrics.ServiceTwitter + ":" + testDMEvent.Message.SenderID})
+	pool.Put(pooling.Bucket{Source: metrics.ServiceTwitter + ":1"})
+
+	event := testDMEvent
+	event.Message = &twitter.DirectMessageEventMessage{
+		SenderID: testDMEvent.Message.SenderID,
+		Data:     &twitter.DirectMessageData{Text: "/status"},
+	}
+	tw := &Twitter{ctx: ctx, pool: pool, opts: opts, client: twitter.NewClient(httpClient)}
+	if err := tw.process(ctx, event); err != nil {
+		t.Fatalf("Unexpected process status command: %v", err)
+	}
+	if !strings.Contains(reply, fmt.Sprintf("#%d queued", id)) || strings.Contains(reply, "#2") {
+		t.Errorf("Unexpected status reply: %s", reply)
+	}
+}
@@ -91,6 +91,8 @@ func filterArtifact(art reduxer.Artifact, upper int64) (paths []string) {
 		art.HTM,
 		art.WARC,
 		art.Media,
+		art.Diff,
+		art.DiffImg,
 	}
 
 	var fsize int64
@@ -0,0 +1,182 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/feed"
+	"github.com/wabarc/wayback/ingress"
+	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/schedule"
+	"github.com/wabarc/wayback/storage"
+)
+
+// DefaultSchedule is the schedule of the watches created without one.
+const DefaultSchedule = "@daily"
+
+// FeedTag is the tag in the arguments of the watch command to watch a
+// sitemap, feed or OPML list, e.g. "https://example.com/feed.xml #feed".
+const FeedTag = "#feed"
+
+// maxFeedItems is the maximum number of the new items of a feed archived by
+// a run of the watch, the rest are archived by the next runs.
+const maxFeedItems = 20
+
+// feedRetention is the duration the seen items of a feed are kept after they
+// are no longer listed by the feed.
+const feedRetention = 30 * 24 * time.Hour
+
+// ArchiveWatch returns the schedule.Func that archives the watched URL
+// regardless of the freshness window and publishes the results.
+func ArchiveWatch(opts Options) schedule.Func {
+	return func(ctx context.Context, w *entity.Watch) error {
+		u, err := url.Parse(w.URL)
+		if err != nil {
+			return err
+		}
+		do := func(cols []wayback.Collect, rdx reduxer.Reduxer) error {
+			if opts.Publish != nil {
+				opts.Publish.Spread(ctx, rdx, cols, publish.FlagSchedule)
+			}
+			return nil
+		}
+		return Wayback(Force(ctx), opts.Config, opts.Storage, []*url.URL{u}, do)
+	}
+}
+
+// ExpandWatch returns the schedule.ExpandFunc that returns the new items of
+// the feed of the watch, the items are marked as seen by the scheduler once
+// archived, and the stale items no longer listed by the feed are forgotten.
+func ExpandWatch(opts Options) schedule.ExpandFunc {
+	return func(ctx context.Context, w *entity.Watch) ([]string, error) {
+		if opts.Storage == nil {
+			return nil, fmt.Errorf("storage missing")
+		}
+		items, err := feed.Expand(ctx, ingress.Client(), w.URL)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = opts.Storage.PruneSeen(w.URL, items, time.Now().Add(-feedRetention)); err != nil {
+			logger.Warn("prune seen items of %s failed: %v", w.URL, err)
+		}
+		unseen, err := opts.Storage.Unseen(w.URL, items)
+		if err != nil {
+			return nil, err
+		}
+		if len(unseen) > maxFeedItems {
+			unseen = unseen[:maxFeedItems]
+		}
+		return unseen, nil
+	}
+}
+
+// Watch creates a watch owned by the owner from the arguments of the watch
+// command, which are the URL and an optional schedule, e.g.
+// `https://example.com 0 8 * * *`, the FeedTag in the arguments watches the
+// new items of the feed of the URL. It returns the reply of the command.
+func Watch(store *storage.Storage, owner, args string) string {
+	var fields []string
+	var isFeed bool
+	for _, field := range strings.Fields(args) {
+		if strings.EqualFold(field, FeedTag) {
+			isFeed = true
+			continue
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return "Usage: watch <url> [schedule] [" + FeedTag + "], the schedule defaults to " + DefaultSchedule
+	}
+	spec := DefaultSchedule
+	if len(fields) > 1 {
+		spec = strings.Join(fields[1:], " ")
+	}
+
+	w, err := schedule.NewWatch(fields[0], spec, owner)
+	if err != nil {
+		return err.Error()
+	}
+	w.Feed = isFeed
+	if store == nil {
+		return "Watches are not supported."
+	}
+	if err = store.CreateWatch(w); err != nil {
+		logger.Error("create watch failed: %v", err)
+		return "Create watch failed, please try later."
+	}
+	if w.Feed {
+		return fmt.Sprintf("Watch #%d created, the new items of %s will be archived %s, next run at %s.", w.ID, w.URL, w.Schedule, w.NextRun.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Watch #%d created, %s will be archived %s, next run at %s.", w.ID, w.URL, w.Schedule, w.NextRun.Format(time.RFC3339))
+}
+
+// Unwatch deletes the watch of the id given by the arguments of the unwatch
+// command, only the watches owned by the owner can be deleted. It returns
+// the reply of the command.
+func Unwatch(store *storage.Storage, owner, args string) string {
+	id, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(args), "#"), 10, 64)
+	if err != nil {
+		return "Usage: unwatch <id>"
+	}
+	if store == nil {
+		return "Watches are not supported."
+	}
+
+	w, err := store.Watch(id)
+	if err == storage.ErrWatchNotFound || (err == nil && w.Owner != owner) {
+		return fmt.Sprintf("Watch #%d not found.", id)
+	}
+	if err == nil {
+		err = store.DeleteWatch(id)
+	}
+	if err != nil {
+		logger.Error("delete watch %d failed: %v", id, err)
+		return "Delete watch failed, please try later."
+	}
+	return fmt.Sprintf("Watch #%d deleted.", id)
+}
+
+// Watches returns the watches owned by the owner, it is the reply of the
+// watches command.
+func Watches(store *storage.Storage, owner string) string {
+	if store == nil {
+		return "Watches are not supported."
+	}
+	watches, err := store.Watches()
+	if err != nil {
+		logger.Error("query watches failed: %v", err)
+		return "Query watches failed, please try later."
+	}
+
+	var sb strings.Builder
+	for _, w := range watches {
+		if w.Owner != owner {
+			continue
+		}
+		fmt.Fprintf(&sb, "#%d %s %s", w.ID, w.URL, w.Schedule)
+		if w.Feed {
+			sb.WriteString(" " + FeedTag)
+		}
+		fmt.Fprintf(&sb, ", next run at %s", w.NextRun.Format(time.RFC3339))
+		if w.LastError != "" {
+			fmt.Fprintf(&sb, ", last run failed: %s", w.LastError)
+		}
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return "No watches."
+	}
+	return strings.TrimSpace(sb.String())
+}
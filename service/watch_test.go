@@ -0,0 +1,102 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package service // import "github.com/wabarc/wayback/service"
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/storage"
+)
+
+func TestWatch(t *testing.T) {
+	db, err := storage.Open(&config.Options{}, filepath.Join(t.TempDir(), "testing.db"))
+	if err != nil {
+		t.Fatalf("open storage failed: %v", err)
+	}
+	store := storage.NewStorage(nil, db)
+	defer store.Close()
+
+	if got := Watch(store, "alice", ""); !strings.HasPrefix(got, "Usage:") {
+		t.Errorf("Unexpected reply of missing url: %s", got)
+	}
+	if got := Watch(store, "alice", "https://example.com daily"); !strings.Contains(got, "invalid schedule") {
+		t.Errorf("Unexpected reply of invalid schedule: %s", got)
+	}
+	if got := Watch(store, "alice", "https://example.com"); !strings.HasPrefix(got, "Watch #1 created") || !strings.Contains(got, DefaultSchedule) {
+		t.Errorf("Unexpected reply of watch: %s", got)
+	}
+	if got := Watch(store, "bob", "https://example.org 0 8 * * *"); !strings.HasPrefix(got, "Watch #2 created") || !strings.Contains(got, "0 8 * * *") {
+		t.Errorf("Unexpected reply of watch: %s", got)
+	}
+
+	got := Watches(store, "alice")
+	if !strings.Contains(got, "#1 https://example.com") || strings.Contains(got, "example.org") {
+		t.Errorf("Unexpected watches: %s", got)
+	}
+	if got = Watches(store, "carol"); got != "No watches." {
+		t.Errorf("Unexpected watches: %s", got)
+	}
+
+	if got = Unwatch(store, "alice", "abc"); got != "Usage: unwatch <id>" {
+		t.Errorf("Unexpected reply of invalid id: %s", got)
+	}
+	if got = Unwatch(store, "alice", "2"); got != "Watch #2 not found." {
+		t.Errorf("Unexpected reply of unwatching others: %s", got)
+	}
+	if got = Unwatch(store, "alice", "#1"); got != "Watch #1 deleted." {
+		t.Errorf("Unexpected reply of unwatch: %s", got)
+	}
+	if got = Watches(store, "alice"); got != "No watches." {
+		t.Errorf("Unexpected watches after unwatch: %s", got)
+	}
+
+	if got = Watch(store, "alice", "https://example.com/feed.xml #feed @hourly"); !strings.Contains(got, "the new items of https://example.com/feed.xml") {
+		t.Errorf("Unexpected reply of watching feed: %s", got)
+	}
+	if got = Watches(store, "alice"); !strings.HasPrefix(got, "#3 https://example.com/feed.xml @hourly #feed, next run at ") {
+		t.Errorf("Unexpected watches of feed: %s", got)
+	}
+}
+
+func TestExpandWatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`<rss><channel>
+<item><link>https://example.com/posts/1</link></item>
+<item><link>https://example.com/posts/2</link></item>
+</channel></rss>`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	db, err := storage.Open(&config.Options{}, filepath.Join(t.TempDir(), "testing.db"))
+	if err != nil {
+		t.Fatalf("open storage failed: %v", err)
+	}
+	store := storage.NewStorage(nil, db)
+	defer store.Close()
+
+	expand := ExpandWatch(ParseOptions(Storage(store)))
+	w := &entity.Watch{ID: 1, URL: server.URL, Feed: true}
+	items, err := expand(context.Background(), w)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Unexpected items %v, error: %v", items, err)
+	}
+	// The items are new until they are archived.
+	if items, err = expand(context.Background(), w); err != nil || len(items) != 2 {
+		t.Fatalf("Unexpected new items %v, error: %v", items, err)
+	}
+	if err = store.MarkSeen(w.URL, items[:1]); err != nil {
+		t.Fatalf("Unexpected mark seen: %v", err)
+	}
+	if items, err = expand(context.Background(), w); err != nil || len(items) != 1 || items[0] != "https://example.com/posts/2" {
+		t.Fatalf("Unexpected new items %v, error: %v", items, err)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/pooling"
 	"github.com/wabarc/wayback/publish"
+	"github.com/wabarc/wayback/ratelimit"
 	"github.com/wabarc/wayback/reduxer"
 	"github.com/wabarc/wayback/service"
 	"github.com/wabarc/wayback/storage"
@@ -41,12 +42,13 @@ var ErrServiceClosed = errors.New("xmpp: Service closed")
 
 // XMPP represents an XMPP service in the application.
 type XMPP struct {
-	ctx   context.Context
-	bot   *xmpp.Session
-	opts  *config.Options
-	pool  *pooling.Pool
-	store *storage.Storage
-	pub   *publish.Publish
+	ctx     context.Context
+	bot     *xmpp.Session
+	opts    *config.Options
+	pool    *pooling.Pool
+	limiter *ratelimit.Limiter
+	store   *storage.Storage
+	pub     *publish.Publish
 }
 
 // messageBody is a message stanza that contains a body. It is normally used for
@@ -111,12 +113,13 @@ func New(ctx context.Context, opts service.Options) (*XMPP, error) {
 	}
 
 	return &XMPP{
-		ctx:   ctx,
-		bot:   bot,
-		store: opts.Storage,
-		opts:  opts.Config,
-		pool:  opts.Pool,
-		pub:   opts.Publish,
+		ctx:     ctx,
+		bot:     bot,
+		store:   opts.Storage,
+		opts:    opts.Config,
+		pool:    opts.Pool,
+		limiter: opts.Limiter,
+		pub:     opts.Publish,
 	}, nil
 }
 
@@ -207,9 +210,15 @@ func (x *XMPP) process(msg messageBody) error {
 		return x.playback(cmdctx, msg)
 	case service.CommandPrivacy:
 		return x.reply(cmdctx, msg, fmt.Sprintf("To read our privacy policy, please visit %s.", x.opts.PrivacyURL()))
+	case service.CommandStatus:
+		return x.reply(cmdctx, msg, service.JobStatus(x.pool, metrics.ServiceXMPP+":"+msg.From.Bare().String()))
 	default:
 		metrics.IncrementWayback(metrics.ServiceXMPP, metrics.StatusRequest)
+		if !x.limiter.Allow(metrics.ServiceXMPP, msg.From.Bare().String()) {
+			return x.reply(cmdctx, msg, service.MsgRateLimited)
+		}
 		bucket := pooling.Bucket{
+			Source: metrics.ServiceXMPP + ":" + msg.From.Bare().String(),
 			Request: func(ctx context.Context) error {
 				if err := x.wayback(ctx, msg); err != nil {
 					logger.Error("process failure, message: %s, error: %v", msg.Body, err)
@@ -254,7 +263,11 @@ func (x *XMPP) wayback(ctx context.Context, msg messageBody) error {
 		return nil
 	}
 
-	return service.Wayback(ctx, x.opts, urls, do)
+	if service.Forced(text) {
+		ctx = service.Force(ctx)
+	}
+	ctx = service.WithArtifacts(ctx, service.Artifacts(text))
+	return service.Wayback(ctx, x.opts, x.store, urls, do)
 }
 
 func (x *XMPP) playback(ctx context.Context, msg messageBody) error {
@@ -330,6 +343,10 @@ func command(msg messageBody) string {
 		strings.HasPrefix(body, "/"+service.CommandPrivacy),
 		strings.HasPrefix(body, service.CommandPrivacy+":"):
 		return service.CommandPrivacy
+	case strings.HasPrefix(body, service.CommandStatus),
+		strings.HasPrefix(body, "/"+service.CommandStatus),
+		strings.HasPrefix(body, service.CommandStatus+":"):
+		return service.CommandStatus
 	}
 	return "unknown"
 }
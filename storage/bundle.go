@@ -0,0 +1,189 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wabarc/logger"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/reduxer"
+)
+
+// ErrPageNotFound is returned when no page metadata stored for a request.
+var ErrPageNotFound = errors.New("page not found")
+
+// bundleOf returns the page metadata and artifacts of the source URL held by
+// rdx, the artifacts stored on the local disk are digested.
+func bundleOf(rdx reduxer.Reduxer, source string) (*entity.Page, []entity.Artifact) {
+	if rdx == nil {
+		return nil, nil
+	}
+	bundle, ok := rdx.Load(reduxer.Src(source))
+	if !ok {
+		return nil, nil
+	}
+
+	article := bundle.Article()
+	page := &entity.Page{
+		Title:    article.Title,
+		Byline:   article.Byline,
+		Excerpt:  article.Excerpt,
+		SiteName: article.SiteName,
+		Summary:  bundle.Summary(),
+		Text:     article.TextContent,
+	}
+	if shots := bundle.Shots(); page.Title == "" && shots != nil {
+		page.Title = shots.Title
+	}
+
+	artifacts := []entity.Artifact{}
+	for _, asset := range bundle.Artifact().Assets() {
+		a := entity.Artifact{Kind: asset.Kind, Path: asset.Local, Remote: asset.Remote.URL()}
+		for _, l := range asset.Remote {
+			a.Locations = append(a.Locations, entity.Location{Store: l.Store, URL: l.URL})
+		}
+		if asset.Local != "" {
+			size, sum, err := asset.Digest()
+			if err != nil {
+				logger.Warn("digest %s artifact of %s failed: %v", asset.Kind, source, err)
+			}
+			a.Size, a.SHA256 = size, sum
+		}
+		artifacts = append(artifacts, a)
+	}
+
+	return page, artifacts
+}
+
+func createBundle(ctx context.Context, tx *sql.Tx, wayback_id int64, page *entity.Page, artifacts []entity.Artifact) error {
+	if page != nil {
+		query := `INSERT INTO pages (wayback_id, title, byline, excerpt, site_name, summary, text) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := tx.ExecContext(ctx, query, wayback_id, page.Title, page.Byline, page.Excerpt, page.SiteName, page.Summary, page.Text)
+		if err != nil {
+			return fmt.Errorf("store: unable to create page: %v", err)
+		}
+	}
+
+	for _, a := range artifacts {
+		var id int64
+		query := `INSERT INTO artifacts (wayback_id, kind, path, remote, size, sha256) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+		err := tx.QueryRowContext(ctx, query, wayback_id, a.Kind, a.Path, a.Remote, a.Size, a.SHA256).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("store: unable to create artifact: %v", err)
+		}
+		for _, l := range a.Locations {
+			query = `INSERT INTO artifact_locations (artifact_id, store, url) VALUES ($1, $2, $3)`
+			if _, err = tx.ExecContext(ctx, query, id, l.Store, l.URL); err != nil {
+				return fmt.Errorf("store: unable to create artifact location: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Page returns the page metadata of the request of the id.
+func (s *Storage) Page(ctx context.Context, wayback_id int64) (*entity.Page, error) {
+	var p entity.Page
+	query := `SELECT title, byline, excerpt, site_name, summary, text FROM pages WHERE wayback_id = $1`
+	err := s.ds.QueryRowContext(ctx, query, wayback_id).Scan(&p.Title, &p.Byline, &p.Excerpt, &p.SiteName, &p.Summary, &p.Text)
+	if err == sql.ErrNoRows {
+		return nil, ErrPageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to fetch page: %v", err)
+	}
+	return &p, nil
+}
+
+// Artifacts returns the artifacts of the request of the id with their
+// locations on the artifact stores.
+func (s *Storage) Artifacts(ctx context.Context, wayback_id int64) ([]entity.Artifact, error) {
+	query := `SELECT id, kind, path, remote, size, sha256 FROM artifacts WHERE wayback_id = $1 ORDER BY id ASC`
+	rows, err := s.ds.QueryContext(ctx, query, wayback_id)
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to query artifacts: %v", err)
+	}
+	defer rows.Close()
+
+	artifacts := []entity.Artifact{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var a entity.Artifact
+		if err = rows.Scan(&id, &a.Kind, &a.Path, &a.Remote, &a.Size, &a.SHA256); err != nil {
+			return nil, fmt.Errorf("store: unable to fetch artifact row: %v", err)
+		}
+		index[id] = len(artifacts)
+		artifacts = append(artifacts, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: unable to fetch artifact rows: %v", err)
+	}
+
+	query = `
+		SELECT l.artifact_id, l.store, l.url FROM artifact_locations l
+		JOIN artifacts a ON a.id = l.artifact_id
+		WHERE a.wayback_id = $1 ORDER BY l.id ASC
+	`
+	locs, err := s.ds.QueryContext(ctx, query, wayback_id)
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to query artifact locations: %v", err)
+	}
+	defer locs.Close()
+
+	for locs.Next() {
+		var id int64
+		var l entity.Location
+		if err = locs.Scan(&id, &l.Store, &l.URL); err != nil {
+			return nil, fmt.Errorf("store: unable to fetch artifact location row: %v", err)
+		}
+		if i, ok := index[id]; ok {
+			artifacts[i].Locations = append(artifacts[i].Locations, l)
+		}
+	}
+	return artifacts, locs.Err()
+}
+
+// ForgetArtifacts forgets the local files of the paths removed from the disk,
+// the artifacts stored on the artifact stores only lose their paths, while
+// the others are deleted. It returns the number of the artifacts changed.
+func (s *Storage) ForgetArtifacts(ctx context.Context, paths []string) (int64, error) {
+	tx, err := s.ds.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("store: unable to begin transaction: %v", err)
+	}
+	rollback := func(err error) error {
+		if e := tx.Rollback(); e != nil {
+			return fmt.Errorf("store: unable to rollback transaction: %v", e)
+		}
+		return err
+	}
+
+	var n int64
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		for _, query := range []string{
+			`DELETE FROM artifacts WHERE path = $1 AND remote = '' AND NOT EXISTS (SELECT 1 FROM artifact_locations l WHERE l.artifact_id = artifacts.id)`,
+			`UPDATE artifacts SET path = '' WHERE path = $1`,
+		} {
+			res, err := tx.ExecContext(ctx, query, path)
+			if err != nil {
+				return 0, rollback(fmt.Errorf("store: unable to forget artifact: %v", err))
+			}
+			affected, _ := res.RowsAffected()
+			n += affected
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("store: unable to commit transaction: %v", err)
+	}
+	return n, nil
+}
@@ -7,30 +7,68 @@ package storage // import "github.com/wabarc/wayback/storage"
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wabarc/wayback/config"
 
 	_ "github.com/lib/pq"
 	bolt "go.etcd.io/bbolt"
+	"modernc.org/sqlite"
 )
 
+// lockTimeout is the time to wait for the lock of the bolt database, which
+// is held by the running service.
+const lockTimeout = 5 * time.Second
+
 // Open open a bolt database on current directory in given path.
 // It is the caller's responsibility to close it.
 func Open(opts *config.Options, path string) (*bolt.DB, error) {
 	if path == "" {
 		path = opts.BoltPathname()
 	}
-	db, err := bolt.Open(path, 0600, nil)
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: lockTimeout})
 	if err != nil {
 		return nil, fmt.Errorf("open bolt database failed: %v", err)
 	}
 	return db, nil
 }
 
-// NewConnectionPool configures the database connection pool.
+// Names of the supported database drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// sqliteSchemes holds the schemes of the database URL that select SQLite,
+// the rest of the URL is the path of the database file.
+var sqliteSchemes = []string{"sqlite3://", "sqlite://", "sqlite3:", "sqlite:"}
+
+// sqlitePragmas are applied to each connection of the SQLite database,
+// the timestamps are stored in a sortable format.
+const sqlitePragmas = "_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_time_format=sqlite"
+
+// ParseDSN returns the driver name and the data source name of the database
+// URL, e.g. sqlite:///var/lib/wayback/wayback.db for SQLite, otherwise the
+// URL is a connection string of Postgres.
+func ParseDSN(dsn string) (driver, source string) {
+	for _, scheme := range sqliteSchemes {
+		if !strings.HasPrefix(dsn, scheme) {
+			continue
+		}
+		path := strings.TrimPrefix(dsn, scheme)
+		if strings.Contains(path, "?") {
+			return DriverSQLite, "file:" + path + "&" + sqlitePragmas
+		}
+		return DriverSQLite, "file:" + path + "?" + sqlitePragmas
+	}
+	return DriverPostgres, dsn
+}
+
+// NewConnectionPool configures the database connection pool, the driver is
+// selected by the scheme of the dsn, see ParseDSN.
 func NewConnectionPool(dsn string, minConnections, maxConnections int, connectionLifetime time.Duration) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open(ParseDSN(dsn))
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +83,8 @@ func NewConnectionPool(dsn string, minConnections, maxConnections int, connectio
 // Migrate executes database migrations.
 // nolint: errcheck
 func Migrate(db *sql.DB) error {
+	driver := driverOf(db)
+
 	var currentVersion int
 	db.QueryRow(`SELECT version FROM schema_version`).Scan(&currentVersion)
 
@@ -56,7 +96,7 @@ func Migrate(db *sql.DB) error {
 			return fmt.Errorf("[Migration v%d] %v", newVersion, err)
 		}
 
-		if err := migrations[version](tx); err != nil {
+		if err := migrations[version](tx, driver); err != nil {
 			tx.Rollback()
 			return fmt.Errorf("[Migration v%d] %v", newVersion, err)
 		}
@@ -78,3 +118,11 @@ func Migrate(db *sql.DB) error {
 
 	return nil
 }
+
+// driverOf returns the name of the driver of the database.
+func driverOf(db *sql.DB) string {
+	if _, ok := db.Driver().(*sqlite.Driver); ok {
+		return DriverSQLite
+	}
+	return DriverPostgres
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testDatabases returns the migrated databases of the supported drivers,
+// Postgres is tested if WAYBACK_TEST_DATABASE_URL is set.
+func testDatabases(t *testing.T) map[string]*sql.DB {
+	t.Helper()
+
+	dbs := emptyDatabases(t)
+	for driver, db := range dbs {
+		if err := Migrate(db); err != nil {
+			t.Fatalf("migrate %s failed: %v", driver, err)
+		}
+	}
+	return dbs
+}
+
+// emptyDatabases returns the databases of the supported drivers without
+// tables.
+func emptyDatabases(t *testing.T) map[string]*sql.DB {
+	t.Helper()
+
+	dsns := map[string]string{
+		DriverSQLite: "sqlite://" + filepath.Join(t.TempDir(), "wayback.db"),
+	}
+	if dsn := os.Getenv("WAYBACK_TEST_DATABASE_URL"); dsn != "" {
+		dsns[DriverPostgres] = dsn
+	}
+
+	dbs := make(map[string]*sql.DB)
+	for driver, dsn := range dsns {
+		db, err := NewConnectionPool(dsn, 1, 2, time.Minute)
+		if err != nil {
+			t.Fatalf("connect to %s failed: %v", driver, err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		if driver == DriverPostgres {
+			// Start from an empty database.
+			_, err = db.Exec(`DROP TABLE IF EXISTS artifact_locations, artifacts, pages, archives, wayback, schema_version`)
+			if err != nil {
+				t.Fatalf("reset %s failed: %v", driver, err)
+			}
+		}
+		dbs[driver] = db
+	}
+	return dbs
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		dsn    string
+		driver string
+		source string
+	}{
+		{
+			dsn:    "user=postgres password=postgres dbname=wayback sslmode=disable",
+			driver: DriverPostgres,
+			source: "user=postgres password=postgres dbname=wayback sslmode=disable",
+		},
+		{
+			dsn:    "postgres://postgres@localhost/wayback?sslmode=disable",
+			driver: DriverPostgres,
+			source: "postgres://postgres@localhost/wayback?sslmode=disable",
+		},
+		{
+			dsn:    "sqlite:///var/lib/wayback/wayback.db",
+			driver: DriverSQLite,
+			source: "file:/var/lib/wayback/wayback.db?" + sqlitePragmas,
+		},
+		{
+			dsn:    "sqlite3:wayback.db?cache=shared",
+			driver: DriverSQLite,
+			source: "file:wayback.db?cache=shared&" + sqlitePragmas,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dsn, func(t *testing.T) {
+			driver, source := ParseDSN(test.dsn)
+			if driver != test.driver {
+				t.Errorf("unexpected driver, got %s instead of %s", driver, test.driver)
+			}
+			if source != test.source {
+				t.Errorf("unexpected source, got %s instead of %s", source, test.source)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	for driver, db := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			if got := driverOf(db); got != driver {
+				t.Fatalf("unexpected driver, got %s instead of %s", got, driver)
+			}
+
+			var version int
+			if err := db.QueryRow(`SELECT version FROM schema_version`).Scan(&version); err != nil {
+				t.Fatalf("query schema version failed: %v", err)
+			}
+			if version != schemaVersion {
+				t.Fatalf("unexpected schema version, got %d instead of %d", version, schemaVersion)
+			}
+
+			// Migrating again is a no-op.
+			if err := Migrate(db); err != nil {
+				t.Fatalf("migrate again failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrateLegacyArchives(t *testing.T) {
+	for driver, db := range emptyDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			// The archives of the first schema hold the errors as destinations.
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = migrations[0](tx, driver); err != nil {
+				tx.Rollback()
+				t.Fatalf("migrate first schema failed: %v", err)
+			}
+			if _, err = tx.Exec(`INSERT INTO schema_version (version) VALUES ($1)`, 1); err != nil {
+				tx.Rollback()
+				t.Fatal(err)
+			}
+			if err = tx.Commit(); err != nil {
+				t.Fatal(err)
+			}
+
+			var id int64
+			if err = db.QueryRow(`INSERT INTO wayback (source) VALUES ($1) RETURNING id`, "https://example.com/").Scan(&id); err != nil {
+				t.Fatalf("seed wayback failed: %v", err)
+			}
+			legacy := map[string]string{
+				"ia": "https://web.archive.org/web/2025/https://example.com/",
+				"is": "Archive failed.",
+				"ip": "",
+			}
+			for slot, dest := range legacy {
+				if _, err = db.Exec(`INSERT INTO archives (wayback_id, slot, dest) VALUES ($1, $2, $3)`, id, slot, dest); err != nil {
+					t.Fatalf("seed archives failed: %v", err)
+				}
+			}
+
+			if err = Migrate(db); err != nil {
+				t.Fatalf("migrate legacy archives failed: %v", err)
+			}
+
+			expected := map[string][3]string{
+				"ia": {"https://web.archive.org/web/2025/https://example.com/", "success", ""},
+				"is": {"", "failed", "Archive failed."},
+				"ip": {"", "failed", ""},
+			}
+			rows, err := db.Query(`SELECT slot, dest, status, error FROM archives`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var slot, dest, status, errMsg string
+				if err = rows.Scan(&slot, &dest, &status, &errMsg); err != nil {
+					t.Fatal(err)
+				}
+				if got := [3]string{dest, status, errMsg}; got != expected[slot] {
+					t.Errorf("unexpected archive of slot %s, got %q instead of %q", slot, got, expected[slot])
+				}
+			}
+			if err = rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
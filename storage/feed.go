@@ -0,0 +1,106 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Unseen returns the items of the feed which have not been marked as seen,
+// in the order of the items.
+func (s *Storage) Unseen(feed string, items []string) ([]string, error) {
+	unseen := []string{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var b *bolt.Bucket
+		if root := tx.Bucket(helper.String2Byte(entity.EntityFeed)); root != nil {
+			b = root.Bucket(helper.String2Byte(feed))
+		}
+		for _, item := range items {
+			if b == nil || b.Get(helper.String2Byte(item)) == nil {
+				unseen = append(unseen, item)
+			}
+		}
+		return nil
+	})
+
+	return unseen, err
+}
+
+// MarkSeen marks the items of the feed as seen, the time first seen of each
+// item is kept.
+func (s *Storage) MarkSeen(feed string, items []string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	now := helper.String2Byte(time.Now().UTC().Format(time.RFC3339))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(helper.String2Byte(entity.EntityFeed))
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists(helper.String2Byte(feed))
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			key := helper.String2Byte(item)
+			if b.Get(key) != nil {
+				continue
+			}
+			if err = b.Put(key, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PruneSeen forgets the items of the feed seen before the time which are no
+// longer listed by the items of the feed, so that the seen items of a feed
+// are bounded by its size. It returns the number of the items forgotten.
+func (s *Storage) PruneSeen(feed string, items []string, before time.Time) (int, error) {
+	listed := make(map[string]bool, len(items))
+	for _, item := range items {
+		listed[item] = true
+	}
+
+	var n int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var b *bolt.Bucket
+		if root := tx.Bucket(helper.String2Byte(entity.EntityFeed)); root != nil {
+			b = root.Bucket(helper.String2Byte(feed))
+		}
+		if b == nil {
+			return nil
+		}
+
+		var stale [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			seen, err := time.Parse(time.RFC3339, string(v))
+			if (err == nil && seen.After(before)) || listed[string(k)] {
+				return nil
+			}
+			stale = append(stale, k)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Keys must not be deleted while iterating.
+		for _, k := range stale {
+			if err = b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(stale)
+		return nil
+	})
+
+	return n, err
+}
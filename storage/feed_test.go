@@ -0,0 +1,56 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+)
+
+func TestFeedSeen(t *testing.T) {
+	db, err := Open(&config.Options{}, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s := NewStorage(nil, db)
+	defer s.Close()
+
+	feed := "https://example.com/feed.xml"
+	items := []string{"https://example.com/posts/1", "https://example.com/posts/2"}
+	unseen, err := s.Unseen(feed, items)
+	if err != nil || !reflect.DeepEqual(unseen, items) {
+		t.Fatalf("unexpected unseen items %v, error: %v", unseen, err)
+	}
+
+	if err = s.MarkSeen(feed, items[:1]); err != nil {
+		t.Fatalf("unexpected mark seen: %v", err)
+	}
+	unseen, err = s.Unseen(feed, items)
+	if err != nil || !reflect.DeepEqual(unseen, items[1:]) {
+		t.Fatalf("unexpected unseen items %v, error: %v", unseen, err)
+	}
+
+	// The items are tracked per feed.
+	unseen, err = s.Unseen("https://example.org/feed.xml", items)
+	if err != nil || len(unseen) != 2 {
+		t.Fatalf("unexpected unseen items of another feed %v, error: %v", unseen, err)
+	}
+
+	// The items no longer listed are forgotten once they are stale.
+	if n, err := s.PruneSeen(feed, items[1:], time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("unexpected pruned %d fresh items, error: %v", n, err)
+	}
+	if n, err := s.PruneSeen(feed, items[1:], time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("unexpected pruned %d stale items, error: %v", n, err)
+	}
+	unseen, err = s.Unseen(feed, items)
+	if err != nil || !reflect.DeepEqual(unseen, items) {
+		t.Fatalf("unexpected unseen items %v, error: %v", unseen, err)
+	}
+}
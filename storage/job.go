@@ -0,0 +1,131 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// CreateJob persists a job, the id of the job is generated.
+func (s *Storage) CreateJob(job *entity.Job) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(helper.String2Byte(entity.EntityJob))
+		if err != nil {
+			return err
+		}
+		id, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("generate id for job failed: %w", err)
+		}
+
+		now := time.Now().UTC()
+		job.ID = id
+		job.Created = now
+		job.Updated = now
+		if job.State == "" {
+			job.State = entity.JobQueued
+		}
+		buf, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(itob(job.ID), buf)
+	})
+}
+
+// UpdateJob updates the state and error of a job.
+func (s *Storage) UpdateJob(job *entity.Job) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityJob))
+		if b == nil || b.Get(itob(job.ID)) == nil {
+			return fmt.Errorf("job %d not found", job.ID)
+		}
+
+		job.Updated = time.Now().UTC()
+		buf, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(itob(job.ID), buf)
+	})
+}
+
+// NextJobID returns the next id of the jobs, which is shared by the jobs
+// persisted and the ones kept in memory only.
+func (s *Storage) NextJobID() (id uint64, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(helper.String2Byte(entity.EntityJob))
+		if err != nil {
+			return err
+		}
+		id, err = b.NextSequence()
+		return err
+	})
+	return id, err
+}
+
+// DeleteJob deletes the job of the given id.
+func (s *Storage) DeleteJob(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityJob))
+		if b == nil {
+			return nil
+		}
+		return b.Delete(itob(id))
+	})
+}
+
+// Job returns the job of the given id.
+func (s *Storage) Job(id uint64) (*entity.Job, error) {
+	var job entity.Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityJob))
+		if b == nil {
+			return fmt.Errorf("job %d not found", id)
+		}
+		v := b.Get(itob(id))
+		if v == nil {
+			return fmt.Errorf("job %d not found", id)
+		}
+		return json.Unmarshal(v, &job)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// PendingJobs returns the unfinished jobs of the given kind ordered by id,
+// the running jobs are included since they are interrupted.
+func (s *Storage) PendingJobs(kind string) ([]*entity.Job, error) {
+	var jobs []*entity.Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityJob))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var job entity.Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return err
+			}
+			if job.Kind == kind && !job.Finished() {
+				jobs = append(jobs, &job)
+			}
+			return nil
+		})
+	})
+
+	return jobs, err
+}
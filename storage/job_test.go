@@ -0,0 +1,67 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"path"
+	"testing"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+)
+
+func TestJob(t *testing.T) {
+	db, err := Open(&config.Options{}, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s := NewStorage(nil, db)
+	defer s.Close()
+
+	jobs, err := s.PendingJobs("telegram")
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("unexpected pending jobs: %v, %v", jobs, err)
+	}
+
+	for _, kind := range []string{"telegram", "telegram", "discord"} {
+		if err := s.CreateJob(&entity.Job{Kind: kind, Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("unexpected create job: %v", err)
+		}
+	}
+
+	job, err := s.Job(1)
+	if err != nil {
+		t.Fatalf("unexpected query job: %v", err)
+	}
+	if job.State != entity.JobQueued || job.Kind != "telegram" || string(job.Payload) != `{}` {
+		t.Fatalf("unexpected job: %#v", job)
+	}
+
+	job.State = entity.JobDone
+	if err := s.UpdateJob(job); err != nil {
+		t.Fatalf("unexpected update job: %v", err)
+	}
+	if err := s.UpdateJob(&entity.Job{ID: 100}); err == nil {
+		t.Error("unexpected update job not exists")
+	}
+
+	jobs, err = s.PendingJobs("telegram")
+	if err != nil {
+		t.Fatalf("unexpected pending jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != 2 {
+		t.Errorf("unexpected pending jobs: %v", jobs)
+	}
+
+	if err = s.DeleteJob(2); err != nil {
+		t.Fatalf("unexpected delete job: %v", err)
+	}
+	if _, err = s.Job(2); err == nil {
+		t.Error("unexpected job deleted")
+	}
+	if jobs, err = s.PendingJobs("telegram"); err != nil || len(jobs) != 0 {
+		t.Errorf("unexpected pending jobs: %v, %v", jobs, err)
+	}
+}
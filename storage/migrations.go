@@ -11,8 +11,9 @@ import (
 var schemaVersion = len(migrations)
 
 // Order is important. Add new migrations at the end of the list.
-var migrations = []func(tx *sql.Tx) error{
-	func(tx *sql.Tx) (err error) {
+// The driver is one of DriverPostgres and DriverSQLite.
+var migrations = []func(tx *sql.Tx, driver string) error{
+	func(tx *sql.Tx, driver string) (err error) {
 		sql := `
 			CREATE TABLE schema_version (
 				version text not null
@@ -34,6 +35,143 @@ var migrations = []func(tx *sql.Tx) error{
 				foreign key (wayback_id) references wayback(id) on delete cascade
 			);
 		`
+		if driver == DriverSQLite {
+			sql = `
+				CREATE TABLE schema_version (
+					version text not null
+				);
+
+				CREATE TABLE wayback (
+					id integer primary key autoincrement,
+					source text not null,
+					created_at datetime not null default current_timestamp
+				);
+
+				CREATE TABLE archives (
+					id integer primary key autoincrement,
+					wayback_id integer not null,
+					slot varchar(255) not null default '',
+					dest text not null default '',
+					foreign key (wayback_id) references wayback(id) on delete cascade
+				);
+			`
+		}
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx, _ string) (err error) {
+		sql := `
+			ALTER TABLE archives ADD COLUMN status varchar(32) not null default 'success';
+			ALTER TABLE archives ADD COLUMN error text not null default '';
+			ALTER TABLE archives ADD COLUMN elapsed bigint not null default 0;
+			ALTER TABLE archives ADD COLUMN attempts integer not null default 0;
+
+			-- The destinations of the failed archives held the errors before.
+			UPDATE archives SET status = 'failed', error = dest, dest = ''
+			WHERE dest NOT LIKE 'http://%' AND dest NOT LIKE 'https://%';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx, driver string) (err error) {
+		sql := `ALTER TABLE wayback ADD COLUMN domain varchar(255) not null default '';`
+		if driver == DriverPostgres {
+			// SQLite databases are created after the domain column is introduced.
+			sql += `
+				UPDATE wayback SET domain = lower(coalesce(substring(source from '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^/?#@]*@)?([^/?#:]+)'), ''));
+			`
+		}
+		sql += `
+			CREATE INDEX wayback_domain_idx ON wayback (domain);
+			CREATE INDEX wayback_source_idx ON wayback (source);
+			CREATE INDEX wayback_created_at_idx ON wayback (created_at);
+			CREATE INDEX archives_wayback_id_idx ON archives (wayback_id);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx, driver string) (err error) {
+		id := `id bigserial not null primary key`
+		if driver == DriverSQLite {
+			id = `id integer primary key autoincrement`
+		}
+		sql := `
+			CREATE TABLE pages (
+				wayback_id bigint not null,
+				title text not null default '',
+				byline text not null default '',
+				excerpt text not null default '',
+				site_name text not null default '',
+				summary text not null default '',
+				text text not null default '',
+				primary key (wayback_id),
+				foreign key (wayback_id) references wayback(id) on delete cascade
+			);
+
+			CREATE TABLE artifacts (
+				` + id + `,
+				wayback_id bigint not null,
+				kind varchar(32) not null,
+				path text not null default '',
+				remote text not null default '',
+				size bigint not null default 0,
+				sha256 varchar(64) not null default '',
+				foreign key (wayback_id) references wayback(id) on delete cascade
+			);
+
+			CREATE INDEX artifacts_wayback_id_idx ON artifacts (wayback_id);
+			CREATE INDEX artifacts_sha256_idx ON artifacts (sha256);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx, driver string) (err error) {
+		sql := `
+			ALTER TABLE pages ADD COLUMN search tsvector GENERATED ALWAYS AS (
+				to_tsvector('simple', title || ' ' || summary || ' ' || text)
+			) STORED;
+
+			CREATE INDEX pages_search_idx ON pages USING gin (search);
+		`
+		if driver == DriverSQLite {
+			sql = `
+				CREATE VIRTUAL TABLE pages_fts USING fts5(title, summary, text);
+
+				CREATE TRIGGER pages_fts_insert AFTER INSERT ON pages BEGIN
+					INSERT INTO pages_fts (rowid, title, summary, text) VALUES (new.wayback_id, new.title, new.summary, new.text);
+				END;
+				CREATE TRIGGER pages_fts_update AFTER UPDATE ON pages BEGIN
+					UPDATE pages_fts SET title = new.title, summary = new.summary, text = new.text WHERE rowid = old.wayback_id;
+				END;
+				CREATE TRIGGER pages_fts_delete AFTER DELETE ON pages BEGIN
+					DELETE FROM pages_fts WHERE rowid = old.wayback_id;
+				END;
+
+				INSERT INTO pages_fts (rowid, title, summary, text) SELECT wayback_id, title, summary, text FROM pages;
+			`
+		}
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx, driver string) (err error) {
+		id := `id bigserial not null primary key`
+		if driver == DriverSQLite {
+			id = `id integer primary key autoincrement`
+		}
+		sql := `
+			CREATE TABLE artifact_locations (
+				` + id + `,
+				artifact_id bigint not null,
+				store varchar(32) not null,
+				url text not null,
+				foreign key (artifact_id) references artifacts(id) on delete cascade
+			);
+
+			CREATE INDEX artifact_locations_artifact_id_idx ON artifact_locations (artifact_id);
+
+			INSERT INTO artifact_locations (artifact_id, store, url)
+				SELECT id, 'catbox', remote FROM artifacts WHERE remote <> '';
+		`
 		_, err = tx.Exec(sql)
 		return err
 	},
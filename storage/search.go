@@ -0,0 +1,178 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wabarc/wayback/entity"
+)
+
+// snippetLen is the maximum number of characters of the snippets around
+// the matched words of the search hits.
+const snippetLen = 200
+
+// matchQuery returns the full-text query of the words for the driver, or an
+// empty string if no words are given. Postgres parses the words with
+// plainto_tsquery, and each word is quoted as a phrase of SQLite FTS5, so
+// the operators in the words are matched literally.
+func matchQuery(driver, query string) string {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return ""
+	}
+	if driver != DriverSQLite {
+		return strings.Join(words, " ")
+	}
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// searchCond returns the condition on the wayback table matching the
+// full-text query given by a placeholder.
+func searchCond(driver string) string {
+	if driver == DriverSQLite {
+		return "id IN (SELECT rowid FROM pages_fts WHERE pages_fts MATCH %s)"
+	}
+	return "id IN (SELECT wayback_id FROM pages WHERE search @@ plainto_tsquery('simple', %s))"
+}
+
+// Search returns the requests whose pages matched the full-text query of the
+// filter in order of relevance, the page of each hit holds the title and the
+// summary without the text.
+func (s *Storage) Search(ctx context.Context, f WaybackFilter) ([]entity.SearchHit, error) {
+	driver := driverOf(s.ds)
+	match := matchQuery(driver, f.Query)
+	if match == "" {
+		return nil, fmt.Errorf("store: query missing")
+	}
+
+	where, args := f.where(driver)
+	args = append(args, match)
+	rank := fmt.Sprintf("ts_rank(p.search, plainto_tsquery('simple', $%d)) DESC", len(args))
+	join := ""
+	if driver == DriverSQLite {
+		join = fmt.Sprintf("JOIN pages_fts ON pages_fts.rowid = w.id AND pages_fts MATCH $%d", len(args))
+		rank = "bm25(pages_fts)"
+	}
+	page := ""
+	if f.Limit > 0 {
+		args = append(args, f.Limit, f.Offset)
+		page = fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+	query := fmt.Sprintf(`
+		SELECT w.id, w.source, w.domain, w.created_at, p.title, p.summary, p.text
+		FROM (SELECT id, source, domain, created_at FROM wayback %s) w
+		JOIN pages p ON p.wayback_id = w.id
+		%s
+		ORDER BY %s, w.id DESC
+		%s
+	`, where, join, rank, page)
+	rows, err := s.ds.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to search pages: %v", err)
+	}
+	defer rows.Close()
+
+	hits := []entity.SearchHit{}
+	for rows.Next() {
+		var hit entity.SearchHit
+		var page entity.Page
+		err = rows.Scan(&hit.ID, &hit.Source, &hit.Domain, &hit.Created, &page.Title, &page.Summary, &page.Text)
+		if err != nil {
+			return nil, fmt.Errorf("store: unable to fetch search row: %v", err)
+		}
+		hit.Snippet = snippet(page.Text, strings.Fields(f.Query))
+		page.Text = ""
+		hit.Page = &page
+		hit.Archives = []entity.Archive{}
+		hits = append(hits, hit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hits, s.fillArchives(ctx, hits)
+}
+
+// fillArchives loads the archives of the hits.
+func (s *Storage) fillArchives(ctx context.Context, hits []entity.SearchHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	index := make(map[int64]*entity.SearchHit, len(hits))
+	params := make([]string, len(hits))
+	args := make([]interface{}, len(hits))
+	for i := range hits {
+		index[hits[i].ID] = &hits[i]
+		params[i] = "$" + strconv.Itoa(i+1)
+		args[i] = hits[i].ID
+	}
+
+	query := `SELECT wayback_id, slot, dest, status, error, elapsed, attempts FROM archives WHERE wayback_id IN (` + strings.Join(params, ", ") + `) ORDER BY id ASC`
+	rows, err := s.ds.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("store: unable to query archives: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var a entity.Archive
+		if err = rows.Scan(&id, &a.Slot, &a.Dest, &a.Status, &a.Error, &a.Elapsed, &a.Attempts); err != nil {
+			return fmt.Errorf("store: unable to fetch archive row: %v", err)
+		}
+		if hit, ok := index[id]; ok {
+			hit.Archives = append(hit.Archives, a)
+		}
+	}
+	return rows.Err()
+}
+
+// snippet returns the part of the text around the first matched word, or the
+// beginning of the text if no words matched, the whitespaces are collapsed.
+func snippet(text string, words []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+
+	at := -1
+	for _, word := range words {
+		// The offsets are not applicable if lowercasing changed the length.
+		i := strings.Index(lower, strings.ToLower(word))
+		if i >= 0 && len(lower) == len(text) && (at < 0 || i < at) {
+			at = i
+		}
+	}
+
+	start := 0
+	if at > 0 {
+		// Keep a quarter of the snippet before the matched word.
+		start = at
+		for n := 0; start > 0 && n < snippetLen/4; n++ {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+	}
+	end := start
+	for n := 0; end < len(text) && n < snippetLen; n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	s := text[start:end]
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(text) {
+		s += "…"
+	}
+	return s
+}
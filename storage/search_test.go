@@ -0,0 +1,123 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/entity"
+)
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("lorem ipsum ", 50)
+
+	tests := []struct {
+		name  string
+		text  string
+		words []string
+		want  string
+	}{
+		{"short text", "Hello,\n  world", []string{"world"}, "Hello, world"},
+		{"no match", "Hello world", []string{"foo"}, "Hello world"},
+		{"match at the end", long + "Wayback", []string{"wayback"}, "…" + long[len(long)-snippetLen/4:] + "Wayback"},
+		{"match at the beginning", "Wayback " + long, []string{"WAYBACK"}, ("Wayback " + long)[:snippetLen] + "…"},
+		{"multibyte", strings.Repeat("档案", 200), []string{"案"}, strings.Repeat("档案", 100) + "…"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := snippet(test.text, test.words); got != test.want {
+				t.Errorf("unexpected snippet, got %q instead of %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+
+	for driver, db := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			s := NewStorage(db, nil)
+
+			pages := map[string]*entity.Page{
+				"https://example.com/go":     {Title: "The Go Programming Language", Text: "Go is an open source programming language."},
+				"https://example.org/rust":   {Title: "Rust", Summary: "A language empowering everyone.", Text: "Build reliable and efficient software."},
+				"https://www.example.com/wb": {Title: "Wayback", Text: "A toolkit for snapshot webpages to the Internet Archive (and more)."},
+			}
+			for source, page := range pages {
+				cols := []wayback.Collect{{Arc: "ia", Src: source, Dst: "https://web.archive.org/web/" + source, Status: wayback.StatusSuccess}}
+				if err := s.CreateWayback(ctx, cols, nil); err != nil {
+					t.Fatalf("create wayback failed: %v", err)
+				}
+				waybacks, err := s.Waybacks(ctx, WaybackFilter{Source: source})
+				if err != nil {
+					t.Fatalf("query wayback failed: %v", err)
+				}
+				tx, err := db.Begin()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = createBundle(ctx, tx, waybacks[0].ID, page, nil); err != nil {
+					t.Fatalf("create bundle failed: %v", err)
+				}
+				if err = tx.Commit(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tests := []struct {
+				name    string
+				filter  WaybackFilter
+				sources []string
+			}{
+				{"title", WaybackFilter{Query: "rust"}, []string{"https://example.org/rust"}},
+				{"summary", WaybackFilter{Query: "Empowering"}, []string{"https://example.org/rust"}},
+				{"all words", WaybackFilter{Query: "language open"}, []string{"https://example.com/go"}},
+				{"operators", WaybackFilter{Query: `(and OR "more")`}, []string{}},
+				{"domain", WaybackFilter{Query: "language", Domain: "example.org"}, []string{"https://example.org/rust"}},
+				{"no match", WaybackFilter{Query: "python"}, []string{}},
+			}
+			for _, test := range tests {
+				t.Run(test.name, func(t *testing.T) {
+					hits, err := s.Search(ctx, test.filter)
+					if err != nil {
+						t.Fatalf("search failed: %v", err)
+					}
+					sources := []string{}
+					for _, hit := range hits {
+						sources = append(sources, hit.Source)
+						if hit.Page == nil || hit.Page.Title == "" || hit.Page.Text != "" || len(hit.Archives) != 1 {
+							t.Fatalf("unexpected hit: %+v", hit)
+						}
+					}
+					if !reflect.DeepEqual(sources, test.sources) {
+						t.Fatalf("unexpected hits, got %v instead of %v", sources, test.sources)
+					}
+
+					count, err := s.CountWaybacks(ctx, test.filter)
+					if err != nil {
+						t.Fatalf("count hits failed: %v", err)
+					}
+					if count != len(test.sources) {
+						t.Fatalf("unexpected count, got %d instead of %d", count, len(test.sources))
+					}
+				})
+			}
+
+			hits, err := s.Search(ctx, WaybackFilter{Query: "language", Limit: 1})
+			if err != nil || len(hits) != 1 {
+				t.Fatalf("unexpected paginated hits %+v, error: %v", hits, err)
+			}
+			if _, err = s.Search(ctx, WaybackFilter{Query: " "}); err == nil {
+				t.Fatal("unexpected search without query")
+			}
+		})
+	}
+}
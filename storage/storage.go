@@ -54,3 +54,7 @@ func itob(v uint64) []byte {
 	binary.BigEndian.PutUint64(b, v)
 	return b
 }
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
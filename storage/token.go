@@ -0,0 +1,190 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// tokenPrefix is the prefix of the secrets of tokens, it helps to
+// recognize leaked tokens.
+const tokenPrefix = "wbk_"
+
+// ErrTokenNotFound is returned if the token does not exist.
+var ErrTokenNotFound = errors.New("token not found")
+
+// tokenIndex is the bucket that maps the hashes to the ids of tokens.
+var tokenIndex = helper.String2Byte(entity.EntityToken + "_hash")
+
+// CreateToken generates a token of the given name and scopes, a zero
+// expires means the token never expires. It returns the secret of the
+// token, which is not stored and can not be retrieved later.
+func (s *Storage) CreateToken(name string, scopes []entity.Scope, expires time.Time) (string, *entity.Token, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("generate token failed: %w", err)
+	}
+	secret := tokenPrefix + hex.EncodeToString(buf)
+
+	token := &entity.Token{
+		Name:    name,
+		Hash:    hashToken(secret),
+		Scopes:  scopes,
+		Usage:   make(map[entity.Scope]uint64),
+		Created: time.Now().UTC(),
+		Expires: expires,
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(helper.String2Byte(entity.EntityToken))
+		if err != nil {
+			return err
+		}
+		idx, err := tx.CreateBucketIfNotExists(tokenIndex)
+		if err != nil {
+			return err
+		}
+		id, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("generate id for token failed: %w", err)
+		}
+		token.ID = id
+
+		if err = putToken(b, token); err != nil {
+			return err
+		}
+		return idx.Put(helper.String2Byte(token.Hash), itob(id))
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return secret, token, nil
+}
+
+// Tokens returns all tokens ordered by id.
+func (s *Storage) Tokens() ([]*entity.Token, error) {
+	tokens := []*entity.Token{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityToken))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var token entity.Token
+			if err := json.Unmarshal(v, &token); err != nil {
+				return err
+			}
+			tokens = append(tokens, &token)
+			return nil
+		})
+	})
+
+	return tokens, err
+}
+
+// DeleteToken revokes the token of the given id.
+func (s *Storage) DeleteToken(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityToken))
+		if b == nil {
+			return ErrTokenNotFound
+		}
+		token, err := getToken(b, id)
+		if err != nil {
+			return err
+		}
+		if idx := tx.Bucket(tokenIndex); idx != nil {
+			if err = idx.Delete(helper.String2Byte(token.Hash)); err != nil {
+				return err
+			}
+		}
+		return b.Delete(itob(id))
+	})
+}
+
+// AuthenticateToken returns the token of the given secret, expired tokens
+// are treated as not found.
+func (s *Storage) AuthenticateToken(secret string) (*entity.Token, error) {
+	var token *entity.Token
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityToken))
+		idx := tx.Bucket(tokenIndex)
+		if b == nil || idx == nil {
+			return ErrTokenNotFound
+		}
+		id := idx.Get(helper.String2Byte(hashToken(secret)))
+		if id == nil {
+			return ErrTokenNotFound
+		}
+
+		var err error
+		token, err = getToken(b, btoi(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token.Expired(time.Now()) {
+		return nil, ErrTokenNotFound
+	}
+
+	return token, nil
+}
+
+// UseToken accounts a request of the scope to the token of the given id.
+func (s *Storage) UseToken(id uint64, scope entity.Scope) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityToken))
+		if b == nil {
+			return ErrTokenNotFound
+		}
+		token, err := getToken(b, id)
+		if err != nil {
+			return err
+		}
+		if token.Usage == nil {
+			token.Usage = make(map[entity.Scope]uint64)
+		}
+		token.Usage[scope]++
+		token.LastUsed = time.Now().UTC()
+
+		return putToken(b, token)
+	})
+}
+
+func getToken(b *bolt.Bucket, id uint64) (*entity.Token, error) {
+	v := b.Get(itob(id))
+	if v == nil {
+		return nil, ErrTokenNotFound
+	}
+	var token entity.Token
+	if err := json.Unmarshal(v, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func putToken(b *bolt.Bucket, token *entity.Token) error {
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return b.Put(itob(token.ID), buf)
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256(helper.String2Byte(secret))
+	return hex.EncodeToString(sum[:])
+}
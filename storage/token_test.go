@@ -0,0 +1,87 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+)
+
+func TestToken(t *testing.T) {
+	db, err := Open(&config.Options{}, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s := NewStorage(nil, db)
+	defer s.Close()
+
+	if _, err = s.AuthenticateToken("foo"); err != ErrTokenNotFound {
+		t.Fatalf("unexpected authenticate token without tokens: %v", err)
+	}
+
+	secret, token, err := s.CreateToken("partner", []entity.Scope{entity.ScopeArchive}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected create token: %v", err)
+	}
+	if !strings.HasPrefix(secret, tokenPrefix) || token.ID != 1 || token.Hash == "" || strings.Contains(token.Hash, secret) {
+		t.Fatalf("unexpected token: %s, %#v", secret, token)
+	}
+
+	got, err := s.AuthenticateToken(secret)
+	if err != nil {
+		t.Fatalf("unexpected authenticate token: %v", err)
+	}
+	if got.Name != "partner" || !got.Allows(entity.ScopeArchive) || got.Allows(entity.ScopeReadHistory) {
+		t.Fatalf("unexpected authenticated token: %#v", got)
+	}
+	if _, err = s.AuthenticateToken(secret + "x"); err != ErrTokenNotFound {
+		t.Fatalf("unexpected authenticate wrong secret: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err = s.UseToken(token.ID, entity.ScopeArchive); err != nil {
+			t.Fatalf("unexpected use token: %v", err)
+		}
+	}
+	tokens, err := s.Tokens()
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("unexpected tokens: %v, %v", tokens, err)
+	}
+	if tokens[0].Usage[entity.ScopeArchive] != 3 || tokens[0].LastUsed.IsZero() {
+		t.Fatalf("unexpected usage of token: %#v", tokens[0])
+	}
+
+	if err = s.DeleteToken(token.ID); err != nil {
+		t.Fatalf("unexpected delete token: %v", err)
+	}
+	if _, err = s.AuthenticateToken(secret); err != ErrTokenNotFound {
+		t.Fatalf("unexpected authenticate revoked token: %v", err)
+	}
+	if err = s.DeleteToken(token.ID); err != ErrTokenNotFound {
+		t.Fatalf("unexpected delete revoked token: %v", err)
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	db, err := Open(&config.Options{}, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s := NewStorage(nil, db)
+	defer s.Close()
+
+	secret, _, err := s.CreateToken("expired", []entity.Scope{entity.ScopeAdmin}, time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatalf("unexpected create token: %v", err)
+	}
+	if _, err = s.AuthenticateToken(secret); err != ErrTokenNotFound {
+		t.Fatalf("unexpected authenticate expired token: %v", err)
+	}
+}
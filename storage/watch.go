@@ -0,0 +1,119 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wabarc/helper"
+	"github.com/wabarc/wayback/entity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrWatchNotFound is returned if the watch does not exist.
+var ErrWatchNotFound = errors.New("watch not found")
+
+// CreateWatch stores the watch and assigns its id.
+func (s *Storage) CreateWatch(w *entity.Watch) error {
+	if w.Created.IsZero() {
+		w.Created = time.Now().UTC()
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(helper.String2Byte(entity.EntityWatch))
+		if err != nil {
+			return err
+		}
+		id, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("generate id for watch failed: %w", err)
+		}
+		w.ID = id
+
+		return putWatch(b, w)
+	})
+}
+
+// Watch returns the watch of the given id.
+func (s *Storage) Watch(id uint64) (*entity.Watch, error) {
+	var w *entity.Watch
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityWatch))
+		if b == nil {
+			return ErrWatchNotFound
+		}
+		var err error
+		w, err = getWatch(b, id)
+		return err
+	})
+
+	return w, err
+}
+
+// Watches returns all watches ordered by id.
+func (s *Storage) Watches() ([]*entity.Watch, error) {
+	watches := []*entity.Watch{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityWatch))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var w entity.Watch
+			if err := json.Unmarshal(v, &w); err != nil {
+				return err
+			}
+			watches = append(watches, &w)
+			return nil
+		})
+	})
+
+	return watches, err
+}
+
+// UpdateWatch updates the stored watch, it returns ErrWatchNotFound if the
+// watch has been deleted.
+func (s *Storage) UpdateWatch(w *entity.Watch) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityWatch))
+		if b == nil || b.Get(itob(w.ID)) == nil {
+			return ErrWatchNotFound
+		}
+		return putWatch(b, w)
+	})
+}
+
+// DeleteWatch deletes the watch of the given id.
+func (s *Storage) DeleteWatch(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(helper.String2Byte(entity.EntityWatch))
+		if b == nil || b.Get(itob(id)) == nil {
+			return ErrWatchNotFound
+		}
+		return b.Delete(itob(id))
+	})
+}
+
+func getWatch(b *bolt.Bucket, id uint64) (*entity.Watch, error) {
+	v := b.Get(itob(id))
+	if v == nil {
+		return nil, ErrWatchNotFound
+	}
+	var w entity.Watch
+	if err := json.Unmarshal(v, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func putWatch(b *bolt.Bucket, w *entity.Watch) error {
+	buf, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return b.Put(itob(w.ID), buf)
+}
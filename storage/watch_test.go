@@ -0,0 +1,64 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package storage // import "github.com/wabarc/wayback/storage"
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/entity"
+)
+
+func TestWatch(t *testing.T) {
+	db, err := Open(&config.Options{}, path.Join(t.TempDir(), "bolt.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	s := NewStorage(nil, db)
+	defer s.Close()
+
+	if _, err = s.Watch(1); err != ErrWatchNotFound {
+		t.Fatalf("unexpected watch without watches: %v", err)
+	}
+
+	next := time.Now().Add(time.Hour).UTC()
+	w := &entity.Watch{URL: "https://example.com/", Schedule: "@daily", Owner: "telegram:1", NextRun: next}
+	if err = s.CreateWatch(w); err != nil {
+		t.Fatalf("unexpected create watch: %v", err)
+	}
+	if w.ID != 1 || w.Created.IsZero() {
+		t.Fatalf("unexpected created watch: %#v", w)
+	}
+
+	w.Runs++
+	w.LastError = "timeout"
+	if err = s.UpdateWatch(w); err != nil {
+		t.Fatalf("unexpected update watch: %v", err)
+	}
+	got, err := s.Watch(w.ID)
+	if err != nil {
+		t.Fatalf("unexpected watch: %v", err)
+	}
+	if got.URL != w.URL || got.Runs != 1 || got.LastError != "timeout" || !got.NextRun.Equal(next) {
+		t.Fatalf("unexpected watch: %#v", got)
+	}
+
+	watches, err := s.Watches()
+	if err != nil || len(watches) != 1 {
+		t.Fatalf("unexpected watches: %v, %v", watches, err)
+	}
+
+	if err = s.DeleteWatch(w.ID); err != nil {
+		t.Fatalf("unexpected delete watch: %v", err)
+	}
+	if err = s.DeleteWatch(w.ID); err != ErrWatchNotFound {
+		t.Fatalf("unexpected delete deleted watch: %v", err)
+	}
+	if err = s.UpdateWatch(w); err != ErrWatchNotFound {
+		t.Fatalf("unexpected update deleted watch: %v", err)
+	}
+}
@@ -7,42 +7,82 @@ package storage // import "github.com/wabarc/wayback/storage"
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/wabarc/helper"
 	"github.com/wabarc/wayback"
+	"github.com/wabarc/wayback/entity"
+	"github.com/wabarc/wayback/reduxer"
 )
 
-func (s *Storage) CreateWayback(ctx context.Context, cols []wayback.Collect) error {
+// ErrWaybackNotFound is returned when no request matched in the history.
+var ErrWaybackNotFound = errors.New("wayback not found")
+
+// CreateWayback stores the given cols into the database, one request per
+// source URL of the cols, failed slots are recorded with their status and
+// error rather than the destination. The page metadata and artifacts of the
+// source URLs are stored too if rdx holds them.
+func (s *Storage) CreateWayback(ctx context.Context, cols []wayback.Collect, rdx reduxer.Reduxer) error {
 	if len(cols) == 0 {
 		return fmt.Errorf("store: cols missing")
 	}
 
+	// Group the cols by their source URLs in order of appearance.
+	type request struct {
+		src       string
+		cols      []wayback.Collect
+		page      *entity.Page
+		artifacts []entity.Artifact
+	}
+	requests := []*request{}
+	index := make(map[string]*request)
+	for _, col := range cols {
+		req, ok := index[col.Src]
+		if !ok {
+			req = &request{src: col.Src}
+			index[col.Src] = req
+			requests = append(requests, req)
+		}
+		req.cols = append(req.cols, col)
+	}
+	// Digest the artifacts before the transaction begins, hashing large
+	// files should not hold a connection.
+	for _, req := range requests {
+		req.page, req.artifacts = bundleOf(rdx, req.src)
+	}
+
 	tx, err := s.ds.Begin()
 	if err != nil {
 		return fmt.Errorf("store: unable to begin transaction: %v", err)
 	}
-
-	var id int64
-	query := `INSERT INTO wayback (source) VALUES ($1) RETURNING id`
-	err = tx.QueryRowContext(ctx, query, cols[0].Src).Scan(&id)
-	if err != nil {
-		if err = tx.Rollback(); err != nil {
-			return fmt.Errorf("store: unable to rollback transaction: %v", err)
+	rollback := func(err error) error {
+		if e := tx.Rollback(); e != nil {
+			return fmt.Errorf("store: unable to rollback transaction: %v", e)
 		}
-		return fmt.Errorf("store: unable to create wayback: %v", err)
+		return err
 	}
 
-	for _, col := range cols {
-		if !helper.IsURL(col.Dst) {
-			continue
-		}
-		err = s.createArchives(ctx, tx, col, id)
+	now := time.Now().UTC()
+	query := `INSERT INTO wayback (source, domain, created_at) VALUES ($1, $2, $3) RETURNING id`
+	for _, req := range requests {
+		var id int64
+		err = tx.QueryRowContext(ctx, query, req.src, domainOf(req.src), now).Scan(&id)
 		if err != nil {
-			if err = tx.Rollback(); err != nil {
-				return fmt.Errorf("store: unable to rollback transaction: %v", err)
+			return rollback(fmt.Errorf("store: unable to create wayback: %v", err))
+		}
+
+		for _, col := range req.cols {
+			if err = s.createArchives(ctx, tx, col, id); err != nil {
+				return rollback(fmt.Errorf("store: create archives failed: %v", err))
 			}
-			return fmt.Errorf("store: create archives failed: %v", err)
+		}
+
+		if err = createBundle(ctx, tx, id, req.page, req.artifacts); err != nil {
+			return rollback(fmt.Errorf("store: create bundle failed: %v", err))
 		}
 	}
 
@@ -50,10 +90,207 @@ func (s *Storage) CreateWayback(ctx context.Context, cols []wayback.Collect) err
 }
 
 func (s *Storage) createArchives(ctx context.Context, tx *sql.Tx, col wayback.Collect, wayback_id int64) error {
-	query := `INSERT INTO archives (wayback_id, slot, dest) VALUES ($1, $2, $3)`
-	_, err := tx.ExecContext(ctx, query, wayback_id, col.Arc, col.Dst)
+	var dest, reason string
+	if col.Succeeded() {
+		dest = col.Dst
+	} else if col.Err != nil {
+		reason = col.Err.Error()
+	}
+
+	query := `INSERT INTO archives (wayback_id, slot, dest, status, error, elapsed, attempts) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.ExecContext(ctx, query, wayback_id, col.Arc, dest, col.Status.String(), reason, col.Elapsed.Milliseconds(), col.Attempts)
 	if err != nil {
 		return fmt.Errorf("store: unable to create archive: %v", err)
 	}
 	return nil
 }
+
+// WaybackFilter filters the archiving history, zero values are ignored.
+type WaybackFilter struct {
+	ID     int64     // Matches the request of the ID
+	Domain string    // Matches the domain and its subdomains
+	Slot   string    // Matches the requests archived to the slot
+	Status string    // Matches the requests archived with the status, e.g. success
+	Source string    // Matches the requests of the source URL
+	Query  string    // Matches the full-text of the pages, all words are required
+	Since  time.Time // Matches the requests created at or after
+	Until  time.Time // Matches the requests created before
+
+	Limit  int // Zero means unlimited
+	Offset int
+}
+
+// likeEscaper escapes the wildcards of the LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// where returns the conditions of the filter on the wayback table and
+// their arguments for the driver, the arguments are numbered from $1.
+func (f WaybackFilter) where(driver string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if f.ID != 0 {
+		conds = append(conds, "id = "+arg(f.ID))
+	}
+	if f.Domain != "" {
+		domain := strings.ToLower(strings.TrimPrefix(f.Domain, "."))
+		conds = append(conds, fmt.Sprintf(`(domain = %s OR domain LIKE %s ESCAPE '\')`, arg(domain), arg("%."+likeEscaper.Replace(domain))))
+	}
+	var archives []string
+	if f.Slot != "" {
+		archives = append(archives, "archives.slot = "+arg(f.Slot))
+	}
+	if f.Status != "" {
+		archives = append(archives, "archives.status = "+arg(f.Status))
+	}
+	if len(archives) > 0 {
+		conds = append(conds, "EXISTS (SELECT 1 FROM archives WHERE archives.wayback_id = wayback.id AND "+strings.Join(archives, " AND ")+")")
+	}
+	if f.Source != "" {
+		conds = append(conds, "source = "+arg(f.Source))
+	}
+	if match := matchQuery(driver, f.Query); match != "" {
+		conds = append(conds, fmt.Sprintf(searchCond(driver), arg(match)))
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, "created_at >= "+arg(f.Since.UTC()))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, "created_at < "+arg(f.Until.UTC()))
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// Waybacks returns the archiving history matched by the filter in reverse
+// chronological order.
+func (s *Storage) Waybacks(ctx context.Context, f WaybackFilter) ([]entity.Wayback, error) {
+	return s.waybacks(ctx, f, "DESC")
+}
+
+// Timeline returns all captures of the source URL in chronological order.
+func (s *Storage) Timeline(ctx context.Context, source string) ([]entity.Wayback, error) {
+	if source == "" {
+		return nil, fmt.Errorf("store: source missing")
+	}
+	return s.waybacks(ctx, WaybackFilter{Source: source}, "ASC")
+}
+
+// RecentWayback returns the latest request of the source URL created at or
+// after since which archived to at least one slot successfully.
+func (s *Storage) RecentWayback(ctx context.Context, source string, since time.Time) (*entity.Wayback, error) {
+	f := WaybackFilter{Source: source, Status: wayback.StatusSuccess.String(), Since: since, Limit: 1}
+	waybacks, err := s.waybacks(ctx, f, "DESC")
+	if err != nil {
+		return nil, err
+	}
+	if len(waybacks) == 0 {
+		return nil, ErrWaybackNotFound
+	}
+	return &waybacks[0], nil
+}
+
+// LastCapture returns the latest request of the source URL with its page
+// metadata and artifacts, the requests without page metadata are skipped.
+func (s *Storage) LastCapture(ctx context.Context, source string) (*entity.Wayback, error) {
+	var id int64
+	query := `SELECT id FROM wayback WHERE source = $1 AND id IN (SELECT wayback_id FROM pages) ORDER BY id DESC LIMIT 1`
+	err := s.ds.QueryRowContext(ctx, query, source).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, ErrWaybackNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to query last capture: %v", err)
+	}
+	return s.Wayback(ctx, id)
+}
+
+// Wayback returns the request of the id with its archives, page metadata
+// and artifacts.
+func (s *Storage) Wayback(ctx context.Context, id int64) (*entity.Wayback, error) {
+	waybacks, err := s.waybacks(ctx, WaybackFilter{ID: id, Limit: 1}, "DESC")
+	if err != nil {
+		return nil, err
+	}
+	if len(waybacks) == 0 {
+		return nil, ErrWaybackNotFound
+	}
+	w := &waybacks[0]
+
+	if w.Page, err = s.Page(ctx, id); err != nil && err != ErrPageNotFound {
+		return nil, err
+	}
+	if w.Artifacts, err = s.Artifacts(ctx, id); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Storage) waybacks(ctx context.Context, f WaybackFilter, order string) ([]entity.Wayback, error) {
+	where, args := f.where(driverOf(s.ds))
+	page := ""
+	if f.Limit > 0 {
+		args = append(args, f.Limit, f.Offset)
+		page = fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+	query := fmt.Sprintf(`
+		SELECT w.id, w.source, w.domain, w.created_at,
+			COALESCE(a.slot, ''), COALESCE(a.dest, ''), COALESCE(a.status, ''),
+			COALESCE(a.error, ''), COALESCE(a.elapsed, 0), COALESCE(a.attempts, 0)
+		FROM (SELECT id, source, domain, created_at FROM wayback %[1]s ORDER BY id %[2]s %[3]s) w
+		LEFT JOIN archives a ON a.wayback_id = w.id
+		ORDER BY w.id %[2]s, a.id ASC
+	`, where, order, page)
+	rows, err := s.ds.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: unable to query waybacks: %v", err)
+	}
+	defer rows.Close()
+
+	waybacks := []entity.Wayback{}
+	for rows.Next() {
+		var w entity.Wayback
+		var a entity.Archive
+		err = rows.Scan(&w.ID, &w.Source, &w.Domain, &w.Created, &a.Slot, &a.Dest, &a.Status, &a.Error, &a.Elapsed, &a.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("store: unable to fetch wayback row: %v", err)
+		}
+		if n := len(waybacks); n == 0 || waybacks[n-1].ID != w.ID {
+			w.Archives = []entity.Archive{}
+			waybacks = append(waybacks, w)
+		}
+		if a.Slot != "" {
+			last := &waybacks[len(waybacks)-1]
+			last.Archives = append(last.Archives, a)
+		}
+	}
+
+	return waybacks, rows.Err()
+}
+
+// CountWaybacks returns the number of archiving requests matched by the filter,
+// the limit and offset of the filter are ignored.
+func (s *Storage) CountWaybacks(ctx context.Context, f WaybackFilter) (count int, err error) {
+	where, args := f.where(driverOf(s.ds))
+	query := `SELECT count(*) FROM wayback ` + where
+	if err = s.ds.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("store: unable to count waybacks: %v", err)
+	}
+	return count, nil
+}
+
+// domainOf returns the lowercase host of the source URL without port.
+func domainOf(source string) string {
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -93,6 +93,9 @@ const render = (collects) => {
 
     let link;
     try {
+      if (collect.error) {
+        throw new Error(collect.error);
+      }
       const url = new URL(collect.dst);
       link = document.createElement('a');
       link.href = url.href;
@@ -101,7 +104,7 @@ const render = (collects) => {
     } catch (_) {
       link = document.createElement('a');
       link.href = 'javascript:;';
-      link.textContent = collect.dst;
+      link.textContent = collect.error ? `${collect.status}: ${collect.error}` : collect.dst;
     }
 
     dst.appendChild(link);
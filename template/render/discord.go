@@ -27,7 +27,7 @@ func (d *Discord) ForReply() (r *Render) {
 	var tmplBytes bytes.Buffer
 
 	const tmpl = `{{range $ := .}}{{ $.Arc | name }}:
-• {{ $.Dst }}
+• {{ $.Result }}
 
 {{end}}`
 
@@ -65,7 +65,7 @@ func (d *Discord) ForPublish() (r *Render) {
 	}
 
 	const tmpl = `{{range $ := .}}{{ $.Arc | name }}:
-• {{ $.Dst }}
+• {{ $.Result }}
 
 {{end}}`
 
@@ -41,13 +41,13 @@ This domain is for use in illustrative examples in documents. You may use this d
 More information...
 
 Internet Archive:
-• Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
+• timeout: Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
 
 archive.today:
 • http://archive.today/abcdE
 
 IPFS:
-• Archive failed.
+• failed: Archive failed.
 
 Telegraph:
 • https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/`
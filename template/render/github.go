@@ -40,8 +40,8 @@ func (gh *GitHub) ForPublish() *Render {
 
 	const tmpl = `{{range $ := .}}**[{{ $.Arc | name }}]({{ $.Ext | extra }})**:
 > source: [{{ $.Src | unescape | revert }}]({{ $.Src | revert }})
-> archived: {{ if $.Dst | isURL }}[{{ $.Dst | unescape }}]({{ $.Dst | escapeString }})
-{{ else }}{{ $.Dst }}
+> archived: {{ if $.Succeeded }}[{{ $.Dst | unescape }}]({{ $.Dst | escapeString }})
+{{ else }}{{ $.Result }}
 {{ end }}
 {{ end }}`
 
@@ -14,28 +14,32 @@ import (
 func TestRenderGitHub(t *testing.T) {
 	collects := []wayback.Collect{
 		{
-			Arc: config.SLOT_IA,
-			Dst: "https://web.archive.org/web/20211000000001/https://example.com/?q=%E4%B8%AD%E6%96%87",
-			Src: "https://example.com/?q=%E4%B8%AD%E6%96%87",
-			Ext: config.SLOT_IA,
+			Arc:    config.SLOT_IA,
+			Dst:    "https://web.archive.org/web/20211000000001/https://example.com/?q=%E4%B8%AD%E6%96%87",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/?q=%E4%B8%AD%E6%96%87",
+			Ext:    config.SLOT_IA,
 		},
 		{
-			Arc: config.SLOT_IS,
-			Dst: "http://archive.today/abcdE",
-			Src: "https://example.com/?q=%E4%B8%AD%E6%96%87",
-			Ext: config.SLOT_IS,
+			Arc:    config.SLOT_IS,
+			Dst:    "http://archive.today/abcdE",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/?q=%E4%B8%AD%E6%96%87",
+			Ext:    config.SLOT_IS,
 		},
 		{
-			Arc: config.SLOT_IP,
-			Dst: "https://ipfs.io/ipfs/QmTbDmpvQ3cPZG6TA5tnar4ZG6q9JMBYVmX2n3wypMQMtr",
-			Src: "https://example.com/?q=%E4%B8%AD%E6%96%87",
-			Ext: config.SLOT_IP,
+			Arc:    config.SLOT_IP,
+			Dst:    "https://ipfs.io/ipfs/QmTbDmpvQ3cPZG6TA5tnar4ZG6q9JMBYVmX2n3wypMQMtr",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/?q=%E4%B8%AD%E6%96%87",
+			Ext:    config.SLOT_IP,
 		},
 		{
-			Arc: config.SLOT_PH,
-			Dst: "http://telegra.ph/title-01-01",
-			Src: "https://example.com/?q=%E4%B8%AD%E6%96%87",
-			Ext: config.SLOT_PH,
+			Arc:    config.SLOT_PH,
+			Dst:    "http://telegra.ph/title-01-01",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/?q=%E4%B8%AD%E6%96%87",
+			Ext:    config.SLOT_PH,
 		},
 	}
 
@@ -64,7 +68,7 @@ func TestRenderGitHub(t *testing.T) {
 func TestRenderGitHubFlawed(t *testing.T) {
 	expected := `**[Internet Archive](https://web.archive.org/)**:
 > source: [https://example.com/](https://example.com/)
-> archived: Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
+> archived: timeout: Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
 
 **[archive.today](https://archive.today/)**:
 > source: [https://example.com/](https://example.com/)
@@ -72,7 +76,7 @@ func TestRenderGitHubFlawed(t *testing.T) {
 
 **[IPFS](https://ipfs.github.io/public-gateway-checker/)**:
 > source: [https://example.com/](https://example.com/)
-> archived: Archive failed.
+> archived: failed: Archive failed.
 
 **[Telegraph](https://telegra.ph/)**:
 > source: [https://example.com/](https://example.com/)
@@ -41,7 +41,7 @@ func (m *Mastodon) ForPublish() *Render {
 
 	const tmpl = `{{range $ := .}}
 • {{ $.Arc | name }}
-> {{ $.Dst }}
+> {{ $.Result }}
 {{end}}`
 
 	tpl, err := template.New("mastodon").Funcs(funcMap()).Parse(tmpl)
@@ -44,7 +44,7 @@ func (n *Nostr) ForPublish() *Render {
 
 	const tmpl = `{{range $ := .}}
 • {{ $.Arc | name }}
-> {{ $.Dst }}
+> {{ $.Result }}
 {{end}}`
 
 	tpl, err := template.New("nostr").Funcs(funcMap()).Parse(tmpl)
@@ -51,7 +51,7 @@ func (i *Relaychat) ForPublish() *Render {
 func (i *Relaychat) main() *bytes.Buffer {
 	tmplBytes := new(bytes.Buffer)
 
-	const tmpl = "{{range $ := .}}• {{ $.Arc | name }}:\n> {{ $.Dst }}\n{{end}}"
+	const tmpl = "{{range $ := .}}• {{ $.Arc | name }}:\n> {{ $.Result }}\n{{end}}"
 
 	tpl, err := template.New("relaychat").Funcs(funcMap()).Parse(tmpl)
 	if err != nil {
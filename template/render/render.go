@@ -111,7 +111,7 @@ type Collects map[string]Collect
 func groupBySlot(cols []wayback.Collect) *Collects {
 	m := make(map[string][]map[string]string)
 	for _, col := range cols {
-		m[col.Arc] = append(m[col.Arc], map[string]string{col.Src: col.Dst})
+		m[col.Arc] = append(m[col.Arc], map[string]string{col.Src: col.Result()})
 	}
 	c := make(Collects)
 	for _, col := range cols {
@@ -5,6 +5,8 @@
 package render // import "github.com/wabarc/wayback/template/render"
 
 import (
+	"errors"
+
 	"github.com/wabarc/wayback"
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/reduxer"
@@ -13,82 +15,94 @@ import (
 var (
 	collects = []wayback.Collect{
 		{
-			Arc: config.SLOT_IA,
-			Dst: "https://web.archive.org/web/20211000000001/https://example.com/",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IA,
+			Arc:    config.SLOT_IA,
+			Dst:    "https://web.archive.org/web/20211000000001/https://example.com/",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IA,
 		},
 		{
-			Arc: config.SLOT_IS,
-			Dst: "http://archive.today/abcdE",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IS,
+			Arc:    config.SLOT_IS,
+			Dst:    "http://archive.today/abcdE",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IS,
 		},
 		{
-			Arc: config.SLOT_IP,
-			Dst: "https://ipfs.io/ipfs/QmTbDmpvQ3cPZG6TA5tnar4ZG6q9JMBYVmX2n3wypMQMtr",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IP,
+			Arc:    config.SLOT_IP,
+			Dst:    "https://ipfs.io/ipfs/QmTbDmpvQ3cPZG6TA5tnar4ZG6q9JMBYVmX2n3wypMQMtr",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IP,
 		},
 		{
-			Arc: config.SLOT_PH,
-			Dst: "http://telegra.ph/title-01-01",
-			Src: "https://example.com/",
-			Ext: config.SLOT_PH,
+			Arc:    config.SLOT_PH,
+			Dst:    "http://telegra.ph/title-01-01",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_PH,
 		},
 	}
 
 	flawed = []wayback.Collect{
 		{
-			Arc: config.SLOT_IA,
-			Dst: `Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`,
-			Src: "https://example.com/",
-			Ext: config.SLOT_IA,
+			Arc:    config.SLOT_IA,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IA,
+			Err:    errors.New(`Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`),
+			Status: wayback.StatusTimeout,
 		},
 		{
-			Arc: config.SLOT_IS,
-			Dst: "http://archive.today/abcdE",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IS,
+			Arc:    config.SLOT_IS,
+			Dst:    "http://archive.today/abcdE",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IS,
 		},
 		{
-			Arc: config.SLOT_IP,
-			Dst: "Archive failed.",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IP,
+			Arc:    config.SLOT_IP,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IP,
+			Err:    errors.New("Archive failed."),
+			Status: wayback.StatusFailed,
 		},
 		{
-			Arc: config.SLOT_PH,
-			Dst: "https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/",
-			Src: "https://example.com/",
-			Ext: config.SLOT_PH,
+			Arc:    config.SLOT_PH,
+			Dst:    "https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_PH,
 		},
 	}
 
 	multi = []wayback.Collect{
 		{
-			Arc: config.SLOT_IA,
-			Dst: `https://web.archive.org/123/https://example.com/`,
-			Src: "https://example.com/",
-			Ext: config.SLOT_IA,
+			Arc:    config.SLOT_IA,
+			Dst:    `https://web.archive.org/123/https://example.com/`,
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IA,
 		},
 		{
-			Arc: config.SLOT_IS,
-			Dst: "http://archive.today/abcdE",
-			Src: "https://example.com/",
-			Ext: config.SLOT_IS,
+			Arc:    config.SLOT_IS,
+			Dst:    "http://archive.today/abcdE",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.com/",
+			Ext:    config.SLOT_IS,
 		},
 		{
-			Arc: config.SLOT_IA,
-			Dst: `https://web.archive.org/123/https://example.org/`,
-			Src: "https://example.org/",
-			Ext: config.SLOT_IA,
+			Arc:    config.SLOT_IA,
+			Dst:    `https://web.archive.org/123/https://example.org/`,
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.org/",
+			Ext:    config.SLOT_IA,
 		},
 		{
-			Arc: config.SLOT_IS,
-			Dst: "http://archive.today/abc",
-			Src: "https://example.org/",
-			Ext: config.SLOT_IS,
+			Arc:    config.SLOT_IS,
+			Dst:    "http://archive.today/abc",
+			Status: wayback.StatusSuccess,
+			Src:    "https://example.org/",
+			Ext:    config.SLOT_IS,
 		},
 	}
 
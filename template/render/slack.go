@@ -27,7 +27,7 @@ func (s *Slack) ForReply() (r *Render) {
 	var tmplBytes bytes.Buffer
 
 	const tmpl = `{{range $ := .}}{{ $.Arc | name }}:
-• {{ $.Dst }}
+• {{ $.Result }}
 
 {{end}}`
 
@@ -67,7 +67,7 @@ func (s *Slack) ForPublish() (r *Render) {
 	}
 
 	const tmpl = `{{range $ := .}}{{ $.Arc | name }}:
-• {{ $.Dst }}
+• {{ $.Result }}
 
 {{end}}`
 
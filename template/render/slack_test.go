@@ -38,13 +38,13 @@ Telegraph:
 
 func TestRenderSlackFlawed(t *testing.T) {
 	message := `Internet Archive:
-• Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
+• timeout: Get "https://web.archive.org/save/https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)
 
 archive.today:
 • http://archive.today/abcdE
 
 IPFS:
-• Archive failed.
+• failed: Archive failed.
 
 Telegraph:
 • https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/`
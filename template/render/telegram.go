@@ -76,7 +76,7 @@ func (t *Telegram) ForPublish() (r *Render) {
 
 	tmpl := `{{range $ := .}}
 <b><a href="{{ $.Ext | extra }}">{{ $.Arc | name }}</a></b>:
-• <a href="{{ $.Src | revert }}">source</a> - {{ if $.Dst | isURL }}<a href="{{ $.Dst }}">{{ $.Dst }}</a>{{ else }}{{ $.Dst | escapeString }}{{ end }}
+• <a href="{{ $.Src | revert }}">source</a> - {{ if $.Succeeded }}<a href="{{ $.Dst }}">{{ $.Dst }}</a>{{ else }}{{ $.Result | escapeString }}{{ end }}
 {{ end }}`
 
 	tpl, err := template.New("message").Funcs(funcMap()).Parse(tmpl)
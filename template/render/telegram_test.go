@@ -54,13 +54,13 @@ func TestRenderTelegramForPublishWithArtifact(t *testing.T) {
 
 func TestRenderTelegramFlawed(t *testing.T) {
 	message := `<b><a href="https://web.archive.org/">Internet Archive</a></b>:
-• <a href="https://example.com/">source</a> - Get &#34;https://web.archive.org/save/https://example.com&#34;: context deadline exceeded (Client.Timeout exceeded while awaiting headers)
+• <a href="https://example.com/">source</a> - timeout: Get &#34;https://web.archive.org/save/https://example.com&#34;: context deadline exceeded (Client.Timeout exceeded while awaiting headers)
 
 <b><a href="https://archive.today/">archive.today</a></b>:
 • <a href="https://example.com/">source</a> - <a href="http://archive.today/abcdE">http://archive.today/abcdE</a>
 
 <b><a href="https://ipfs.github.io/public-gateway-checker/">IPFS</a></b>:
-• <a href="https://example.com/">source</a> - Archive failed.
+• <a href="https://example.com/">source</a> - failed: Archive failed.
 
 <b><a href="https://telegra.ph/">Telegraph</a></b>:
 • <a href="https://example.com/">source</a> - <a href="https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/">https://web.archive.org/*/https://webcache.googleusercontent.com/search?q=cache:https://example.com/</a>
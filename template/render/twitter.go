@@ -70,7 +70,7 @@ func (t *Twitter) ForPublish() *Render {
 
 	const tmpl = `{{range $ := .}}{{ if not $.Arc "ph" }}
 • {{ $.Arc | name }}
-> {{ $.Dst }}
+> {{ $.Result }}
 {{end}}{{end}}`
 
 	tpl, err := template.New("twitter").Funcs(funcMap()).Parse(tmpl)
@@ -37,6 +37,11 @@ type Collect struct {
 	Slot string `json:"slot"`
 	Src  string `json:"src"`
 	Dst  string `json:"dst"`
+
+	Status   string `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Elapsed  int64  `json:"elapsed,omitempty"` // Elapsed time in milliseconds
+	Attempts int    `json:"attempts,omitempty"`
 }
 
 // Collector represents a group of Collect.
@@ -18,7 +18,9 @@ import (
 	"github.com/wabarc/wayback/config"
 	"github.com/wabarc/wayback/errors"
 	"github.com/wabarc/wayback/ingress"
+	"github.com/wabarc/wayback/metrics"
 	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/replay"
 	"golang.org/x/sync/errgroup"
 
 	is "github.com/wabarc/archive.is"
@@ -30,14 +32,75 @@ import (
 	pinner "github.com/wabarc/ipfs-pinner"
 )
 
+// Status represents the result status of an archive slot.
+type Status uint8
+
+const (
+	StatusUnknown Status = iota // StatusUnknown represents the status is not reported
+	StatusSuccess               // StatusSuccess represents the slot archived successfully
+	StatusFailed                // StatusFailed represents the slot failed to archive
+	StatusTimeout               // StatusTimeout represents the slot exceeded its deadline
+	StatusSkipped               // StatusSkipped represents the slot has been skipped
+)
+
+// String returns the status as a string.
+func (s Status) String() string {
+	switch s {
+	case StatusSuccess:
+		return "success"
+	case StatusFailed:
+		return "failed"
+	case StatusTimeout:
+		return "timeout"
+	case StatusSkipped:
+		return "skipped"
+	default:
+		return config.UNKNOWN
+	}
+}
+
+// ParseStatus returns the Status of the string returned by String,
+// it returns StatusFailed for unknown strings.
+func ParseStatus(s string) Status {
+	for _, status := range []Status{StatusSuccess, StatusFailed, StatusTimeout, StatusSkipped} {
+		if status.String() == s {
+			return status
+		}
+	}
+	return StatusFailed
+}
+
 // Collect results that archived, Arc is name of the archive service,
 // Dst mapping the original URL and archived destination URL,
 // Ext is extra descriptions.
 type Collect struct {
 	Arc string // Archive slot name, see config/config.go
-	Dst string // Archived destination URL
+	Dst string // Archived destination URL, empty if archiving failed
 	Src string // Source URL
 	Ext string // Extra identifier
+
+	Err      error         // Error of archiving, nil if succeeded
+	Status   Status        // Result status of the slot
+	Elapsed  time.Duration // Time spent on archiving
+	Attempts int           // Number of attempts to archive
+	Cached   bool          // Whether it is reused from a recent capture
+}
+
+// Succeeded reports whether the slot archived successfully.
+func (c Collect) Succeeded() bool {
+	return c.Status == StatusSuccess && c.Err == nil
+}
+
+// Result returns the archived destination URL if it succeeded,
+// otherwise returns the descriptions of the failure.
+func (c Collect) Result() string {
+	if c.Succeeded() {
+		return c.Dst
+	}
+	if c.Err != nil {
+		return fmt.Sprintf("%s: %v", c.Status, c.Err)
+	}
+	return c.Status.String()
 }
 
 // IA represents the Internet Archive slot.
@@ -75,55 +138,62 @@ type GA struct {
 	URL *url.URL
 }
 
+// LC represents the local capture slot, it is backed by the artifacts of reduxer.
+type LC struct {
+	ctx context.Context
+	cfg *config.Options
+	URL *url.URL
+}
+
 // Waybacker is the interface that wraps the basic Wayback method.
 //
 // Wayback wayback *url.URL from struct of the implementations to the Wayback Machine.
-// It returns the result of string from the upstream services.
+// It returns the archived URL from the upstream services, or an error if failed.
 type Waybacker interface {
-	Wayback(reduxer.Reduxer) string
+	Wayback(reduxer.Reduxer) (string, error)
 }
 
 // Wayback implements the standard Waybacker interface:
 // it reads URL from the IA and returns archived URL as a string.
-func (i IA) Wayback(_ reduxer.Reduxer) string {
+func (i IA) Wayback(_ reduxer.Reduxer) (string, error) {
 	arc := &ia.Archiver{Client: ingress.Client()}
 	dst, err := arc.Wayback(i.ctx, i.URL)
 	if err != nil {
 		logger.Error("wayback %s to Internet Archive failed: %v", i.URL.String(), err)
-		return fmt.Sprint(err)
+		return "", err
 	}
-	return dst
+	return dst, nil
 }
 
 // Wayback implements the standard Waybacker interface:
 // it reads URL from the IS and returns archived URL as a string.
-func (i IS) Wayback(_ reduxer.Reduxer) string {
+func (i IS) Wayback(_ reduxer.Reduxer) (string, error) {
 	arc := is.NewArchiver(ingress.Client())
 	defer arc.CloseTor()
 
 	dst, err := arc.Wayback(i.ctx, i.URL)
 	if err != nil {
 		logger.Error("wayback %s to archive.today failed: %v", i.URL.String(), err)
-		return fmt.Sprint(err)
+		return "", err
 	}
-	return dst
+	return dst, nil
 }
 
 // Wayback implements the standard Waybacker interface:
 // it reads URL from the Ghostarchive and returns archived URL as a string.
-func (g GA) Wayback(_ reduxer.Reduxer) string {
+func (g GA) Wayback(_ reduxer.Reduxer) (string, error) {
 	arc := &ga.Archiver{Client: ingress.Client()}
 	dst, err := arc.Wayback(g.ctx, g.URL)
 	if err != nil {
 		logger.Error("wayback %s to Ghostarchive failed: %v", g.URL.String(), err)
-		return fmt.Sprint(err)
+		return "", err
 	}
-	return dst
+	return dst, nil
 }
 
 // Wayback implements the standard Waybacker interface:
 // it reads URL from the IP and returns archived URL as a string.
-func (i IP) Wayback(rdx reduxer.Reduxer) string {
+func (i IP) Wayback(rdx reduxer.Reduxer) (string, error) {
 	opts := []ipfs.PinningOption{
 		ipfs.Mode(ipfs.Remote),
 	}
@@ -159,14 +229,14 @@ func (i IP) Wayback(rdx reduxer.Reduxer) string {
 	dst, err := arc.Wayback(ctx, i.URL)
 	if err != nil {
 		logger.Error("wayback %s to IPFS failed: %v", i.URL.String(), err)
-		return fmt.Sprint(err)
+		return "", err
 	}
-	return dst
+	return dst, nil
 }
 
 // Wayback implements the standard Waybacker interface:
 // it reads URL from the PH and returns archived URL as a string.
-func (i PH) Wayback(rdx reduxer.Reduxer) string {
+func (i PH) Wayback(rdx reduxer.Reduxer) (string, error) {
 	arc := ph.New(ingress.Client())
 	uri := i.URL.String()
 	ctx := i.ctx
@@ -182,13 +252,186 @@ func (i PH) Wayback(rdx reduxer.Reduxer) string {
 	dst, err := arc.Wayback(ctx, i.URL)
 	if err != nil {
 		logger.Error("wayback %s to telegra.ph failed: %v", i.URL.String(), err)
-		return fmt.Sprint(err)
+		return "", err
+	}
+	return dst, nil
+}
+
+// Wayback implements the standard Waybacker interface:
+// it reads URL from the LC and returns the URL replaying the WARC file
+// produced by reduxer, the WARC file itself is kept as an artifact
+// served by the httpd service.
+func (l LC) Wayback(rdx reduxer.Reduxer) (string, error) {
+	if !l.cfg.EnabledReduxer() {
+		return "", errors.New("local capture requires WAYBACK_STORAGE_DIR")
+	}
+
+	bundle, ok := rdx.Load(reduxer.Src(l.URL.String()))
+	if !ok {
+		return "", errors.New("no artifacts captured for " + l.URL.String())
+	}
+	warc := bundle.Artifact().WARC.Local
+	if warc == "" {
+		return "", errors.New("no WARC captured for " + l.URL.String())
+	}
+
+	return replayURL(l.cfg, l.URL, warc)
+}
+
+// replayURL indexes the WARC file captured the URL, and returns the URL
+// replaying the capture at the time it was recorded.
+func replayURL(cfg *config.Options, u *url.URL, warc string) (string, error) {
+	captures, err := replay.IndexOf(cfg.StorageDir()).Add(warc)
+	if err != nil {
+		return "", errors.Wrap(err, "index WARC "+warc+" failed")
+	}
+	if len(captures) == 0 {
+		return "", errors.New("no response recorded in WARC " + warc)
 	}
-	return dst
+
+	// The first response is the capture of the URL if it was redirected.
+	capture := captures[0]
+	key, _ := replay.Key(u.String())
+	for _, c := range captures {
+		if c.URLKey == key {
+			capture = c
+			break
+		}
+	}
+
+	return cfg.PublicURL() + config.RP_SLUG + "/" + capture.Timestamp + "/" + capture.Original, nil
 }
 
-func wayback(w Waybacker, r reduxer.Reduxer) string {
-	return w.Wayback(r)
+func init() {
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_IA},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return IA{URL: u, cfg: cfg, ctx: ctx}
+		},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.IA{URL: u})
+		},
+	})
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_IS},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return IS{URL: u, cfg: cfg, ctx: ctx}
+		},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.IS{URL: u})
+		},
+	})
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_IP},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return IP{URL: u, cfg: cfg, ctx: ctx}
+		},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.IP{URL: u})
+		},
+	})
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_PH},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return PH{URL: u, cfg: cfg, ctx: ctx}
+		},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.PH{URL: u})
+		},
+	})
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_GA},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return GA{URL: u, cfg: cfg, ctx: ctx}
+		},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.GA{URL: u})
+		},
+	})
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_LC},
+		Setup: func(ctx context.Context, cfg *config.Options, u *url.URL) Waybacker {
+			return LC{URL: u, cfg: cfg, ctx: ctx}
+		},
+	})
+	// Time Travel is a playback only slot.
+	Register(&Module{
+		Slot: config.Slot{Name: config.SLOT_TT},
+		Playback: func(ctx context.Context, u *url.URL) string {
+			return playback.Playback(ctx, playback.TT{URL: u})
+		},
+	})
+}
+
+func wayback(ctx context.Context, w Waybacker, r reduxer.Reduxer) (col Collect) {
+	start := time.Now()
+	col.Dst, col.Err = w.Wayback(r)
+	col.Elapsed = time.Since(start)
+	col.Attempts = 1
+
+	switch {
+	case col.Err == nil:
+		col.Status = StatusSuccess
+	case errors.Is(col.Err, context.DeadlineExceeded), errors.Is(ctx.Err(), context.DeadlineExceeded):
+		col.Status = StatusTimeout
+		col.Dst = ""
+	default:
+		col.Status = StatusFailed
+		col.Dst = ""
+	}
+
+	return col
+}
+
+// archive archives the input URL to the slot of the given module, it retries
+// with backoff according to the policy of the slot and reports the result to
+// the circuit breaker of the slot.
+func archive(ctx context.Context, cfg *config.Options, mod *Module, input *url.URL, rdx reduxer.Reduxer) (col Collect) {
+	slot := mod.Slot.Name
+	policy := cfg.SlotPolicy(slot)
+	brk := breakerOf(slot)
+	if !brk.allow(policy) {
+		logger.Warn("skipped %s: circuit breaker is open", config.SlotName(slot))
+		metrics.IncrementSlot(slot, StatusSkipped.String())
+		return Collect{Err: errors.New("circuit breaker is open"), Status: StatusSkipped}
+	}
+
+	start := time.Now()
+	attempts := 0
+	for attempts <= policy.MaxRetries {
+		if attempts > 0 {
+			delay := policy.Backoff << (attempts - 1)
+			logger.Debug("retry slot %s in %s, attempts: %d", slot, delay, attempts)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		attempts++
+
+		actx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.Timeout > 0 {
+			actx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		}
+		col = wayback(actx, mod.Setup(actx, cfg, input), rdx)
+		cancel()
+
+		if col.Succeeded() || ctx.Err() != nil {
+			break
+		}
+	}
+	col.Attempts = attempts
+	col.Elapsed = time.Since(start)
+
+	brk.done(policy, col.Succeeded())
+	metrics.IncrementSlot(slot, col.Status.String())
+
+	return col
 }
 
 // Wayback returns URLs archived to the time capsules of given URLs.
@@ -212,24 +455,26 @@ func Wayback(ctx context.Context, rdx reduxer.Reduxer, cfg *config.Options, urls
 				logger.Warn("skipped %s", config.SlotName(slot))
 				continue
 			}
+			mod, err := loadModule(slot)
+			if err != nil || mod.Setup == nil {
+				logger.Warn("skipped %s: no archiver registered", config.SlotName(slot))
+				mu.Lock()
+				cols = append(cols, Collect{
+					Arc:    slot,
+					Src:    input.String(),
+					Ext:    slot,
+					Err:    errors.New("no archiver registered"),
+					Status: StatusSkipped,
+				})
+				mu.Unlock()
+				continue
+			}
 			slot, input := slot, input
 			g.Go(func() error {
 				logger.Debug("archiving slot: %s", slot)
 
 				uri := input.String()
-				var col Collect
-				switch slot {
-				case config.SLOT_IA:
-					col.Dst = wayback(IA{URL: input, cfg: cfg, ctx: ctx}, rdx)
-				case config.SLOT_IS:
-					col.Dst = wayback(IS{URL: input, cfg: cfg, ctx: ctx}, rdx)
-				case config.SLOT_IP:
-					col.Dst = wayback(IP{URL: input, cfg: cfg, ctx: ctx}, rdx)
-				case config.SLOT_PH:
-					col.Dst = wayback(PH{URL: input, cfg: cfg, ctx: ctx}, rdx)
-				case config.SLOT_GA:
-					col.Dst = wayback(GA{URL: input, cfg: cfg, ctx: ctx}, rdx)
-				}
+				col := archive(ctx, cfg, mod, input, rdx)
 				col.Src = uri
 				col.Arc = slot
 				col.Ext = slot
@@ -265,33 +510,16 @@ func Playback(ctx context.Context, cfg *config.Options, urls ...*url.URL) (cols
 
 	mu := sync.Mutex{}
 	g, ctx := errgroup.WithContext(ctx)
-	var slots = []string{
-		config.SLOT_IA,
-		config.SLOT_IS,
-		config.SLOT_IP,
-		config.SLOT_PH,
-		config.SLOT_GA,
-		config.SLOT_TT,
-	}
 	for _, input := range urls {
-		for _, slot := range slots {
-			slot, input := slot, input
+		for _, mod := range playbackModules() {
+			slot, input, mod := mod.Slot.Name, input, mod
 			g.Go(func() error {
 				logger.Debug("searching slot: %s", slot)
 				var col Collect
-				switch slot {
-				case config.SLOT_IA:
-					col.Dst = playback.Playback(ctx, playback.IA{URL: input})
-				case config.SLOT_IS:
-					col.Dst = playback.Playback(ctx, playback.IS{URL: input})
-				case config.SLOT_IP:
-					col.Dst = playback.Playback(ctx, playback.IP{URL: input})
-				case config.SLOT_PH:
-					col.Dst = playback.Playback(ctx, playback.PH{URL: input})
-				case config.SLOT_GA:
-					col.Dst = playback.Playback(ctx, playback.GA{URL: input})
-				case config.SLOT_TT:
-					col.Dst = playback.Playback(ctx, playback.TT{URL: input})
+				col.Dst = mod.Playback(ctx, input)
+				col.Status, col.Err = playbackStatus(ctx, col.Dst)
+				if col.Err != nil {
+					col.Dst = ""
 				}
 				col.Src = input.String()
 				col.Arc = slot
@@ -314,6 +542,21 @@ func Playback(ctx context.Context, cfg *config.Options, urls ...*url.URL) (cols
 	return cols, nil
 }
 
+// playbackStatus returns the status of the result of a playback module, the
+// modules return the archived URL if found, or the descriptions of errors.
+func playbackStatus(ctx context.Context, dst string) (Status, error) {
+	if u, err := url.Parse(dst); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return StatusSuccess, nil
+	}
+	if dst == "" {
+		dst = "not found"
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return StatusTimeout, errors.New(dst)
+	}
+	return StatusFailed, errors.New(dst)
+}
+
 // duration reduce the context deadline time for downstream and reserve
 // extra time for the caller.
 func duration(ctx context.Context) time.Duration {
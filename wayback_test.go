@@ -0,0 +1,215 @@
+// Copyright 2025 Wayback Archiver. All rights reserved.
+// Use of this source code is governed by the GNU GPL v3
+// license that can be found in the LICENSE file.
+
+package wayback // import "github.com/wabarc/wayback"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wabarc/wayback/config"
+	"github.com/wabarc/wayback/reduxer"
+	"github.com/wabarc/wayback/replay"
+)
+
+type failedArchiver struct {
+	err error
+}
+
+func (f failedArchiver) Wayback(_ reduxer.Reduxer) (string, error) {
+	return "", f.err
+}
+
+func TestWaybackCollect(t *testing.T) {
+	u, _ := url.Parse("https://example.com/")
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		arc    Waybacker
+		status Status
+		result string
+	}{
+		{
+			name:   "success",
+			ctx:    context.Background(),
+			arc:    fakeArchiver{URL: u},
+			status: StatusSuccess,
+			result: "https://example.org/archived/example.com",
+		},
+		{
+			name:   "failed",
+			ctx:    context.Background(),
+			arc:    failedArchiver{err: errors.New("bad gateway")},
+			status: StatusFailed,
+			result: "failed: bad gateway",
+		},
+		{
+			name:   "timeout",
+			ctx:    expired,
+			arc:    failedArchiver{err: errors.New("request canceled")},
+			status: StatusTimeout,
+			result: "timeout: request canceled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			col := wayback(test.ctx, test.arc, reduxer.BundleExample())
+			if col.Status != test.status {
+				t.Errorf(`Unexpected status, got %s instead of %s`, col.Status, test.status)
+			}
+			if col.Attempts != 1 {
+				t.Errorf(`Unexpected attempts, got %d instead of 1`, col.Attempts)
+			}
+			if got := col.Result(); got != test.result {
+				t.Errorf(`Unexpected result, got %s instead of %s`, got, test.result)
+			}
+			if col.Succeeded() != (test.status == StatusSuccess) {
+				t.Errorf(`Unexpected succeeded, got %t`, col.Succeeded())
+			}
+			if got := ParseStatus(col.Status.String()); got != col.Status {
+				t.Errorf(`Unexpected parsed status, got %s instead of %s`, got, col.Status)
+			}
+		})
+	}
+}
+
+func TestPlaybackStatus(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		dst    string
+		status Status
+		result string
+	}{
+		{"found", context.Background(), "https://web.archive.org/web/https://example.com/", StatusSuccess, ""},
+		{"not found", context.Background(), "Not found", StatusFailed, "failed: Not found"},
+		{"empty", context.Background(), "", StatusFailed, "failed: not found"},
+		{"timeout", expired, "context deadline exceeded", StatusTimeout, "timeout: context deadline exceeded"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, err := playbackStatus(test.ctx, test.dst)
+			if status != test.status {
+				t.Errorf(`Unexpected status, got %s instead of %s`, status, test.status)
+			}
+			col := Collect{Status: status, Err: err}
+			if test.result != "" && col.Result() != test.result {
+				t.Errorf(`Unexpected result, got %s instead of %s`, col.Result(), test.result)
+			}
+		})
+	}
+
+	if (Collect{Dst: "https://example.org/"}).Succeeded() {
+		t.Error("Unexpected succeeded collect without status")
+	}
+}
+
+type flakyArchiver struct {
+	calls *int
+	fails int
+}
+
+func (f flakyArchiver) Wayback(_ reduxer.Reduxer) (string, error) {
+	*f.calls++
+	if *f.calls <= f.fails {
+		return "", errors.New("service unavailable")
+	}
+	return "https://example.org/archived", nil
+}
+
+func TestArchiveWithPolicy(t *testing.T) {
+	const slot = "flaky"
+	t.Setenv("WAYBACK_FLAKY_MAX_RETRIES", "2")
+	t.Setenv("WAYBACK_FLAKY_BACKOFF", "0")
+	t.Setenv("WAYBACK_FLAKY_BREAKER_THRESHOLD", "1")
+	t.Setenv("WAYBACK_FLAKY_BREAKER_COOLDOWN", "60")
+
+	calls := 0
+	fails := 0
+	mod := &Module{
+		Slot: config.Slot{Name: slot, Title: "Flaky Archive"},
+		Setup: func(_ context.Context, _ *config.Options, _ *url.URL) Waybacker {
+			return flakyArchiver{calls: &calls, fails: fails}
+		},
+	}
+	registerTest(t, mod)
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+	u, _ := url.Parse("https://example.com/")
+
+	fails = 2
+	col := archive(context.Background(), opts, mod, u, reduxer.BundleExample())
+	if !col.Succeeded() || col.Attempts != 3 {
+		t.Fatalf(`Unexpected archive result, got %s with %d attempts`, col.Result(), col.Attempts)
+	}
+
+	calls, fails = 0, 3
+	col = archive(context.Background(), opts, mod, u, reduxer.BundleExample())
+	if col.Status != StatusFailed || col.Attempts != 3 {
+		t.Fatalf(`Unexpected archive result, got %s with %d attempts`, col.Result(), col.Attempts)
+	}
+
+	col = archive(context.Background(), opts, mod, u, reduxer.BundleExample())
+	if col.Status != StatusSkipped {
+		t.Fatalf(`Unexpected archive status, got %s instead of %s`, col.Status, StatusSkipped)
+	}
+}
+
+func TestLocalCapture(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WAYBACK_STORAGE_DIR", dir)
+	t.Setenv("WAYBACK_PUBLIC_URL", "https://wayback.example.org/")
+
+	opts, err := config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing environment variables failed: %v`, err)
+	}
+
+	block := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>example</p>"
+	record := fmt.Sprintf("WARC/1.0\r\nWARC-Type: response\r\nWARC-Record-ID: <urn:uuid:1>\r\n"+
+		"WARC-Target-URI: https://example.com/\r\nWARC-Date: 2025-01-02T03:04:05Z\r\n"+
+		"Content-Type: application/http;msgtype=response\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", len(block), block)
+	warc := filepath.Join(dir, "example.warc")
+	if err := os.WriteFile(warc, []byte(record), 0o600); err != nil {
+		t.Fatalf(`Unexpected write file: %v`, err)
+	}
+
+	u, _ := url.Parse("https://example.com/")
+	dst, err := replayURL(opts, u, warc)
+	if err != nil {
+		t.Fatalf(`Unexpected local capture: %v`, err)
+	}
+	if expected := "https://wayback.example.org" + config.RP_SLUG + "/20250102030405/https://example.com/"; dst != expected {
+		t.Errorf(`Unexpected local capture URL, got %s instead of %s`, dst, expected)
+	}
+	if n := len(replay.IndexOf(dir).Lookup(u.String())); n != 1 {
+		t.Errorf(`Unexpected captures number of replay index, got %d instead of 1`, n)
+	}
+
+	// The WARC file of the example bundle does not exist.
+	if _, err = (LC{URL: u, cfg: opts, ctx: context.Background()}).Wayback(reduxer.BundleExample()); err == nil {
+		t.Errorf(`Unexpected local capture without WARC file`)
+	}
+	u, _ = url.Parse("https://example.org/")
+	if _, err = (LC{URL: u, cfg: opts, ctx: context.Background()}).Wayback(reduxer.BundleExample()); err == nil {
+		t.Errorf(`Unexpected local capture without artifacts`)
+	}
+}